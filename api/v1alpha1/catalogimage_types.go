@@ -32,6 +32,11 @@ type CatalogImageSpec struct {
 	// +optional
 	Digest string `json:"digest,omitempty"`
 
+	// PinDigest sets Digest to the first digest the tag resolves to, so consumers keep receiving the same
+	// image when the tag is moved
+	// +optional
+	PinDigest bool `json:"pinDigest,omitempty"`
+
 	// Tags are mutable labels for categorization
 	// +optional
 	Tags []string `json:"tags,omitempty"`
@@ -49,6 +54,64 @@ type CatalogImageSpec struct {
 	// Metadata contains automotive-specific image metadata
 	// +optional
 	Metadata *CatalogImageMetadata `json:"metadata,omitempty"`
+
+	// Channel is the promotion channel this image belongs to
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// Lifecycle marks the image as deprecated or end-of-life
+	// +optional
+	Lifecycle *CatalogImageLifecycle `json:"lifecycle,omitempty"`
+
+	// Mirror copies the image and its referrers into a local registry
+	// +optional
+	Mirror *CatalogImageMirror `json:"mirror,omitempty"`
+}
+
+// CatalogImageMirror configures mirroring of a catalog image into a local registry
+type CatalogImageMirror struct {
+	// Disabled opts the image out of the cluster-wide mirror policy of the OperatorConfig
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// RepositoryURL is the registry repository the image is mirrored to, without tag.
+	// Defaults to the image's repository name under the OperatorConfig mirror repository.
+	// +optional
+	RepositoryURL string `json:"repositoryUrl,omitempty"`
+
+	// AuthSecretRef references the credentials used to push to the mirror repository
+	// +optional
+	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
+}
+
+// LifecycleState is the support state of a catalog image
+// +kubebuilder:validation:Enum=Active;Deprecated;EOL
+type LifecycleState string
+
+const (
+	// LifecycleActive indicates the image is supported
+	LifecycleActive LifecycleState = "Active"
+	// LifecycleDeprecated indicates the image is superseded but still verified and usable
+	LifecycleDeprecated LifecycleState = "Deprecated"
+	// LifecycleEOL indicates the image is no longer supported and is no longer verified
+	LifecycleEOL LifecycleState = "EOL"
+)
+
+// CatalogImageLifecycle describes whether a catalog image is superseded
+type CatalogImageLifecycle struct {
+	// State is the lifecycle state of the image
+	// +kubebuilder:default=Active
+	// +optional
+	State LifecycleState `json:"state,omitempty"`
+
+	// Reason explains why the image is deprecated or end-of-life
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// ReplacedBy is the name of the CatalogImage that supersedes this one
+	// +optional
+	ReplacedBy string `json:"replacedBy,omitempty"`
 }
 
 // AuthSecretReference references a secret containing registry credentials
@@ -179,6 +242,88 @@ type CatalogImageStatus struct {
 	// ArtifactRefs contains references to downloadable artifacts
 	// +optional
 	ArtifactRefs []ArtifactReference `json:"artifactRefs,omitempty"`
+
+	// PromotionHistory records the promotions that led to this image, oldest first
+	// +optional
+	PromotionHistory []PromotionRecord `json:"promotionHistory,omitempty"`
+
+	// PendingPromotions are promotions of this image into protected channels awaiting approval
+	// +optional
+	PendingPromotions []PendingPromotion `json:"pendingPromotions,omitempty"`
+
+	// LifecycleState is the lifecycle state last observed by the controller
+	// +optional
+	LifecycleState LifecycleState `json:"lifecycleState,omitempty"`
+
+	// DigestHistory records the digests the registry URL resolved to, oldest first
+	// +optional
+	DigestHistory []DigestRecord `json:"digestHistory,omitempty"`
+}
+
+// DigestRecord records a digest a catalog image's tag resolved to
+type DigestRecord struct {
+	// Digest is the resolved manifest digest
+	// +kubebuilder:validation:Required
+	Digest string `json:"digest"`
+
+	// FirstSeen is when the tag was first verified to resolve to this digest
+	// +kubebuilder:validation:Required
+	FirstSeen metav1.Time `json:"firstSeen"`
+}
+
+// PromotionRecord records the promotion of a catalog image into a channel
+type PromotionRecord struct {
+	// SourceImage is the name of the CatalogImage that was promoted
+	// +kubebuilder:validation:Required
+	SourceImage string `json:"sourceImage"`
+
+	// SourceDigest is the digest of the promoted image
+	// +optional
+	SourceDigest string `json:"sourceDigest,omitempty"`
+
+	// FromChannel is the channel of the source image, empty if it was not in a channel
+	// +optional
+	FromChannel string `json:"fromChannel,omitempty"`
+
+	// Channel is the channel the image was promoted into
+	// +kubebuilder:validation:Required
+	Channel string `json:"channel"`
+
+	// PromotedBy is the user who requested the promotion
+	// +optional
+	PromotedBy string `json:"promotedBy,omitempty"`
+
+	// ApprovedBy is the second user who approved a promotion into a protected channel
+	// +optional
+	ApprovedBy string `json:"approvedBy,omitempty"`
+
+	// PromotedAt is when the promotion completed
+	// +kubebuilder:validation:Required
+	PromotedAt metav1.Time `json:"promotedAt"`
+}
+
+// PendingPromotion is a requested promotion into a protected channel awaiting a second approver
+type PendingPromotion struct {
+	// Channel is the protected channel the image is to be promoted into
+	// +kubebuilder:validation:Required
+	Channel string `json:"channel"`
+
+	// TargetName is the name of the CatalogImage to create on approval
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
+
+	// Digest is the manifest digest of the image when the promotion was requested.
+	// Approval copies this digest and is refused if the image has moved since.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// RequestedBy is the user who requested the promotion
+	// +kubebuilder:validation:Required
+	RequestedBy string `json:"requestedBy"`
+
+	// RequestedAt is when the promotion was requested
+	// +kubebuilder:validation:Required
+	RequestedAt metav1.Time `json:"requestedAt"`
 }
 
 // ArtifactReference represents a downloadable artifact associated with the image
@@ -204,6 +349,9 @@ type ArtifactReference struct {
 	Format string `json:"format,omitempty"`
 }
 
+// ArtifactFormatMirror is the format of the artifact reference recording the mirror copy of an image
+const ArtifactFormatMirror = "mirror"
+
 // RegistryMetadata contains metadata extracted from the container registry
 type RegistryMetadata struct {
 	// ResolvedDigest is the digest resolved from the registry
@@ -286,8 +434,30 @@ const (
 	CatalogImageConditionVerified = "Verified"
 	// CatalogImageConditionReady indicates the image is ready for use
 	CatalogImageConditionReady = "Ready"
+	// CatalogImageConditionMirrored indicates the image was copied into the mirror registry
+	CatalogImageConditionMirrored = "Mirrored"
+	// CatalogImageConditionDigestChanged indicates the tag resolves to a different digest than first seen
+	CatalogImageConditionDigestChanged = "DigestChanged"
 )
 
+// EffectiveLifecycleState returns the lifecycle state of the image, Active if unset
+func (c *CatalogImage) EffectiveLifecycleState() LifecycleState {
+	if c.Spec.Lifecycle == nil || c.Spec.Lifecycle.State == "" {
+		return LifecycleActive
+	}
+	return c.Spec.Lifecycle.State
+}
+
+// MirrorArtifact returns the artifact reference of the image's mirror copy, or nil if it is not mirrored
+func (c *CatalogImage) MirrorArtifact() *ArtifactReference {
+	for i := range c.Status.ArtifactRefs {
+		if c.Status.ArtifactRefs[i].Format == ArtifactFormatMirror {
+			return &c.Status.ArtifactRefs[i]
+		}
+	}
+	return nil
+}
+
 // Label keys for CatalogImage
 const (
 	// LabelArchitecture is the label key for architecture
@@ -302,6 +472,12 @@ const (
 	LabelRegistryType = "automotive.sdv.cloud.redhat.com/registry-type"
 	// LabelSourceType is the label key for source type
 	LabelSourceType = "automotive.sdv.cloud.redhat.com/source-type"
+	// LabelSourceImageBuild is the label key for the ImageBuild an image was published from
+	LabelSourceImageBuild = "automotive.sdv.cloud.redhat.com/source-imagebuild"
+	// LabelChannel is the label key for the promotion channel of an image
+	LabelChannel = "automotive.sdv.cloud.redhat.com/channel"
+	// LabelPromotedFrom is the label key for the CatalogImage an image was promoted from
+	LabelPromotedFrom = "automotive.sdv.cloud.redhat.com/promoted-from"
 )
 
 // Finalizer for CatalogImage
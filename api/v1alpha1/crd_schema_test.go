@@ -92,6 +92,12 @@ func TestCRDSchemaMatchesGoTypes(t *testing.T) {
 			statusType: reflect.TypeOf(OperatorConfigStatus{}),
 			specType:   reflect.TypeOf(OperatorConfigSpec{}),
 		},
+		{
+			name:       "FlashJob",
+			crdFile:    "automotive.sdv.cloud.redhat.com_flashjobs.yaml",
+			statusType: reflect.TypeOf(FlashJobStatus{}),
+			specType:   reflect.TypeOf(FlashJobSpec{}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FlashJobSpec defines an image to flash onto a device leased from Jumpstarter
+type FlashJobSpec struct {
+	// ImageBuildName is the completed ImageBuild in the FlashJob's namespace whose image is flashed
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ImageBuildName string `json:"imageBuildName"`
+
+	// ImageRef overrides the flashed image, an OCI reference or artifact URL. Defaults to the build's
+	// exported OCI reference, or its artifact URL.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9._:/@+~-]+$`
+	// +optional
+	ImageRef string `json:"imageRef,omitempty"`
+
+	// ExporterSelector narrows the exporters the device may be leased from. Its requirements are added
+	// to the selector of the Jumpstarter target mapping of the build's target, which always applies.
+	// The flash command is always the target mapping's.
+	// Example: "lab=lab-1"
+	// +optional
+	ExporterSelector string `json:"exporterSelector,omitempty"`
+
+	// LeaseDuration is how long the exporter is leased for, bounding the flash
+	// Default: 30m
+	// +optional
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+}
+
+// FlashJobPhase represents the progress of a FlashJob
+// +kubebuilder:validation:Enum=Pending;Leasing;Flashing;Succeeded;Failed
+type FlashJobPhase string
+
+const (
+	// FlashJobPhasePending indicates the FlashJob has not requested a lease yet
+	FlashJobPhasePending FlashJobPhase = "Pending"
+	// FlashJobPhaseLeasing indicates the FlashJob waits for an exporter matching its selector
+	FlashJobPhaseLeasing FlashJobPhase = "Leasing"
+	// FlashJobPhaseFlashing indicates the flash pod runs against the leased exporter
+	FlashJobPhaseFlashing FlashJobPhase = "Flashing"
+	// FlashJobPhaseSucceeded indicates the image was flashed
+	FlashJobPhaseSucceeded FlashJobPhase = "Succeeded"
+	// FlashJobPhaseFailed indicates the lease or the flash failed
+	FlashJobPhaseFailed FlashJobPhase = "Failed"
+)
+
+// FlashJobLabel is set on the leases and pods created for a FlashJob to its UID, which unlike its
+// name always fits a label value
+const FlashJobLabel = "automotive.sdv.cloud.redhat.com/flashjob"
+
+// FlashJobStatus defines the observed state of FlashJob
+type FlashJobStatus struct {
+	// Phase is the progress of the FlashJob
+	// +optional
+	Phase FlashJobPhase `json:"phase,omitempty"`
+
+	// Message provides more detail about the current phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ImageRef is the flashed image
+	// +optional
+	ImageRef string `json:"imageRef,omitempty"`
+
+	// ExporterSelector is the selector the exporter was leased with
+	// +optional
+	ExporterSelector string `json:"exporterSelector,omitempty"`
+
+	// FlashCmd is the command run against the exporter, with placeholders replaced
+	// +optional
+	FlashCmd string `json:"flashCmd,omitempty"`
+
+	// LeaseName is the Jumpstarter lease held for the flash
+	// +optional
+	LeaseName string `json:"leaseName,omitempty"`
+
+	// LeaseReleased records that the lease was released
+	// +optional
+	LeaseReleased bool `json:"leaseReleased,omitempty"`
+
+	// ExporterName is the exporter whose device was flashed
+	// +optional
+	ExporterName string `json:"exporterName,omitempty"`
+
+	// PodName is the pod running the flash, whose logs are the flash logs
+	// +optional
+	PodName string `json:"podName,omitempty"`
+
+	// PodNamespace is the namespace of the flash pod, the operator namespace holding the Jumpstarter
+	// client credentials
+	// +optional
+	PodNamespace string `json:"podNamespace,omitempty"`
+
+	// ExitCode is the exit code of the flash command
+	// +optional
+	ExitCode *int32 `json:"exitCode,omitempty"`
+
+	// StartTime is when the lease was requested
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the flash finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// IsFinished reports whether the FlashJob succeeded or failed
+func (s *FlashJobStatus) IsFinished() bool {
+	return s.Phase == FlashJobPhaseSucceeded || s.Phase == FlashJobPhaseFailed
+}
+
+// FlashImageRef is the image flashed for a build: its exported OCI reference, or its artifact URL
+func (b *ImageBuild) FlashImageRef() string {
+	if b.Spec.ExportOCI != "" {
+		return b.Spec.ExportOCI
+	}
+	return b.Status.ArtifactURL
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Build",type=string,JSONPath=`.spec.imageBuildName`
+// +kubebuilder:printcolumn:name="Exporter",type=string,JSONPath=`.status.exporterName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// FlashJob is the Schema for the flashjobs API. It leases a Jumpstarter exporter, flashes the image
+// of an ImageBuild onto its device and releases the lease.
+type FlashJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FlashJobSpec   `json:"spec,omitempty"`
+	Status FlashJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FlashJobList contains a list of FlashJob
+type FlashJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FlashJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FlashJob{}, &FlashJobList{})
+}
@@ -71,12 +71,24 @@ type ImageSize struct {
 // ImageLocation defines where an image is stored with support for different storage types
 type ImageLocation struct {
 	// Type specifies the storage type
-	// +kubebuilder:validation:Enum=registry
+	// +kubebuilder:validation:Enum=registry;http;s3;pvc
 	// +kubebuilder:default=registry
 	Type string `json:"type"`
 
 	// Registry contains configuration for container registry storage
 	Registry *RegistryLocation `json:"registry,omitempty"`
+
+	// HTTP contains configuration for images served over HTTP(S)
+	// +optional
+	HTTP *HTTPLocation `json:"http,omitempty"`
+
+	// S3 contains configuration for images stored in S3-compatible object storage
+	// +optional
+	S3 *S3Location `json:"s3,omitempty"`
+
+	// PVC contains configuration for images stored on a PersistentVolumeClaim
+	// +optional
+	PVC *PVCLocation `json:"pvc,omitempty"`
 }
 
 // RegistryLocation defines storage in a container registry
@@ -91,6 +103,61 @@ type RegistryLocation struct {
 	SecretRef string `json:"secretRef,omitempty"`
 }
 
+// HTTPLocation defines an image file served over HTTP(S)
+type HTTPLocation struct {
+	// URL is the full URL of the image file (e.g., "https://mirror.example.com/images/autosd.qcow2")
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// SHA256 is the expected hex-encoded SHA-256 checksum of the file.
+	// It is compared against digests advertised by the server and verified by clients on download.
+	// +kubebuilder:validation:Pattern=`^[a-f0-9]{64}$`
+	// +optional
+	SHA256 string `json:"sha256,omitempty"`
+
+	// SecretRef is the name of a secret containing either a "token" key (bearer authentication)
+	// or "username" and "password" keys (basic authentication)
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// InsecureSkipTLSVerify disables TLS certificate verification for the server
+	// +optional
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// S3Location defines an image file stored in S3-compatible object storage
+type S3Location struct {
+	// Endpoint is the URL of the S3 endpoint (e.g., "https://s3.us-east-1.amazonaws.com", "http://minio:9000")
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// Bucket is the name of the bucket holding the image
+	Bucket string `json:"bucket"`
+
+	// Key is the object key of the image file
+	Key string `json:"key"`
+
+	// Region is the bucket region (default: us-east-1)
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Secret is the name of the secret containing AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	Secret string `json:"secret"`
+
+	// InsecureSkipTLSVerify disables TLS certificate verification for the endpoint
+	// +optional
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// PVCLocation defines an image file stored on a PersistentVolumeClaim in the Image's namespace
+type PVCLocation struct {
+	// ClaimName is the name of the PersistentVolumeClaim
+	ClaimName string `json:"claimName"`
+
+	// Path is the path of the image file relative to the root of the volume
+	Path string `json:"path"`
+}
+
 // ImageMetadata contains additional metadata about the image
 type ImageMetadata struct {
 	// CreatedBy identifies who or what created this image
@@ -121,6 +188,22 @@ type ImageStatus struct {
 	// LastVerified is when the image location was last verified to be accessible
 	LastVerified *metav1.Time `json:"lastVerified,omitempty"`
 
+	// NextVerification is when the image location is scheduled to be verified again
+	// +optional
+	NextVerification *metav1.Time `json:"nextVerification,omitempty"`
+
+	// ConsecutiveFailures counts failed verifications since the last success and drives the retry backoff
+	// +optional
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
+	// ResolvedDigest is the manifest digest observed at the last successful verification
+	// +optional
+	ResolvedDigest string `json:"resolvedDigest,omitempty"`
+
+	// Size is the image size observed at the last successful verification
+	// +optional
+	Size *ImageSize `json:"size,omitempty"`
+
 	// Message provides more detail about the current phase
 	Message string `json:"message,omitempty"`
 
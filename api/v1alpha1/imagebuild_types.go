@@ -93,12 +93,62 @@ type ImageBuildSpec struct {
 	// ContainerRef is the reference to an existing bootc container image
 	// Used with mode=disk to create a disk image from an existing container
 	ContainerRef string `json:"containerRef,omitempty"`
+
+	// Delta requests a binary delta from a base image to this build's artifact, e.g. for OTA testing
+	// +optional
+	Delta *DeltaSpec `json:"delta,omitempty"`
+
+	// PublishToCatalog creates a CatalogImage for the pushed image once the build completes.
+	// Requires Publishers.Registry, ContainerPush or ExportOCI.
+	// +optional
+	PublishToCatalog *CatalogPublishSpec `json:"publishToCatalog,omitempty"`
+}
+
+// CatalogPublishSpec defines how a completed build is published to the catalog
+type CatalogPublishSpec struct {
+	// NameTemplate is a Go template for the CatalogImage name. The fields .Name, .Namespace,
+	// .Distro, .Target, .Architecture, .Mode and .ExportFormat are available
+	// (default: the ImageBuild name)
+	// +optional
+	NameTemplate string `json:"nameTemplate,omitempty"`
+
+	// Tags are category tags applied to the CatalogImage
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// TargetNotes are recorded on the hardware target of the CatalogImage
+	// +optional
+	TargetNotes string `json:"targetNotes,omitempty"`
+}
+
+// DeltaSpec defines how to produce a binary delta against a base image.
+// Exactly one of BaseImageBuild and BaseCatalogImage must be set.
+type DeltaSpec struct {
+	// BaseImageBuild is the name of a completed ImageBuild in the same namespace whose artifact is the base.
+	// Its workspace PVC is mounted read-only by the delta task.
+	// +optional
+	BaseImageBuild string `json:"baseImageBuild,omitempty"`
+
+	// BaseCatalogImage is the name of a CatalogImage in the same namespace whose disk image
+	// OCI artifact is pulled from the registry and used as the base
+	// +optional
+	BaseCatalogImage string `json:"baseCatalogImage,omitempty"`
+
+	// Method is the delta algorithm: zstd (zstd --patch-from) or bsdiff for disk images,
+	// ostree for a static delta between ostree-commit exports
+	// +kubebuilder:validation:Enum=zstd;bsdiff;ostree
+	// +kubebuilder:default=zstd
+	// +optional
+	Method string `json:"method,omitempty"`
 }
 
 // Publishers defines the configuration for artifact publishing
 type Publishers struct {
 	// Registry configuration for publishing to an OCI registry
 	Registry *RegistryPublisher `json:"registry,omitempty"`
+
+	// S3 configuration for publishing to S3-compatible object storage
+	S3 *S3Publisher `json:"s3,omitempty"`
 }
 
 // RegistryPublisher defines the configuration for publishing to an OCI registry
@@ -110,6 +160,31 @@ type RegistryPublisher struct {
 	Secret string `json:"secret"`
 }
 
+// S3Publisher defines the configuration for publishing to S3-compatible object storage
+type S3Publisher struct {
+	// Endpoint is the URL of the S3 endpoint (e.g., "https://s3.us-east-1.amazonaws.com", "http://minio:9000")
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// Bucket is the name of the bucket to upload artifacts to
+	Bucket string `json:"bucket"`
+
+	// Prefix is prepended to object keys; artifacts are stored under <prefix>/<namespace>/<build-name>/
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Region is the bucket region (default: us-east-1)
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Secret is the name of the secret containing AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	Secret string `json:"secret"`
+
+	// InsecureSkipTLSVerify disables TLS certificate verification for the endpoint
+	// +optional
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+}
+
 // ImageBuildStatus defines the observed state of ImageBuild
 type ImageBuildStatus struct {
 	// ObservedGeneration is the most recent generation observed by the controller.
@@ -138,12 +213,36 @@ type ImageBuildStatus struct {
 	// ArtifactFileName is the name of the artifact file inside the PVC
 	ArtifactFileName string `json:"artifactFileName,omitempty"`
 
+	// ArtifactSHA256 is the SHA-256 checksum of the artifact file, computed at build time
+	ArtifactSHA256 string `json:"artifactSha256,omitempty"`
+
+	// ArtifactSize is the size of the artifact file in bytes, computed at build time
+	ArtifactSize int64 `json:"artifactSize,omitempty"`
+
 	// PipelineRunName is the name of the active PipelineRun for this build
 	PipelineRunName string `json:"pipelineRunName,omitempty"`
 
 	// PushTaskRunName is the name of the TaskRun for pushing artifacts to registry
 	PushTaskRunName string `json:"pushTaskRunName,omitempty"`
 
+	// S3UploadTaskRunName is the name of the TaskRun for uploading artifacts to S3-compatible storage
+	S3UploadTaskRunName string `json:"s3UploadTaskRunName,omitempty"`
+
+	// S3ArtifactKey is the object key of the artifact uploaded to S3-compatible storage
+	S3ArtifactKey string `json:"s3ArtifactKey,omitempty"`
+
+	// DeltaTaskRunName is the name of the TaskRun generating the binary delta
+	DeltaTaskRunName string `json:"deltaTaskRunName,omitempty"`
+
+	// DeltaFileName is the name of the delta file inside the PVC, next to the artifact
+	DeltaFileName string `json:"deltaFileName,omitempty"`
+
+	// DeltaSHA256 is the SHA-256 checksum of the delta file
+	DeltaSHA256 string `json:"deltaSha256,omitempty"`
+
+	// CatalogImageName is the name of the CatalogImage created by PublishToCatalog
+	CatalogImageName string `json:"catalogImageName,omitempty"`
+
 	// ArtifactURL is the route URL created to expose the artifacts
 	ArtifactURL string `json:"artifactURL,omitempty"`
 
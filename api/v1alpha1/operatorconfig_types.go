@@ -17,7 +17,13 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -53,8 +59,9 @@ type JumpstarterTargetMapping struct {
 	// Example: "board-type=j784s4evm"
 	Selector string `json:"selector"`
 
-	// FlashCmd is the command template for flashing the device
-	// Example: "j storage flash ${IMAGE}"
+	// FlashCmd is the command template for flashing the device. The {image_uri} and {artifact_url}
+	// placeholders are replaced by shell-quoted values, so they must not be quoted in the template.
+	// Example: "j storage flash {image_uri}"
 	// +optional
 	FlashCmd string `json:"flashCmd,omitempty"`
 }
@@ -64,6 +71,49 @@ type JumpstarterConfig struct {
 	// TargetMappings maps build targets to Jumpstarter exporter configurations
 	// +optional
 	TargetMappings map[string]JumpstarterTargetMapping `json:"targetMappings,omitempty"`
+
+	// Client is the Jumpstarter client FlashJobs lease exporters as. FlashJobs fail without it.
+	// +optional
+	Client *JumpstarterClientConfig `json:"client,omitempty"`
+}
+
+// JumpstarterClientConfig identifies the Jumpstarter client the operator leases exporters as
+type JumpstarterClientConfig struct {
+	// Name is the Jumpstarter Client object leases are created for
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Client, where leases are created
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// ConfigSecretName is a Secret in the operator namespace whose "client.yaml" key is the client's
+	// jmp configuration. It is mounted into flash pods, which run in the operator namespace and never
+	// in tenant namespaces.
+	// +kubebuilder:validation:MinLength=1
+	ConfigSecretName string `json:"configSecretName"`
+
+	// Image is the container image running jmp in flash pods
+	// Default: "quay.io/jumpstarter-dev/jumpstarter:latest"
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// DefaultFlashCmd flashes the image when the target mapping sets no command
+const DefaultFlashCmd = "j storage flash {image_uri}"
+
+// RenderFlashCmd replaces the {image_uri} and {artifact_url} placeholders of a flash command template.
+// The values are shell-quoted, as flash commands run through sh -c and image references come from users.
+func RenderFlashCmd(template, imageURI, artifactURL string) string {
+	return strings.NewReplacer(
+		"{image_uri}", shellQuote(imageURI),
+		"{artifact_url}", shellQuote(artifactURL),
+	).Replace(template)
+}
+
+// shellQuote quotes s as a single shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 // BuildAPIConfig defines configuration for the Build API server
@@ -87,6 +137,214 @@ type BuildAPIConfig struct {
 	// Default: 120 (2 hours)
 	// +optional
 	MaxLogStreamDurationMinutes int32 `json:"maxLogStreamDurationMinutes,omitempty"`
+
+	// OIDC lets the Build API accept JWTs of an OpenID Connect provider such as Keycloak, for
+	// clusters without OpenShift OAuth. Other bearer tokens are still checked with TokenReviews.
+	// +optional
+	OIDC *OIDCConfig `json:"oidc,omitempty"`
+
+	// Quotas limit the builds each user may run and the artifact storage their builds may hold.
+	// Builds over quota are rejected with HTTP 429.
+	// +optional
+	Quotas *BuildQuotasConfig `json:"quotas,omitempty"`
+
+	// Audit configures where the audit trail of Build API actions is kept besides stdout
+	// +optional
+	Audit *AuditConfig `json:"audit,omitempty"`
+
+	// RateLimits limit how often users and client IPs may create builds, open log streams and
+	// download artifacts. Requests over a limit are rejected with HTTP 429 and a Retry-After header.
+	// +optional
+	RateLimits *RateLimitsConfig `json:"rateLimits,omitempty"`
+
+	// TrustedProxies lists the addresses or CIDRs of proxies in front of the Build API, such as the
+	// ingress controller, whose X-Forwarded-For header gives the client IP used by rate limits and
+	// the audit trail. The oauth-proxy sidecar is always trusted; other clients' headers are ignored.
+	// +optional
+	TrustedProxies []string `json:"trustedProxies,omitempty"`
+}
+
+// RateLimitsConfig sets token-bucket rate limits per user and per client IP. Both apply when set.
+type RateLimitsConfig struct {
+	// PerUser limits each authenticated user, across all of their clients and API tokens
+	// +optional
+	PerUser *OperationRateLimits `json:"perUser,omitempty"`
+
+	// PerIP limits each client IP address, across all users
+	// +optional
+	PerIP *OperationRateLimits `json:"perIP,omitempty"`
+}
+
+// OperationRateLimits sets the rate limit of each rate-limited operation. Unset operations are not limited.
+type OperationRateLimits struct {
+	// BuildCreation limits POST /v1/builds
+	// +optional
+	BuildCreation *RateLimit `json:"buildCreation,omitempty"`
+
+	// LogStreams limits opening build log streams
+	// +optional
+	LogStreams *RateLimit `json:"logStreams,omitempty"`
+
+	// ArtifactDownloads limits artifact and image download requests, including each ranged request
+	// of a parallel download
+	// +optional
+	ArtifactDownloads *RateLimit `json:"artifactDownloads,omitempty"`
+}
+
+// RateLimit is a token bucket refilled with RequestsPerMinute tokens per minute
+type RateLimit struct {
+	// RequestsPerMinute is the sustained request rate
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerMinute int32 `json:"requestsPerMinute"`
+
+	// Burst is the number of requests allowed in quick succession
+	// Default: RequestsPerMinute
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+}
+
+const (
+	// BuildAPIAuditLogDir is where the audit log volume is mounted into the Build API container
+	BuildAPIAuditLogDir = "/var/log/build-api-audit"
+
+	// CorrelationIDAnnotation records the ID of the Build API request that created an object
+	CorrelationIDAnnotation = "automotive.sdv.cloud.redhat.com/correlation-id"
+)
+
+// AuditConfig configures the sinks of the Build API audit trail. Audit events are always written
+// to stdout as JSON lines.
+type AuditConfig struct {
+	// File writes audit events to a size-rotated file on a persistent volume, which also keeps
+	// GET /v1/audit history across restarts
+	// +optional
+	File *AuditFileSink `json:"file,omitempty"`
+
+	// Webhook posts every audit event as JSON to an HTTP endpoint
+	// +optional
+	Webhook *AuditWebhookSink `json:"webhook,omitempty"`
+}
+
+// AuditFileSink is a rotating audit log file on a PersistentVolumeClaim
+type AuditFileSink struct {
+	// ClaimName is the PersistentVolumeClaim in the operator namespace holding the audit log
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// MaxSizeMB rotates the file once it grows beyond this size
+	// Default: 100
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxSizeMB int32 `json:"maxSizeMB,omitempty"`
+
+	// MaxBackups is the number of rotated files kept
+	// Default: 5
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxBackups int32 `json:"maxBackups,omitempty"`
+}
+
+// AuditWebhookSink posts audit events to an HTTP endpoint
+type AuditWebhookSink struct {
+	// URL receives a POST with each audit event as JSON body
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// SecretName is a Secret in the operator namespace whose "token" key is sent as bearer token
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// BuildQuotasConfig assigns build quotas to users, counted from the ImageBuilds they requested
+type BuildQuotasConfig struct {
+	// Default is the quota of users without an override
+	// +optional
+	Default *BuildQuota `json:"default,omitempty"`
+
+	// Overrides set the quota of specific users, or of each member of a group. The first match wins.
+	// +optional
+	Overrides []BuildQuotaOverride `json:"overrides,omitempty"`
+}
+
+// BuildQuota limits the builds of a single user. Zero or unset limits are unlimited.
+type BuildQuota struct {
+	// MaxConcurrentBuilds limits the builds of the user that have neither completed nor failed
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentBuilds int32 `json:"maxConcurrentBuilds,omitempty"`
+
+	// MaxBuildsPerDay limits the builds the user creates in any 24 hours
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxBuildsPerDay int32 `json:"maxBuildsPerDay,omitempty"`
+
+	// MaxArtifactStorage limits the total artifact size of the user's existing builds
+	// Example: "200Gi"
+	// +optional
+	MaxArtifactStorage *resource.Quantity `json:"maxArtifactStorage,omitempty"`
+}
+
+// BuildQuotaOverride is the quota of a user or of the members of a group
+type BuildQuotaOverride struct {
+	// User is the name of the user the quota applies to
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// Group applies the quota to each of its members
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	BuildQuota `json:",inline"`
+}
+
+// QuotaFor returns the quota of a user: the first override naming the user or one of its groups,
+// otherwise the default. Nil means the user's builds are not limited.
+func (q *BuildQuotasConfig) QuotaFor(username string, groups []string) *BuildQuota {
+	if q == nil {
+		return nil
+	}
+	for i := range q.Overrides {
+		override := &q.Overrides[i]
+		if (override.User != "" && override.User == username) ||
+			(override.Group != "" && slices.Contains(groups, override.Group)) {
+			return &override.BuildQuota
+		}
+	}
+	return q.Default
+}
+
+// OIDCConfig configures validation of OpenID Connect ID tokens by the Build API
+type OIDCConfig struct {
+	// IssuerURL is the issuer of accepted tokens, serving OpenID discovery under
+	// /.well-known/openid-configuration
+	// +kubebuilder:validation:Pattern=`^https?://`
+	IssuerURL string `json:"issuerURL"`
+
+	// Audience must be contained in the aud claim of accepted tokens
+	// +kubebuilder:validation:MinLength=1
+	Audience string `json:"audience"`
+
+	// ClientID is the public client caib login authenticates with. Defaults to Audience.
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
+
+	// UsernameClaim is the claim holding the username. Defaults to "sub".
+	// +optional
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+
+	// UsernamePrefix is prepended to usernames to keep them apart from cluster users. Defaults to
+	// "<issuerURL>#"; "-" disables it. Usernames starting with "system:" are rejected.
+	// +optional
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+
+	// GroupsClaim is the claim holding the user's groups. Defaults to "groups".
+	// +optional
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+
+	// GroupsPrefix is prepended to group names. Defaults to "<issuerURL>#"; "-" disables it.
+	// Groups starting with "system:" are rejected.
+	// +optional
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
 }
 
 // OperatorConfigSpec defines the desired state of OperatorConfig
@@ -102,6 +360,287 @@ type OperatorConfigSpec struct {
 	// Jumpstarter defines configuration for Jumpstarter device flashing integration
 	// +optional
 	Jumpstarter *JumpstarterConfig `json:"jumpstarter,omitempty"`
+
+	// Catalog defines configuration for the image catalog
+	// +optional
+	Catalog *CatalogConfig `json:"catalog,omitempty"`
+
+	// Tenancy routes build API requests into per-user and per-team namespaces
+	// +optional
+	Tenancy *TenancyConfig `json:"tenancy,omitempty"`
+
+	// Notifications sends ImageBuild and CatalogImage phase changes to webhooks, Slack and email
+	// +optional
+	Notifications *NotificationsConfig `json:"notifications,omitempty"`
+}
+
+const (
+	// TenantNamespaceLabel marks namespaces the OperatorConfig controller provisions build resources into
+	TenantNamespaceLabel = "automotive.sdv.cloud.redhat.com/tenant"
+
+	// TenantOwnerAnnotation records the user a personal tenant namespace was created for
+	TenantOwnerAnnotation = "automotive.sdv.cloud.redhat.com/tenant-owner"
+
+	// TenantClusterRole is the cluster role bound to the owner or team members of a tenant namespace
+	TenantClusterRole = "ado-tenant-role"
+)
+
+// TenancyConfig defines how build API callers are mapped to tenant namespaces
+type TenancyConfig struct {
+	// Enabled routes build API requests to the caller's tenant namespaces instead of the build API namespace
+	Enabled bool `json:"enabled"`
+
+	// NamespacePrefix is prepended to the caller's user name, followed by a hash of it, to derive their
+	// personal namespace
+	// Default: "ado-user-"
+	// +optional
+	NamespacePrefix string `json:"namespacePrefix,omitempty"`
+
+	// Teams maps user groups to shared team namespaces; members build in the first team listed by default
+	// +listType=map
+	// +listMapKey=group
+	// +optional
+	Teams []TenantTeam `json:"teams,omitempty"`
+
+	// Quota is the hard limit of the ResourceQuota created in every tenant namespace
+	// Example: {"requests.cpu": "16", "persistentvolumeclaims": "10",
+	//           "count/imagebuilds.automotive.sdv.cloud.redhat.com": "20"}
+	// +optional
+	Quota corev1.ResourceList `json:"quota,omitempty"`
+}
+
+// TenantTeam maps a user group to a shared tenant namespace
+type TenantTeam struct {
+	// Group is the user group whose members may build in Namespace
+	// +kubebuilder:validation:Required
+	Group string `json:"group"`
+
+	// Namespace is the team's tenant namespace, created by the operator if missing
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Namespace string `json:"namespace"`
+}
+
+// defaultTenantNamespacePrefix prefixes personal namespaces when NamespacePrefix is unset
+const defaultTenantNamespacePrefix = "ado-user-"
+
+// UserNamespace returns the personal tenant namespace of a user, the prefixed user name reduced to a
+// DNS label: lowercased, with runs of other characters replaced by a dash and truncated to fit 63
+// characters with a hash of the user name, so that user names reduced alike get distinct namespaces
+func (t *TenancyConfig) UserNamespace(username string) string {
+	prefix := t.NamespacePrefix
+	if prefix == "" {
+		prefix = defaultTenantNamespacePrefix
+	}
+
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(prefix + username) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	sum := sha256.Sum256([]byte(username))
+	suffix := hex.EncodeToString(sum[:])[:8]
+	name := b.String()
+	if limit := 63 - len(suffix) - 1; len(name) > limit {
+		name = name[:limit]
+	}
+	return strings.Trim(name, "-") + "-" + suffix
+}
+
+// Namespaces returns the tenant namespaces a caller may use, its default first: the namespaces of
+// the teams it belongs to in the order they are configured, then its personal namespace
+func (t *TenancyConfig) Namespaces(username string, groups []string) []string {
+	var namespaces []string
+	for _, team := range t.Teams {
+		if slices.Contains(groups, team.Group) && !slices.Contains(namespaces, team.Namespace) {
+			namespaces = append(namespaces, team.Namespace)
+		}
+	}
+	if personal := t.UserNamespace(username); personal != "" && !slices.Contains(namespaces, personal) {
+		namespaces = append(namespaces, personal)
+	}
+	return namespaces
+}
+
+// RequestedByAnnotation records the user who requested an ImageBuild through the Build API
+const RequestedByAnnotation = "automotive.sdv.cloud.redhat.com/requested-by"
+
+// NotificationsConfig defines where ImageBuild and CatalogImage phase changes are sent
+type NotificationsConfig struct {
+	// Targets receive the phase changes matching their filter
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Targets []NotificationTarget `json:"targets,omitempty"`
+}
+
+// NotificationTarget is a destination of notifications. Exactly one of webhook, slack and email must be set.
+// +kubebuilder:validation:XValidation:rule="[has(self.webhook), has(self.slack), has(self.email)].filter(x, x).size() == 1",message="exactly one of webhook, slack and email must be set"
+type NotificationTarget struct {
+	// Name identifies the target in logs and metrics
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Webhook posts each notification as JSON to an HTTP endpoint
+	// +optional
+	Webhook *NotificationWebhook `json:"webhook,omitempty"`
+
+	// Slack posts a message to a Slack-compatible incoming webhook
+	// +optional
+	Slack *NotificationSlack `json:"slack,omitempty"`
+
+	// Email sends a message through an SMTP server
+	// +optional
+	Email *NotificationEmail `json:"email,omitempty"`
+
+	// Filter selects the phase changes sent to the target. Without a filter, completed and failed
+	// builds and catalog images becoming unavailable are sent.
+	// +optional
+	Filter *NotificationFilter `json:"filter,omitempty"`
+}
+
+// NotificationWebhook posts notifications as JSON to an HTTP endpoint
+type NotificationWebhook struct {
+	// URL receives a POST with each notification as JSON body
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// SecretName is a Secret in the operator namespace whose "token" key signs "<timestamp>.<body>" with
+	// HMAC-SHA256, sent as "X-ADO-Signature-256: sha256=<hex digest>" along with "X-ADO-Timestamp: <unix
+	// seconds>". Receivers should reject requests signed more than 5 minutes ago.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// NotificationSlack posts notifications to a Slack-compatible incoming webhook
+type NotificationSlack struct {
+	// SecretName is a Secret in the operator namespace whose "url" key holds the incoming webhook URL
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+}
+
+// NotificationEmail sends notifications through an SMTP server, using STARTTLS when the server offers it
+type NotificationEmail struct {
+	// Host is the SMTP server host name
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// Port is the SMTP submission port
+	// Default: 587
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// From is the sender address
+	// +kubebuilder:validation:MinLength=1
+	From string `json:"from"`
+
+	// To are the recipient addresses
+	// +kubebuilder:validation:MinItems=1
+	To []string `json:"to"`
+
+	// SecretName is a Secret in the operator namespace whose "username" and "password" keys
+	// authenticate to the server
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// NotificationFilter selects the phase changes sent to a notification target. All set fields must match.
+type NotificationFilter struct {
+	// BuildPhases are the ImageBuild phases notified
+	// Default: Completed and Failed, or none when only catalogPhases is set
+	// +optional
+	BuildPhases []string `json:"buildPhases,omitempty"`
+
+	// CatalogPhases are the CatalogImage phases notified
+	// Default: Unavailable, or none when only buildPhases is set
+	// +optional
+	CatalogPhases []CatalogImagePhase `json:"catalogPhases,omitempty"`
+
+	// Users limits build notifications to ImageBuilds requested by these users through the Build API;
+	// catalog notifications are not filtered by user
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// Labels limits notifications to objects carrying all of these labels
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// CatalogConfig defines configuration for the image catalog
+type CatalogConfig struct {
+	// Channels are the promotion channels catalog images can be promoted into
+	// Example: dev, qa, release
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Channels []PromotionChannel `json:"channels,omitempty"`
+
+	// Mirror configures the local registry catalog images are mirrored into
+	// +optional
+	Mirror *CatalogMirrorPolicy `json:"mirror,omitempty"`
+
+	// Webhook configures the build API endpoint receiving registry push and delete notifications
+	// +optional
+	Webhook *RegistryWebhookConfig `json:"webhook,omitempty"`
+}
+
+// RegistryWebhookConfig configures the receiver of registry notifications
+type RegistryWebhookConfig struct {
+	// SecretName is the secret in the OperatorConfig namespace whose "token" key holds the shared secret
+	// registries must send with their notifications
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
+}
+
+// CatalogMirrorPolicy configures mirroring of catalog images into a local registry
+type CatalogMirrorPolicy struct {
+	// RepositoryURL is the registry path images are mirrored under, each into a repository named after
+	// the last path component of its source repository
+	// Example: "image-registry.openshift-image-registry.svc:5000/automotive-catalog"
+	// +kubebuilder:validation:Required
+	RepositoryURL string `json:"repositoryUrl"`
+
+	// AuthSecretRef references the credentials used to push to the mirror registry
+	// +optional
+	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
+
+	// MirrorAll mirrors every catalog image, not only those that set spec.mirror
+	// +optional
+	MirrorAll bool `json:"mirrorAll,omitempty"`
+}
+
+// PromotionChannel defines a named lifecycle stage of catalog images backed by a registry repository
+type PromotionChannel struct {
+	// Name is the channel name used by `caib catalog promote --to`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// RepositoryURL is the registry repository promoted images are copied to, without tag
+	// Example: "quay.io/myorg/autosd-release"
+	// +kubebuilder:validation:Required
+	RepositoryURL string `json:"repositoryUrl"`
+
+	// AuthSecretRef references the credentials used to push to the channel repository.
+	// The secret is looked up in the OperatorConfig namespace unless the reference names one.
+	// +optional
+	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
+
+	// Protected requires a second user to approve promotions into this channel
+	// +optional
+	Protected bool `json:"protected,omitempty"`
 }
 
 // OSBuildsConfig defines configuration for OS build operations
@@ -171,6 +710,10 @@ type OperatorConfigStatus struct {
 
 	// JumpstarterAvailable indicates if Jumpstarter CRDs are present in the cluster
 	JumpstarterAvailable bool `json:"jumpstarterAvailable,omitempty"`
+
+	// TenantNamespaces lists the tenant namespaces build resources are provisioned in
+	// +optional
+	TenantNamespaces []string `json:"tenantNamespaces,omitempty"`
 }
 
 // +kubebuilder:object:root=true
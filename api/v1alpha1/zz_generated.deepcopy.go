@@ -41,6 +41,61 @@ func (in *ArtifactReference) DeepCopy() *ArtifactReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditConfig) DeepCopyInto(out *AuditConfig) {
+	*out = *in
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(AuditFileSink)
+		**out = **in
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(AuditWebhookSink)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditConfig.
+func (in *AuditConfig) DeepCopy() *AuditConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditFileSink) DeepCopyInto(out *AuditFileSink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditFileSink.
+func (in *AuditFileSink) DeepCopy() *AuditFileSink {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditFileSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditWebhookSink) DeepCopyInto(out *AuditWebhookSink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditWebhookSink.
+func (in *AuditWebhookSink) DeepCopy() *AuditWebhookSink {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditWebhookSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthSecretReference) DeepCopyInto(out *AuthSecretReference) {
 	*out = *in
@@ -59,6 +114,31 @@ func (in *AuthSecretReference) DeepCopy() *AuthSecretReference {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildAPIConfig) DeepCopyInto(out *BuildAPIConfig) {
 	*out = *in
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCConfig)
+		**out = **in
+	}
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = new(BuildQuotasConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(AuditConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimits != nil {
+		in, out := &in.RateLimits, &out.RateLimits
+		*out = new(RateLimitsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrustedProxies != nil {
+		in, out := &in.TrustedProxies, &out.TrustedProxies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildAPIConfig.
@@ -86,6 +166,101 @@ func (in *BuildConfig) DeepCopy() *BuildConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildQuota) DeepCopyInto(out *BuildQuota) {
+	*out = *in
+	if in.MaxArtifactStorage != nil {
+		in, out := &in.MaxArtifactStorage, &out.MaxArtifactStorage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildQuota.
+func (in *BuildQuota) DeepCopy() *BuildQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildQuotaOverride) DeepCopyInto(out *BuildQuotaOverride) {
+	*out = *in
+	in.BuildQuota.DeepCopyInto(&out.BuildQuota)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildQuotaOverride.
+func (in *BuildQuotaOverride) DeepCopy() *BuildQuotaOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildQuotaOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildQuotasConfig) DeepCopyInto(out *BuildQuotasConfig) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(BuildQuota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]BuildQuotaOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildQuotasConfig.
+func (in *BuildQuotasConfig) DeepCopy() *BuildQuotasConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildQuotasConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogConfig) DeepCopyInto(out *CatalogConfig) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]PromotionChannel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(CatalogMirrorPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(RegistryWebhookConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogConfig.
+func (in *CatalogConfig) DeepCopy() *CatalogConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CatalogImage) DeepCopyInto(out *CatalogImage) {
 	*out = *in
@@ -113,6 +288,21 @@ func (in *CatalogImage) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogImageLifecycle) DeepCopyInto(out *CatalogImageLifecycle) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogImageLifecycle.
+func (in *CatalogImageLifecycle) DeepCopy() *CatalogImageLifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogImageLifecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CatalogImageList) DeepCopyInto(out *CatalogImageList) {
 	*out = *in
@@ -165,6 +355,26 @@ func (in *CatalogImageMetadata) DeepCopy() *CatalogImageMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogImageMirror) DeepCopyInto(out *CatalogImageMirror) {
+	*out = *in
+	if in.AuthSecretRef != nil {
+		in, out := &in.AuthSecretRef, &out.AuthSecretRef
+		*out = new(AuthSecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogImageMirror.
+func (in *CatalogImageMirror) DeepCopy() *CatalogImageMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogImageMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CatalogImageSpec) DeepCopyInto(out *CatalogImageSpec) {
 	*out = *in
@@ -183,6 +393,16 @@ func (in *CatalogImageSpec) DeepCopyInto(out *CatalogImageSpec) {
 		*out = new(CatalogImageMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(CatalogImageLifecycle)
+		**out = **in
+	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(CatalogImageMirror)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogImageSpec.
@@ -223,6 +443,27 @@ func (in *CatalogImageStatus) DeepCopyInto(out *CatalogImageStatus) {
 		*out = make([]ArtifactReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.PromotionHistory != nil {
+		in, out := &in.PromotionHistory, &out.PromotionHistory
+		*out = make([]PromotionRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingPromotions != nil {
+		in, out := &in.PendingPromotions, &out.PendingPromotions
+		*out = make([]PendingPromotion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DigestHistory != nil {
+		in, out := &in.DigestHistory, &out.DigestHistory
+		*out = make([]DigestRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogImageStatus.
@@ -235,6 +476,184 @@ func (in *CatalogImageStatus) DeepCopy() *CatalogImageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogMirrorPolicy) DeepCopyInto(out *CatalogMirrorPolicy) {
+	*out = *in
+	if in.AuthSecretRef != nil {
+		in, out := &in.AuthSecretRef, &out.AuthSecretRef
+		*out = new(AuthSecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogMirrorPolicy.
+func (in *CatalogMirrorPolicy) DeepCopy() *CatalogMirrorPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogMirrorPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogPublishSpec) DeepCopyInto(out *CatalogPublishSpec) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogPublishSpec.
+func (in *CatalogPublishSpec) DeepCopy() *CatalogPublishSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogPublishSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeltaSpec) DeepCopyInto(out *DeltaSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeltaSpec.
+func (in *DeltaSpec) DeepCopy() *DeltaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeltaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DigestRecord) DeepCopyInto(out *DigestRecord) {
+	*out = *in
+	in.FirstSeen.DeepCopyInto(&out.FirstSeen)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DigestRecord.
+func (in *DigestRecord) DeepCopy() *DigestRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(DigestRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlashJob) DeepCopyInto(out *FlashJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlashJob.
+func (in *FlashJob) DeepCopy() *FlashJob {
+	if in == nil {
+		return nil
+	}
+	out := new(FlashJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FlashJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlashJobList) DeepCopyInto(out *FlashJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FlashJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlashJobList.
+func (in *FlashJobList) DeepCopy() *FlashJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(FlashJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FlashJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlashJobSpec) DeepCopyInto(out *FlashJobSpec) {
+	*out = *in
+	if in.LeaseDuration != nil {
+		in, out := &in.LeaseDuration, &out.LeaseDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlashJobSpec.
+func (in *FlashJobSpec) DeepCopy() *FlashJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FlashJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlashJobStatus) DeepCopyInto(out *FlashJobStatus) {
+	*out = *in
+	if in.ExitCode != nil {
+		in, out := &in.ExitCode, &out.ExitCode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlashJobStatus.
+func (in *FlashJobStatus) DeepCopy() *FlashJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FlashJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HardwareTarget) DeepCopyInto(out *HardwareTarget) {
 	*out = *in
@@ -250,6 +669,21 @@ func (in *HardwareTarget) DeepCopy() *HardwareTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPLocation) DeepCopyInto(out *HTTPLocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPLocation.
+func (in *HTTPLocation) DeepCopy() *HTTPLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Image) DeepCopyInto(out *Image) {
 	*out = *in
@@ -344,6 +778,16 @@ func (in *ImageBuildSpec) DeepCopyInto(out *ImageBuildSpec) {
 		*out = new(Publishers)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Delta != nil {
+		in, out := &in.Delta, &out.Delta
+		*out = new(DeltaSpec)
+		**out = **in
+	}
+	if in.PublishToCatalog != nil {
+		in, out := &in.PublishToCatalog, &out.PublishToCatalog
+		*out = new(CatalogPublishSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBuildSpec.
@@ -426,6 +870,21 @@ func (in *ImageLocation) DeepCopyInto(out *ImageLocation) {
 		*out = new(RegistryLocation)
 		**out = **in
 	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPLocation)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3Location)
+		**out = **in
+	}
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(PVCLocation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageLocation.
@@ -452,149 +911,337 @@ func (in *ImageMetadata) DeepCopyInto(out *ImageMetadata) {
 			(*out)[key] = val
 		}
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMetadata.
+func (in *ImageMetadata) DeepCopy() *ImageMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSize) DeepCopyInto(out *ImageSize) {
+	*out = *in
+	if in.CompressedBytes != nil {
+		in, out := &in.CompressedBytes, &out.CompressedBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.UncompressedBytes != nil {
+		in, out := &in.UncompressedBytes, &out.UncompressedBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.VirtualBytes != nil {
+		in, out := &in.VirtualBytes, &out.VirtualBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSize.
+func (in *ImageSize) DeepCopy() *ImageSize {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSize)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(ImageSize)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Location.DeepCopyInto(&out.Location)
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(ImageMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSpec.
+func (in *ImageSpec) DeepCopy() *ImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
+	*out = *in
+	if in.LastVerified != nil {
+		in, out := &in.LastVerified, &out.LastVerified
+		*out = (*in).DeepCopy()
+	}
+	if in.NextVerification != nil {
+		in, out := &in.NextVerification, &out.NextVerification
+		*out = (*in).DeepCopy()
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(ImageSize)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastAccessed != nil {
+		in, out := &in.LastAccessed, &out.LastAccessed
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
+func (in *ImageStatus) DeepCopy() *ImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumpstarterClientConfig) DeepCopyInto(out *JumpstarterClientConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumpstarterClientConfig.
+func (in *JumpstarterClientConfig) DeepCopy() *JumpstarterClientConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(JumpstarterClientConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumpstarterConfig) DeepCopyInto(out *JumpstarterConfig) {
+	*out = *in
+	if in.TargetMappings != nil {
+		in, out := &in.TargetMappings, &out.TargetMappings
+		*out = make(map[string]JumpstarterTargetMapping, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Client != nil {
+		in, out := &in.Client, &out.Client
+		*out = new(JumpstarterClientConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumpstarterConfig.
+func (in *JumpstarterConfig) DeepCopy() *JumpstarterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(JumpstarterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumpstarterTargetMapping) DeepCopyInto(out *JumpstarterTargetMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumpstarterTargetMapping.
+func (in *JumpstarterTargetMapping) DeepCopy() *JumpstarterTargetMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(JumpstarterTargetMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationEmail) DeepCopyInto(out *NotificationEmail) {
+	*out = *in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationEmail.
+func (in *NotificationEmail) DeepCopy() *NotificationEmail {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationEmail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationFilter) DeepCopyInto(out *NotificationFilter) {
+	*out = *in
+	if in.BuildPhases != nil {
+		in, out := &in.BuildPhases, &out.BuildPhases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CatalogPhases != nil {
+		in, out := &in.CatalogPhases, &out.CatalogPhases
+		*out = make([]CatalogImagePhase, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMetadata.
-func (in *ImageMetadata) DeepCopy() *ImageMetadata {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationFilter.
+func (in *NotificationFilter) DeepCopy() *NotificationFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageMetadata)
+	out := new(NotificationFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSize) DeepCopyInto(out *ImageSize) {
+func (in *NotificationSlack) DeepCopyInto(out *NotificationSlack) {
 	*out = *in
-	if in.CompressedBytes != nil {
-		in, out := &in.CompressedBytes, &out.CompressedBytes
-		*out = new(int64)
-		**out = **in
-	}
-	if in.UncompressedBytes != nil {
-		in, out := &in.UncompressedBytes, &out.UncompressedBytes
-		*out = new(int64)
-		**out = **in
-	}
-	if in.VirtualBytes != nil {
-		in, out := &in.VirtualBytes, &out.VirtualBytes
-		*out = new(int64)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSize.
-func (in *ImageSize) DeepCopy() *ImageSize {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSlack.
+func (in *NotificationSlack) DeepCopy() *NotificationSlack {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageSize)
+	out := new(NotificationSlack)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
+func (in *NotificationTarget) DeepCopyInto(out *NotificationTarget) {
 	*out = *in
-	if in.Size != nil {
-		in, out := &in.Size, &out.Size
-		*out = new(ImageSize)
-		(*in).DeepCopyInto(*out)
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(NotificationWebhook)
+		**out = **in
 	}
-	in.Location.DeepCopyInto(&out.Location)
-	if in.Metadata != nil {
-		in, out := &in.Metadata, &out.Metadata
-		*out = new(ImageMetadata)
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(NotificationSlack)
+		**out = **in
+	}
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(NotificationEmail)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(NotificationFilter)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSpec.
-func (in *ImageSpec) DeepCopy() *ImageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTarget.
+func (in *NotificationTarget) DeepCopy() *NotificationTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageSpec)
+	out := new(NotificationTarget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
+func (in *NotificationWebhook) DeepCopyInto(out *NotificationWebhook) {
 	*out = *in
-	if in.LastVerified != nil {
-		in, out := &in.LastVerified, &out.LastVerified
-		*out = (*in).DeepCopy()
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.LastAccessed != nil {
-		in, out := &in.LastAccessed, &out.LastAccessed
-		*out = (*in).DeepCopy()
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
-func (in *ImageStatus) DeepCopy() *ImageStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationWebhook.
+func (in *NotificationWebhook) DeepCopy() *NotificationWebhook {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageStatus)
+	out := new(NotificationWebhook)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JumpstarterConfig) DeepCopyInto(out *JumpstarterConfig) {
+func (in *NotificationsConfig) DeepCopyInto(out *NotificationsConfig) {
 	*out = *in
-	if in.TargetMappings != nil {
-		in, out := &in.TargetMappings, &out.TargetMappings
-		*out = make(map[string]JumpstarterTargetMapping, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]NotificationTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumpstarterConfig.
-func (in *JumpstarterConfig) DeepCopy() *JumpstarterConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationsConfig.
+func (in *NotificationsConfig) DeepCopy() *NotificationsConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(JumpstarterConfig)
+	out := new(NotificationsConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JumpstarterTargetMapping) DeepCopyInto(out *JumpstarterTargetMapping) {
+func (in *OIDCConfig) DeepCopyInto(out *OIDCConfig) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumpstarterTargetMapping.
-func (in *JumpstarterTargetMapping) DeepCopy() *JumpstarterTargetMapping {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCConfig.
+func (in *OIDCConfig) DeepCopy() *OIDCConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(JumpstarterTargetMapping)
+	out := new(OIDCConfig)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -628,13 +1275,43 @@ func (in *OSBuildsConfig) DeepCopy() *OSBuildsConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationRateLimits) DeepCopyInto(out *OperationRateLimits) {
+	*out = *in
+	if in.BuildCreation != nil {
+		in, out := &in.BuildCreation, &out.BuildCreation
+		*out = new(RateLimit)
+		**out = **in
+	}
+	if in.LogStreams != nil {
+		in, out := &in.LogStreams, &out.LogStreams
+		*out = new(RateLimit)
+		**out = **in
+	}
+	if in.ArtifactDownloads != nil {
+		in, out := &in.ArtifactDownloads, &out.ArtifactDownloads
+		*out = new(RateLimit)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationRateLimits.
+func (in *OperationRateLimits) DeepCopy() *OperationRateLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationRateLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfig.
@@ -698,13 +1375,28 @@ func (in *OperatorConfigSpec) DeepCopyInto(out *OperatorConfigSpec) {
 	if in.BuildAPI != nil {
 		in, out := &in.BuildAPI, &out.BuildAPI
 		*out = new(BuildAPIConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Jumpstarter != nil {
 		in, out := &in.Jumpstarter, &out.Jumpstarter
 		*out = new(JumpstarterConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Catalog != nil {
+		in, out := &in.Catalog, &out.Catalog
+		*out = new(CatalogConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tenancy != nil {
+		in, out := &in.Tenancy, &out.Tenancy
+		*out = new(TenancyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(NotificationsConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigSpec.
@@ -720,6 +1412,11 @@ func (in *OperatorConfigSpec) DeepCopy() *OperatorConfigSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorConfigStatus) DeepCopyInto(out *OperatorConfigStatus) {
 	*out = *in
+	if in.TenantNamespaces != nil {
+		in, out := &in.TenantNamespaces, &out.TenantNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigStatus.
@@ -732,6 +1429,37 @@ func (in *OperatorConfigStatus) DeepCopy() *OperatorConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCLocation) DeepCopyInto(out *PVCLocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCLocation.
+func (in *PVCLocation) DeepCopy() *PVCLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingPromotion) DeepCopyInto(out *PendingPromotion) {
+	*out = *in
+	in.RequestedAt.DeepCopyInto(&out.RequestedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingPromotion.
+func (in *PendingPromotion) DeepCopy() *PendingPromotion {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingPromotion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlatformInfo) DeepCopyInto(out *PlatformInfo) {
 	*out = *in
@@ -762,6 +1490,42 @@ func (in *PlatformVariant) DeepCopy() *PlatformVariant {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionChannel) DeepCopyInto(out *PromotionChannel) {
+	*out = *in
+	if in.AuthSecretRef != nil {
+		in, out := &in.AuthSecretRef, &out.AuthSecretRef
+		*out = new(AuthSecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionChannel.
+func (in *PromotionChannel) DeepCopy() *PromotionChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionRecord) DeepCopyInto(out *PromotionRecord) {
+	*out = *in
+	in.PromotedAt.DeepCopyInto(&out.PromotedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionRecord.
+func (in *PromotionRecord) DeepCopy() *PromotionRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Publishers) DeepCopyInto(out *Publishers) {
 	*out = *in
@@ -770,6 +1534,11 @@ func (in *Publishers) DeepCopyInto(out *Publishers) {
 		*out = new(RegistryPublisher)
 		**out = **in
 	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3Publisher)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Publishers.
@@ -782,6 +1551,46 @@ func (in *Publishers) DeepCopy() *Publishers {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitsConfig) DeepCopyInto(out *RateLimitsConfig) {
+	*out = *in
+	if in.PerUser != nil {
+		in, out := &in.PerUser, &out.PerUser
+		*out = new(OperationRateLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PerIP != nil {
+		in, out := &in.PerIP, &out.PerIP
+		*out = new(OperationRateLimits)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitsConfig.
+func (in *RateLimitsConfig) DeepCopy() *RateLimitsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegistryLocation) DeepCopyInto(out *RegistryLocation) {
 	*out = *in
@@ -840,3 +1649,90 @@ func (in *RegistryPublisher) DeepCopy() *RegistryPublisher {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryWebhookConfig) DeepCopyInto(out *RegistryWebhookConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryWebhookConfig.
+func (in *RegistryWebhookConfig) DeepCopy() *RegistryWebhookConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryWebhookConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Location) DeepCopyInto(out *S3Location) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Location.
+func (in *S3Location) DeepCopy() *S3Location {
+	if in == nil {
+		return nil
+	}
+	out := new(S3Location)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Publisher) DeepCopyInto(out *S3Publisher) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Publisher.
+func (in *S3Publisher) DeepCopy() *S3Publisher {
+	if in == nil {
+		return nil
+	}
+	out := new(S3Publisher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenancyConfig) DeepCopyInto(out *TenancyConfig) {
+	*out = *in
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]TenantTeam, len(*in))
+		copy(*out, *in)
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenancyConfig.
+func (in *TenancyConfig) DeepCopy() *TenancyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TenancyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantTeam) DeepCopyInto(out *TenantTeam) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantTeam.
+func (in *TenantTeam) DeepCopy() *TenantTeam {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantTeam)
+	in.DeepCopyInto(out)
+	return out
+}
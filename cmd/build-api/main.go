@@ -65,8 +65,9 @@ func main() {
 		}
 	}
 
-	// Load API limits from OperatorConfig
-	limits := loadLimitsFromOperatorConfig(*namespace, logger)
+	// Load API limits and authentication settings from OperatorConfig
+	buildAPIConfig := loadBuildAPIConfig(*namespace, logger)
+	limits := buildapi.LoadLimitsFromConfig(buildAPIConfig)
 
 	slog.Info("starting build-api server",
 		"addr", addr,
@@ -79,6 +80,29 @@ func main() {
 		"maxLogStreamDurationMinutes", limits.MaxLogStreamDurationMinutes)
 
 	apiServer := buildapi.NewAPIServerWithLimits(addr, logger, limits)
+	if buildAPIConfig != nil && buildAPIConfig.OIDC != nil {
+		slog.Info("accepting OIDC tokens", "issuer", buildAPIConfig.OIDC.IssuerURL)
+		apiServer.EnableOIDC(buildAPIConfig.OIDC)
+	}
+	if buildAPIConfig != nil && buildAPIConfig.RateLimits != nil {
+		slog.Info("enforcing rate limits")
+		apiServer.EnableRateLimits(buildAPIConfig.RateLimits)
+	}
+	if buildAPIConfig != nil && len(buildAPIConfig.TrustedProxies) > 0 {
+		if err := apiServer.EnableTrustedProxies(buildAPIConfig.TrustedProxies); err != nil {
+			slog.Error("invalid trusted proxies", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("trusting forwarded client addresses", "proxies", buildAPIConfig.TrustedProxies)
+	}
+	if buildAPIConfig != nil && buildAPIConfig.Audit != nil {
+		if err := apiServer.EnableAudit(buildAPIConfig.Audit); err != nil {
+			slog.Error("failed to configure audit sinks", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("writing audit events to configured sinks",
+			"file", buildAPIConfig.Audit.File != nil, "webhook", buildAPIConfig.Audit.Webhook != nil)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -97,21 +121,22 @@ func main() {
 	}
 }
 
-func loadLimitsFromOperatorConfig(namespace string, logger logr.Logger) buildapi.APILimits {
+// loadBuildAPIConfig reads the build API settings of the OperatorConfig, nil meaning defaults
+func loadBuildAPIConfig(namespace string, logger logr.Logger) *automotivev1alpha1.BuildAPIConfig {
 	k8sClient, err := createK8sClient()
 	if err != nil {
-		logger.Info("could not create Kubernetes client, using default limits", "error", err)
-		return buildapi.DefaultAPILimits()
+		logger.Info("could not create Kubernetes client, using default settings", "error", err)
+		return nil
 	}
 
 	operatorConfig := &automotivev1alpha1.OperatorConfig{}
 	key := types.NamespacedName{Name: "config", Namespace: namespace}
 	if err := k8sClient.Get(context.Background(), key, operatorConfig); err != nil {
-		logger.Info("could not get OperatorConfig, using default limits", "error", err)
-		return buildapi.DefaultAPILimits()
+		logger.Info("could not get OperatorConfig, using default settings", "error", err)
+		return nil
 	}
 
-	return buildapi.LoadLimitsFromConfig(operatorConfig.Spec.BuildAPI)
+	return operatorConfig.Spec.BuildAPI
 }
 
 func createK8sClient() (client.Client, error) {
@@ -0,0 +1,397 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth logs caib in to the build API with OpenID Connect and caches the resulting tokens.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+const (
+	// expiryLeeway refreshes tokens this long before they expire
+	expiryLeeway = 30 * time.Second
+	// browserLoginTimeout bounds how long caib waits for the browser redirect
+	browserLoginTimeout = 5 * time.Minute
+)
+
+// loginScopes asks for an ID token with the user's profile and a refresh token
+var loginScopes = []string{"openid", "profile", "email", "offline_access"}
+
+// Credentials are the tokens cached for one build API server
+type Credentials struct {
+	IssuerURL    string    `json:"issuerURL"`
+	ClientID     string    `json:"clientID"`
+	TokenURL     string    `json:"tokenURL"`
+	IDToken      string    `json:"idToken"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// LoginOptions control the login flow
+type LoginOptions struct {
+	// Browser forces the authorization code flow even when the provider supports device codes
+	Browser bool
+	// Out receives the instructions for the user
+	Out io.Writer
+}
+
+type discovery struct {
+	Issuer                      string `json:"issuer"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+// Login authenticates with the OpenID provider of the build API server and caches the tokens.
+// Providers supporting it use the device code flow, others a browser login with PKCE.
+func Login(ctx context.Context, server string, opts LoginOptions) (*Credentials, error) {
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	server = strings.TrimSuffix(server, "/")
+
+	var authConfig buildapitypes.AuthConfigResponse
+	if err := getJSON(ctx, server+"/v1/auth/config", &authConfig); err != nil {
+		return nil, fmt.Errorf("failed to get authentication settings of %s: %w", server, err)
+	}
+	if authConfig.OIDC == nil {
+		return nil, fmt.Errorf("%s does not accept OIDC logins, use a cluster token instead", server)
+	}
+
+	issuer := strings.TrimSuffix(authConfig.OIDC.IssuerURL, "/")
+	var provider discovery
+	if err := getJSON(ctx, issuer+"/.well-known/openid-configuration", &provider); err != nil {
+		return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+	if strings.TrimSuffix(provider.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("OIDC discovery returned issuer %q, expected %q", provider.Issuer, issuer)
+	}
+
+	config := &oauth2.Config{
+		ClientID: authConfig.OIDC.ClientID,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:       provider.AuthorizationEndpoint,
+			TokenURL:      provider.TokenEndpoint,
+			DeviceAuthURL: provider.DeviceAuthorizationEndpoint,
+		},
+		Scopes: loginScopes,
+	}
+
+	var token *oauth2.Token
+	var err error
+	if provider.DeviceAuthorizationEndpoint != "" && !opts.Browser {
+		token, err = deviceLogin(ctx, config, opts.Out)
+	} else {
+		token, err = browserLogin(ctx, config, opts.Out)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	creds := &Credentials{IssuerURL: issuer, ClientID: config.ClientID, TokenURL: config.Endpoint.TokenURL}
+	if err := creds.update(token); err != nil {
+		return nil, err
+	}
+	if err := saveCredentials(server, creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// Logout forgets the cached tokens of a build API server
+func Logout(server string) error {
+	return saveCredentials(strings.TrimSuffix(server, "/"), nil)
+}
+
+// CachedToken returns the ID token cached for a build API server, refreshing it when it expired.
+// It returns an empty token when caib is not logged in to the server.
+func CachedToken(ctx context.Context, server string) (string, error) {
+	server = strings.TrimSuffix(server, "/")
+	store, err := loadCredentials()
+	if err != nil {
+		return "", err
+	}
+	creds := store[server]
+	if creds == nil {
+		return "", nil
+	}
+	if time.Now().Add(expiryLeeway).Before(creds.Expiry) {
+		return creds.IDToken, nil
+	}
+	if creds.RefreshToken == "" {
+		return "", errors.New("login expired, run caib login again")
+	}
+
+	config := &oauth2.Config{ClientID: creds.ClientID, Endpoint: oauth2.Endpoint{TokenURL: creds.TokenURL}}
+	token, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: creds.RefreshToken}).Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh login, run caib login again: %w", err)
+	}
+	if err := creds.update(token); err != nil {
+		return "", err
+	}
+	if err := saveCredentials(server, creds); err != nil {
+		return "", err
+	}
+	return creds.IDToken, nil
+}
+
+// update stores the ID token of a token response, keeping the previous refresh token when the
+// provider did not rotate it
+func (c *Credentials) update(token *oauth2.Token) error {
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		return errors.New("the OIDC provider returned no ID token")
+	}
+	expiry, err := idTokenExpiry(idToken)
+	if err != nil {
+		return err
+	}
+	c.IDToken = idToken
+	c.Expiry = expiry
+	if token.RefreshToken != "" {
+		c.RefreshToken = token.RefreshToken
+	}
+	return nil
+}
+
+func deviceLogin(ctx context.Context, config *oauth2.Config, out io.Writer) (*oauth2.Token, error) {
+	device, err := config.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device login: %w", err)
+	}
+	if device.VerificationURIComplete != "" {
+		_, _ = fmt.Fprintf(out, "Open %s to log in\n", device.VerificationURIComplete)
+	} else {
+		_, _ = fmt.Fprintf(out, "Open %s and enter the code %s to log in\n", device.VerificationURI, device.UserCode)
+	}
+	token, err := config.DeviceAccessToken(ctx, device)
+	if err != nil {
+		return nil, fmt.Errorf("device login failed: %w", err)
+	}
+	return token, nil
+}
+
+func browserLogin(ctx context.Context, config *oauth2.Config, out io.Writer) (*oauth2.Token, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the login redirect: %w", err)
+	}
+	defer func() { _ = listener.Close() }()
+	config.RedirectURL = fmt.Sprintf("http://%s/callback", listener.Addr())
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	server := &http.Server{
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/callback" {
+				http.NotFound(w, r)
+				return
+			}
+			query := r.URL.Query()
+			res := result{code: query.Get("code")}
+			switch {
+			case query.Get("state") != state:
+				res.err = errors.New("login redirect has an unexpected state")
+			case query.Get("error") != "":
+				res.err = fmt.Errorf("login failed: %s %s", query.Get("error"), query.Get("error_description"))
+			case res.code == "":
+				res.err = errors.New("login redirect has no authorization code")
+			}
+			if res.err != nil {
+				http.Error(w, res.err.Error(), http.StatusBadRequest)
+			} else {
+				_, _ = fmt.Fprintln(w, "Logged in, you can close this window and return to caib.")
+			}
+			select {
+			case results <- res:
+			default:
+			}
+		}),
+	}
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+
+	authURL := config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+	_, _ = fmt.Fprintf(out, "Open %s to log in\n", authURL)
+	openBrowser(authURL)
+
+	ctx, cancel := context.WithTimeout(ctx, browserLoginTimeout)
+	defer cancel()
+	select {
+	case res := <-results:
+		if res.err != nil {
+			return nil, res.err
+		}
+		token, err := config.Exchange(ctx, res.code, oauth2.VerifierOption(verifier))
+		if err != nil {
+			return nil, fmt.Errorf("failed to exchange the authorization code: %w", err)
+		}
+		return token, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for the browser login: %w", ctx.Err())
+	}
+}
+
+// openBrowser tries to open a URL in the user's browser, the URL is printed in case it cannot
+func openBrowser(url string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err == nil {
+		go func() { _ = cmd.Wait() }()
+	}
+}
+
+// idTokenExpiry reads the exp claim of an ID token; the build API verifies the token itself
+func idTokenExpiry(idToken string) (time.Time, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("malformed ID token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed ID token: %w", err)
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, errors.New("ID token has no expiry")
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate login state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func getJSON(ctx context.Context, url string, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
+
+// credentialsPath is the file caching the tokens of all servers caib logged in to
+func credentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the config directory: %w", err)
+	}
+	return filepath.Join(dir, "caib", "credentials.json"), nil
+}
+
+func loadCredentials() (map[string]*Credentials, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]*Credentials{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached credentials: %w", err)
+	}
+	store := map[string]*Credentials{}
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("failed to parse cached credentials %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// saveCredentials replaces the credentials of a server, nil removing them. The file is only
+// readable by the user as it holds refresh tokens.
+func saveCredentials(server string, creds *Credentials) error {
+	store, err := loadCredentials()
+	if err != nil {
+		return err
+	}
+	if creds == nil {
+		delete(store, server)
+	} else {
+		store[server] = creds
+	}
+
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cached credentials: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
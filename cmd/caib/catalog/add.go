@@ -36,6 +36,7 @@ var (
 	addTargets       []string
 	addTags          []string
 	addDigest        string
+	addPinDigest     bool
 	addAuthSecret    string
 	addBootc         bool
 )
@@ -56,6 +57,7 @@ func newAddCmd() *cobra.Command {
 	cmd.Flags().StringArrayVar(&addTargets, "target", nil, "Hardware targets (can be used multiple times)")
 	cmd.Flags().StringArrayVar(&addTags, "tags", nil, "Tags to apply (can be used multiple times)")
 	cmd.Flags().StringVar(&addDigest, "digest", "", "Specific digest to reference")
+	cmd.Flags().BoolVar(&addPinDigest, "pin-digest", false, "Pin the digest the tag first resolves to")
 	cmd.Flags().StringVar(&addAuthSecret, "auth-secret", "", "Secret containing registry credentials")
 	cmd.Flags().BoolVar(&addBootc, "bootc", false, "Mark as bootc-compatible")
 
@@ -75,6 +77,7 @@ type createRequest struct {
 	Name           string       `json:"name"`
 	RegistryURL    string       `json:"registryUrl"`
 	Digest         string       `json:"digest,omitempty"`
+	PinDigest      bool         `json:"pinDigest,omitempty"`
 	Tags           []string     `json:"tags,omitempty"`
 	AuthSecretName string       `json:"authSecretName,omitempty"`
 	Architecture   string       `json:"architecture,omitempty"`
@@ -87,6 +90,7 @@ type createRequest struct {
 type targetInfo struct {
 	Name     string `json:"name"`
 	Verified bool   `json:"verified"`
+	Notes    string `json:"notes,omitempty"`
 }
 
 func runAdd(_ *cobra.Command, args []string) error {
@@ -101,9 +105,9 @@ func runAdd(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("server URL required (use --server or CAIB_SERVER env var)")
 	}
 
-	token := authToken
-	if token == "" {
-		token = os.Getenv("CAIB_TOKEN")
+	token, err := resolveToken(server)
+	if err != nil {
+		return err
 	}
 
 	ns := namespace
@@ -118,6 +122,7 @@ func runAdd(_ *cobra.Command, args []string) error {
 		Name:           name,
 		RegistryURL:    registryURL,
 		Digest:         addDigest,
+		PinDigest:      addPinDigest,
 		Tags:           addTags,
 		AuthSecretName: addAuthSecret,
 		Architecture:   addArchitecture,
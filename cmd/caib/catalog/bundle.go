@@ -0,0 +1,226 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/v5/docker/reference"
+)
+
+const (
+	// bundleCatalogFile is the file in a catalog bundle listing the exported CatalogImages
+	bundleCatalogFile = "catalog.json"
+	// bundleVersion is the version of the catalog bundle format
+	bundleVersion = 1
+)
+
+// bundleCatalog lists the CatalogImages of a catalog bundle. Their content is stored in the bundle's
+// OCI layout under their layout reference.
+type bundleCatalog struct {
+	Version int           `json:"version"`
+	Images  []bundleImage `json:"images"`
+}
+
+// bundleImage is an exported CatalogImage, decoded from the catalog API response
+type bundleImage struct {
+	Name          string       `json:"name"`
+	Namespace     string       `json:"namespace,omitempty"`
+	LayoutRef     string       `json:"layoutRef,omitempty"`
+	RegistryURL   string       `json:"registryUrl"`
+	Digest        string       `json:"digest,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+	Architecture  string       `json:"architecture,omitempty"`
+	Distro        string       `json:"distro,omitempty"`
+	DistroVersion string       `json:"distroVersion,omitempty"`
+	Targets       []targetInfo `json:"targets,omitempty"`
+	Bootc         bool         `json:"bootc"`
+}
+
+// layoutReference returns the reference of the image's content in the bundle's OCI layout, falling
+// back to the image name for bundles that do not record it
+func (img *bundleImage) layoutReference() string {
+	if img.LayoutRef != "" {
+		return img.LayoutRef
+	}
+	return img.Name
+}
+
+// pinnedReference returns the registry reference of the image, pinned to its digest when known
+func (img *bundleImage) pinnedReference() (string, error) {
+	named, err := reference.ParseNormalizedNamed(img.RegistryURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid registry URL of %s: %w", img.Name, err)
+	}
+	if img.Digest == "" {
+		return reference.FamiliarString(reference.TagNameOnly(named)), nil
+	}
+	return reference.FamiliarName(named) + "@" + img.Digest, nil
+}
+
+// rewriteRegistryURL moves a registry URL to another registry, keeping its repository path and tag.
+// It returns the reference to push to and the registry URL of the imported image; images referenced
+// by digest only are pushed under a tag named after their digest.
+func rewriteRegistryURL(registryURL, registry string) (string, string, error) {
+	named, err := reference.ParseNormalizedNamed(registryURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid registry URL %q: %w", registryURL, err)
+	}
+	repo := strings.TrimSuffix(registry, "/") + "/" + reference.Path(named)
+
+	if tagged, ok := named.(reference.Tagged); ok {
+		ref := repo + ":" + tagged.Tag()
+		return ref, ref, nil
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		d := digested.Digest()
+		return fmt.Sprintf("%s:%s-%s", repo, d.Algorithm(), d.Encoded()), repo + "@" + d.String(), nil
+	}
+	ref := repo + ":latest"
+	return ref, ref, nil
+}
+
+// repinRegistryURL pins a registry URL referencing an image by digest to another digest. Tagged
+// registry URLs are returned unchanged.
+func repinRegistryURL(registryURL, digest string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(registryURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid registry URL %q: %w", registryURL, err)
+	}
+	if _, ok := named.(reference.Digested); !ok {
+		return registryURL, nil
+	}
+	if _, ok := named.(reference.Tagged); ok {
+		return registryURL, nil
+	}
+	return named.Name() + "@" + digest, nil
+}
+
+// writeBundle writes the directory holding the OCI layout and catalog file into a tar archive
+func writeBundle(dir, path string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create bundle: %w", err)
+	}
+	defer func() {
+		if err := out.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close bundle: %v\n", err)
+		}
+	}()
+
+	tw := tar.NewWriter(out)
+	err = filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, file)
+		if err != nil || name == "." {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	return tw.Close()
+}
+
+// extractBundle extracts a catalog bundle into dir and reads its catalog file
+func extractBundle(path, dir string) (*bundleCatalog, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open bundle: %w", err)
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	tr := tar.NewReader(in)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read bundle: %w", err)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return nil, fmt.Errorf("invalid path %q in bundle", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return nil, err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return nil, err
+			}
+			_, err = io.Copy(f, tr)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return nil, fmt.Errorf("extract %s: %w", header.Name, err)
+			}
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, bundleCatalogFile))
+	if err != nil {
+		return nil, fmt.Errorf("bundle has no %s: %w", bundleCatalogFile, err)
+	}
+	var catalog bundleCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", bundleCatalogFile, err)
+	}
+	if catalog.Version != bundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", catalog.Version)
+	}
+	return &catalog, nil
+}
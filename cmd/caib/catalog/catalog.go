@@ -17,7 +17,12 @@ limitations under the License.
 package catalog
 
 import (
+	"context"
+	"os"
+
 	"github.com/spf13/cobra"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/auth"
 )
 
 const (
@@ -46,14 +51,33 @@ func NewCatalogCmd() *cobra.Command {
 	cmd.AddCommand(newAddCmd())
 	cmd.AddCommand(newRemoveCmd())
 	cmd.AddCommand(newVerifyCmd())
+	cmd.AddCommand(newPromoteCmd())
+	cmd.AddCommand(newExportCmd())
+	cmd.AddCommand(newImportCmd())
 
 	return cmd
 }
 
 // addCommonFlags adds common flags to catalog subcommands
 func addCommonFlags(cmd *cobra.Command) {
+	addServerFlags(cmd)
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+}
+
+// addServerFlags adds the flags selecting the API server and namespace to catalog subcommands
+func addServerFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&serverURL, "server", "", "REST API server base URL (env: CAIB_SERVER)")
 	cmd.Flags().StringVar(&authToken, "token", "", "Bearer token for authentication (env: CAIB_TOKEN)")
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Kubernetes namespace")
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+}
+
+// resolveToken returns the bearer token for a server: the --token flag, CAIB_TOKEN or the cached caib login
+func resolveToken(server string) (string, error) {
+	if authToken != "" {
+		return authToken, nil
+	}
+	if token := os.Getenv("CAIB_TOKEN"); token != "" {
+		return token, nil
+	}
+	return auth.CachedToken(context.Background(), server)
 }
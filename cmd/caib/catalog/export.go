@@ -0,0 +1,192 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/spf13/cobra"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/ociutil"
+)
+
+var (
+	exportFilter string
+	exportOutput string
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export catalog images into a bundle for air-gapped sites",
+		Long: `Export the catalog images matching a query, together with their image content, into a tar archive
+holding an OCI layout and the catalog entries. Import the bundle on a disconnected site with
+'caib catalog import'. Registry credentials are read from REGISTRY_USERNAME and REGISTRY_PASSWORD,
+falling back to the Docker/Podman auth files.`,
+		Example: `  caib catalog export --filter "distro=autosd arch=arm64 channel=release" -o bundle.tar`,
+		Args:    cobra.NoArgs,
+		RunE:    runExport,
+	}
+
+	addServerFlags(cmd)
+	cmd.Flags().StringVar(&exportFilter, "filter", "", "Catalog query selecting the images to export")
+	cmd.Flags().StringVarP(&exportOutput, "output", "o", "catalog-bundle.tar", "Bundle file to write")
+
+	return cmd
+}
+
+func runExport(_ *cobra.Command, _ []string) error {
+	server := serverURL
+	if server == "" {
+		server = os.Getenv("CAIB_SERVER")
+	}
+	if server == "" {
+		return fmt.Errorf("server URL required (use --server or CAIB_SERVER env var)")
+	}
+
+	token, err := resolveToken(server)
+	if err != nil {
+		return err
+	}
+
+	images, err := listBundleImages(server, token)
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		return fmt.Errorf("no catalog images match the filter")
+	}
+
+	layoutDir, err := os.MkdirTemp("", "caib-export-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(layoutDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove temp directory: %v\n", err)
+		}
+	}()
+
+	ctx := context.Background()
+	systemCtx := ociutil.SystemContext(os.Getenv("REGISTRY_USERNAME"), os.Getenv("REGISTRY_PASSWORD"))
+	for i := range images {
+		img := &images[i]
+		source, err := img.pinnedReference()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Exporting %s from %s\n", img.Name, source)
+
+		srcRef, err := docker.ParseReference("//" + source)
+		if err != nil {
+			return fmt.Errorf("parse source reference: %w", err)
+		}
+		// Same-named images of different namespaces must not overwrite each other in the layout
+		img.LayoutRef = path.Join(img.Namespace, img.Name)
+		destRef, err := layout.NewReference(layoutDir, img.LayoutRef)
+		if err != nil {
+			return fmt.Errorf("parse destination reference: %w", err)
+		}
+		if _, err := ociutil.CopyImage(ctx, destRef, srcRef, &copy.Options{
+			ReportWriter:       os.Stdout,
+			SourceCtx:          systemCtx,
+			ImageListSelection: copy.CopyAllImages,
+		}); err != nil {
+			return fmt.Errorf("export %s: %w", img.Name, err)
+		}
+	}
+
+	data, err := json.MarshalIndent(bundleCatalog{Version: bundleVersion, Images: images}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal catalog: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(layoutDir, bundleCatalogFile), data, 0644); err != nil {
+		return fmt.Errorf("write catalog: %w", err)
+	}
+	if err := writeBundle(layoutDir, exportOutput); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d catalog images to %s\n", len(images), exportOutput)
+	return nil
+}
+
+// listBundleImages lists all catalog images matching the export filter, following continue tokens
+func listBundleImages(server, token string) ([]bundleImage, error) {
+	var images []bundleImage
+	continueToken := ""
+	for {
+		params := url.Values{}
+		if namespace != "" {
+			params.Set("namespace", namespace)
+		}
+		if exportFilter != "" {
+			params.Set("q", exportFilter)
+		}
+		if continueToken != "" {
+			params.Set("continue", continueToken)
+		}
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/catalog/images?%s", server, params.Encode()), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to make request: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", closeErr)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var page struct {
+			Items    []bundleImage `json:"items"`
+			Continue string        `json:"continue,omitempty"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		images = append(images, page.Items...)
+
+		continueToken = page.Continue
+		if continueToken == "" {
+			return images, nil
+		}
+	}
+}
@@ -22,6 +22,8 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -51,9 +53,9 @@ func runGet(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("server URL required (use --server or CAIB_SERVER env var)")
 	}
 
-	token := authToken
-	if token == "" {
-		token = os.Getenv("CAIB_TOKEN")
+	token, err := resolveToken(server)
+	if err != nil {
+		return err
 	}
 
 	ns := namespace
@@ -97,6 +99,8 @@ func runGet(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to read response: %w", err)
 	}
 
+	printLifecycleWarning(resp.Header, body)
+
 	switch outputFormat {
 	case "json":
 		var result map[string]interface{}
@@ -116,3 +120,24 @@ func runGet(_ *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// printLifecycleWarning warns on stderr when the image is deprecated or end-of-life
+func printLifecycleWarning(header http.Header, body []byte) {
+	if warning := header.Get("Warning"); warning != "" {
+		if _, text, ok := strings.Cut(warning, "- "); ok {
+			if unquoted, err := strconv.Unquote(text); err == nil {
+				text = unquoted
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", text)
+			return
+		}
+	}
+
+	var img struct {
+		Lifecycle  string `json:"lifecycle"`
+		ReplacedBy string `json:"replacedBy"`
+	}
+	if json.Unmarshal(body, &img) == nil && img.Lifecycle != "" && img.Lifecycle != "Active" {
+		fmt.Fprintf(os.Stderr, "Warning: catalog image is %s\n", img.Lifecycle)
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/spf13/cobra"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/ociutil"
+)
+
+var importRegistry string
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <bundle.tar>",
+		Short: "Import a catalog bundle into a local registry and catalog",
+		Long: `Import a bundle written by 'caib catalog export': push its images to a local registry, keeping
+their repository paths and tags, and recreate the catalog images with the rewritten registry URLs.
+Registry credentials are read from REGISTRY_USERNAME and REGISTRY_PASSWORD, falling back to the
+Docker/Podman auth files. Catalog images that already exist are skipped.`,
+		Example: `  caib catalog import bundle.tar --registry local.example:5000`,
+		Args:    cobra.ExactArgs(1),
+		RunE:    runImport,
+	}
+
+	addServerFlags(cmd)
+	cmd.Flags().StringVar(&importRegistry, "registry", "", "Registry to push the bundle's images to")
+	if err := cmd.MarkFlagRequired("registry"); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to mark required flag 'registry': %v\n", err)
+		os.Exit(1)
+	}
+
+	return cmd
+}
+
+func runImport(_ *cobra.Command, args []string) error {
+	server := serverURL
+	if server == "" {
+		server = os.Getenv("CAIB_SERVER")
+	}
+	if server == "" {
+		return fmt.Errorf("server URL required (use --server or CAIB_SERVER env var)")
+	}
+
+	token, err := resolveToken(server)
+	if err != nil {
+		return err
+	}
+
+	ns := namespace
+	if ns == "" {
+		ns = defaultNamespace
+	}
+
+	layoutDir, err := os.MkdirTemp("", "caib-import-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(layoutDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove temp directory: %v\n", err)
+		}
+	}()
+
+	bundle, err := extractBundle(args[0], layoutDir)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	systemCtx := ociutil.SystemContext(os.Getenv("REGISTRY_USERNAME"), os.Getenv("REGISTRY_PASSWORD"))
+	imported := 0
+	for i := range bundle.Images {
+		img := &bundle.Images[i]
+		pushRef, registryURL, err := rewriteRegistryURL(img.RegistryURL, importRegistry)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Importing %s to %s\n", img.Name, registryURL)
+
+		srcRef, err := layout.NewReference(layoutDir, img.layoutReference())
+		if err != nil {
+			return fmt.Errorf("parse source reference: %w", err)
+		}
+		destRef, err := docker.ParseReference("//" + pushRef)
+		if err != nil {
+			return fmt.Errorf("parse destination reference: %w", err)
+		}
+		manifestBytes, err := ociutil.CopyImage(ctx, destRef, srcRef, &copy.Options{
+			ReportWriter:       os.Stdout,
+			DestinationCtx:     systemCtx,
+			ImageListSelection: copy.CopyAllImages,
+		})
+		if err != nil {
+			return fmt.Errorf("import %s: %w", img.Name, err)
+		}
+
+		// Docker images were converted to OCI on export, so the pushed manifest may have a
+		// different digest than the exported image
+		pushed, err := manifest.Digest(manifestBytes)
+		if err != nil {
+			return fmt.Errorf("compute digest of %s: %w", img.Name, err)
+		}
+		if img.Digest != "" && pushed.String() != img.Digest {
+			fmt.Printf("Image %s was converted to OCI, digest changed from %s to %s\n", img.Name, img.Digest, pushed)
+		}
+		registryURL, err = repinRegistryURL(registryURL, pushed.String())
+		if err != nil {
+			return err
+		}
+
+		created, err := createImportedImage(server, token, ns, img, registryURL, pushed.String())
+		if err != nil {
+			return fmt.Errorf("create catalog image %s: %w", img.Name, err)
+		}
+		if !created {
+			fmt.Printf("Catalog image %s already exists, skipping\n", img.Name)
+			continue
+		}
+		imported++
+	}
+
+	fmt.Printf("Imported %d of %d catalog images into namespace %s\n", imported, len(bundle.Images), ns)
+	return nil
+}
+
+// createImportedImage creates the catalog image for an imported bundle image at the digest it was
+// pushed with. It reports false if the catalog image already exists.
+func createImportedImage(server, token, ns string, img *bundleImage, registryURL, digest string) (bool, error) {
+	bodyBytes, err := json.Marshal(createRequest{
+		Name:          img.Name,
+		RegistryURL:   registryURL,
+		Digest:        digest,
+		Tags:          img.Tags,
+		Architecture:  img.Architecture,
+		Distro:        img.Distro,
+		DistroVersion: img.DistroVersion,
+		Targets:       img.Targets,
+		Bootc:         img.Bootc,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	reqURL := fmt.Sprintf("%s/v1/catalog/images?namespace=%s", server, ns)
+	req, err := http.NewRequest(http.MethodPost, reqURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", err)
+		}
+	}()
+
+	body, _ := io.ReadAll(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return true, nil
+	case http.StatusConflict:
+		return false, nil
+	default:
+		return false, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
@@ -35,16 +36,35 @@ var (
 	listTarget        string
 	listPhase         string
 	listTags          string
+	listChannel       string
+	listSort          string
+	listContinue      string
+	listIncludeOld    bool
 	listLimit         int
 	listAllNamespaces bool
 )
 
 func newListCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "list",
+		Use:   "list [query...]",
 		Short: "List images in the catalog",
-		Long:  `List images in the catalog with optional filtering by architecture, distribution, target, and phase.`,
-		RunE:  runList,
+		Long: `List images in the catalog with optional filtering by architecture, distribution, target, and phase.
+Deprecated and end-of-life images are hidden unless --include-deprecated is set or the query filters
+on lifecycle.
+
+Arguments form a query evaluated by the server. Each term is field<op>value, where op is one of
+= != ~ (contains) !~ > >= < <=, and field:value is short for field=value. Terms without a field
+match text in the name, registry URL, metadata, tags and channel. All terms must match.
+
+Fields: name, namespace, registry, digest, tag, arch, distro, distroversion, target, mode, format,
+bootc, multiarch, phase, channel, lifecycle, source, promotedby, approvedby, published, verified, created,
+size, layers, accesses
+
+Quote terms using < or > so the shell does not treat them as redirections.`,
+		Example: `  caib catalog list distro=autosd arch=arm64 target~rpi tag:release 'published>2026-01-01'
+  caib catalog list 'size<2Gi' --sort -published
+  caib catalog list "firmware 1.2" --sort -accesses --limit 5`,
+		RunE: runList,
 	}
 
 	addCommonFlags(cmd)
@@ -53,6 +73,11 @@ func newListCmd() *cobra.Command {
 	cmd.Flags().StringVar(&listTarget, "target", "", "Filter by hardware target (qemu, raspberry-pi)")
 	cmd.Flags().StringVar(&listPhase, "phase", "", "Filter by phase (Available, Unavailable, etc)")
 	cmd.Flags().StringVar(&listTags, "tags", "", "Filter by tags (comma-separated)")
+	cmd.Flags().StringVar(&listChannel, "channel", "", "Filter by promotion channel (dev, qa, release)")
+	cmd.Flags().StringVar(&listSort, "sort", "",
+		"Sort by name, published, created, size or accesses; prefix with - for descending")
+	cmd.Flags().StringVar(&listContinue, "continue", "", "Continue token from a previous listing")
+	cmd.Flags().BoolVar(&listIncludeOld, "include-deprecated", false, "Include deprecated and end-of-life images")
 	cmd.Flags().IntVar(&listLimit, "limit", 20, "Maximum results to show")
 	cmd.Flags().BoolVar(&listAllNamespaces, "all-namespaces", false, "List images across all namespaces")
 
@@ -81,6 +106,7 @@ type CatalogImageResponse struct {
 	Targets      []Target `json:"targets,omitempty"`
 	SizeBytes    int64    `json:"sizeBytes,omitempty"`
 	CreatedAt    string   `json:"createdAt"`
+	Lifecycle    string   `json:"lifecycle,omitempty"`
 }
 
 // Target mirrors target info from API
@@ -88,7 +114,7 @@ type Target struct {
 	Name string `json:"name"`
 }
 
-func runList(_ *cobra.Command, _ []string) error {
+func runList(_ *cobra.Command, args []string) error {
 	// Get server URL
 	server := serverURL
 	if server == "" {
@@ -98,10 +124,9 @@ func runList(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("server URL required (use --server or CAIB_SERVER env var)")
 	}
 
-	// Get auth token
-	token := authToken
-	if token == "" {
-		token = os.Getenv("CAIB_TOKEN")
+	token, err := resolveToken(server)
+	if err != nil {
+		return err
 	}
 
 	// Build query parameters
@@ -124,6 +149,22 @@ func runList(_ *cobra.Command, _ []string) error {
 	if listTags != "" {
 		params.Set("tags", listTags)
 	}
+	if listChannel != "" {
+		params.Set("channel", listChannel)
+	}
+	terms := quoteQueryTerms(args)
+	if !listIncludeOld && !mentionsLifecycle(terms) {
+		terms = append(terms, "lifecycle=Active")
+	}
+	if query := strings.Join(terms, " "); query != "" {
+		params.Set("q", query)
+	}
+	if listSort != "" {
+		params.Set("sort", listSort)
+	}
+	if listContinue != "" {
+		params.Set("continue", listContinue)
+	}
 	if listLimit > 0 {
 		params.Set("limit", fmt.Sprintf("%d", listLimit))
 	}
@@ -179,11 +220,37 @@ func runList(_ *cobra.Command, _ []string) error {
 		fmt.Println(string(output))
 	default:
 		printTable(result.Items)
+		if result.Continue != "" {
+			fmt.Printf("\nShowing %d of %d images. Next page: --continue %s\n",
+				len(result.Items), result.Total, result.Continue)
+		}
 	}
 
 	return nil
 }
 
+// mentionsLifecycle reports whether the query filters on the lifecycle field itself
+func mentionsLifecycle(terms []string) bool {
+	for _, term := range terms {
+		if strings.HasPrefix(strings.ToLower(strings.Trim(term, `"`)), "lifecycle") {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteQueryTerms quotes arguments containing whitespace so that each stays a single query term
+func quoteQueryTerms(args []string) []string {
+	terms := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.ContainsAny(arg, " \t") && !strings.Contains(arg, `"`) {
+			arg = `"` + arg + `"`
+		}
+		terms = append(terms, arg)
+	}
+	return terms
+}
+
 func printTable(items []CatalogImageResponse) {
 	if len(items) == 0 {
 		fmt.Println("No catalog images found")
@@ -214,13 +281,18 @@ func printTable(items []CatalogImageResponse) {
 			registryDisplay = registryDisplay[:47] + "..."
 		}
 
+		phase := img.Phase
+		if img.Lifecycle != "" && img.Lifecycle != "Active" {
+			phase += " (" + img.Lifecycle + ")"
+		}
+
 		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			img.Name,
 			registryDisplay,
 			img.Architecture,
 			img.Distro,
 			target,
-			img.Phase,
+			phase,
 			img.CreatedAt,
 		); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to write row: %v\n", err)
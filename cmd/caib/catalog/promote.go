@@ -0,0 +1,162 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	promoteChannel string
+	promoteName    string
+)
+
+func newPromoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote <name> --to <channel>",
+		Short: "Promote a catalog image into a channel",
+		Long: `Promote a catalog image into a promotion channel defined in the OperatorConfig.
+
+The image is copied to the channel's registry repository by the server and published as a new
+catalog image that records its promotion history. Promotions into protected channels must be
+approved by a second user, who approves by running the same command.`,
+		Example: `  # Promote to the qa channel
+  caib catalog promote autosd-qemu --to qa
+
+  # Request promotion of the qa image to the protected release channel;
+  # a second user then runs the same command to approve it
+  caib catalog promote autosd-qemu-qa --to release`,
+		Args: cobra.ExactArgs(1),
+		RunE: runPromote,
+	}
+
+	addCommonFlags(cmd)
+	cmd.Flags().StringVar(&promoteChannel, "to", "", "Channel to promote the image into (required)")
+	cmd.Flags().StringVar(&promoteName, "name", "",
+		"Name of the promoted catalog image (default: <name>-<channel>)")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+type promoteResponse struct {
+	Message  string                `json:"message"`
+	Pending  bool                  `json:"pending"`
+	Image    *CatalogImageResponse `json:"image,omitempty"`
+	Approval *struct {
+		Channel     string `json:"channel"`
+		TargetName  string `json:"targetName,omitempty"`
+		Digest      string `json:"digest,omitempty"`
+		RequestedBy string `json:"requestedBy"`
+	} `json:"approval,omitempty"`
+}
+
+func runPromote(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	server := serverURL
+	if server == "" {
+		server = os.Getenv("CAIB_SERVER")
+	}
+	if server == "" {
+		return fmt.Errorf("server URL required (use --server or CAIB_SERVER env var)")
+	}
+
+	token, err := resolveToken(server)
+	if err != nil {
+		return err
+	}
+
+	ns := namespace
+	if ns == "" {
+		ns = defaultNamespace
+	}
+
+	reqBody, err := json.Marshal(map[string]string{
+		"channel": promoteChannel,
+		"name":    promoteName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	fmt.Printf("Promoting catalog image %q to channel %q...\n", name, promoteChannel)
+
+	reqURL := fmt.Sprintf("%s/v1/catalog/images/%s/promote?namespace=%s", server, name, ns)
+	req, err := http.NewRequest(http.MethodPost, reqURL, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", err)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("catalog image %q not found in namespace %q", name, ns)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result promoteResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if result.Pending {
+		fmt.Printf("Promotion pending: %s\n", result.Message)
+		if result.Approval != nil {
+			fmt.Printf("Requested by:  %s\n", result.Approval.RequestedBy)
+			fmt.Printf("Target name:   %s\n", result.Approval.TargetName)
+			if result.Approval.Digest != "" {
+				fmt.Printf("Digest:        %s\n", result.Approval.Digest)
+			}
+		}
+		fmt.Printf("Another user can approve with: caib catalog promote %s --to %s -n %s\n", name, promoteChannel, ns)
+		return nil
+	}
+
+	fmt.Printf("✓ %s\n", result.Message)
+	if result.Image != nil {
+		fmt.Printf("Registry URL:  %s\n", result.Image.RegistryURL)
+		fmt.Printf("Status:        %s\n", result.Image.Phase)
+	}
+	return nil
+}
@@ -57,9 +57,9 @@ func runRemove(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("server URL required (use --server or CAIB_SERVER env var)")
 	}
 
-	token := authToken
-	if token == "" {
-		token = os.Getenv("CAIB_TOKEN")
+	token, err := resolveToken(server)
+	if err != nil {
+		return err
 	}
 
 	ns := namespace
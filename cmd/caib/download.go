@@ -0,0 +1,441 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+const (
+	// headerArtifactSHA256 carries the build-time SHA-256 checksum of the artifact
+	headerArtifactSHA256 = "X-AIB-Artifact-SHA256"
+
+	// maxDownloadRetries bounds how often an interrupted download is resumed automatically
+	maxDownloadRetries = 5
+)
+
+// resumeRequest asks the download loop to request the rest of a partially downloaded file
+type resumeRequest struct {
+	path   string
+	offset int64
+	etag   string
+}
+
+func (r *resumeRequest) Error() string {
+	return fmt.Sprintf("resume %s at byte %d", r.path, r.offset)
+}
+
+// downloadInterruptedError reports a transfer that broke off after some data was written.
+// The partial file is kept so the download can resume.
+type downloadInterruptedError struct {
+	err    error
+	resume *resumeRequest
+}
+
+func (e *downloadInterruptedError) Error() string {
+	return fmt.Sprintf("download interrupted: %v", e.err)
+}
+
+func (e *downloadInterruptedError) Unwrap() error {
+	return e.err
+}
+
+// etagFile returns the path of the sidecar file that records the ETag of a partial download
+func etagFile(partialPath string) string {
+	return partialPath + ".etag"
+}
+
+// discardPartial removes a partial download and its ETag sidecar
+func discardPartial(partialPath string) {
+	_ = os.Remove(partialPath)
+	_ = os.Remove(etagFile(partialPath))
+}
+
+// partialResumeRequest returns a resume request if a partial file for the same artifact
+// version exists and the server supports byte ranges, or nil to start over
+func partialResumeRequest(resp *http.Response, partialPath string) *resumeRequest {
+	etag := strings.TrimSpace(resp.Header.Get("ETag"))
+	if etag == "" || !strings.EqualFold(strings.TrimSpace(resp.Header.Get("Accept-Ranges")), "bytes") {
+		return nil
+	}
+	info, err := os.Stat(partialPath)
+	if err != nil || info.Size() == 0 {
+		return nil
+	}
+	saved, err := os.ReadFile(etagFile(partialPath))
+	if err != nil || strings.TrimSpace(string(saved)) != etag {
+		return nil
+	}
+	return &resumeRequest{path: partialPath, offset: info.Size(), etag: etag}
+}
+
+// openPartialForResponse opens the partial file matching the response: appending for a
+// 206 that continues the file, or truncating for a full 200 response. The returned offset
+// is the number of bytes already present.
+func openPartialForResponse(resp *http.Response, partialPath string) (*os.File, int64, error) {
+	if resp.StatusCode == http.StatusPartialContent {
+		start, err := contentRangeStart(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return nil, 0, err
+		}
+		info, err := os.Stat(partialPath)
+		if err != nil || info.Size() != start {
+			return nil, 0, fmt.Errorf("server resumed at byte %d, which does not match the partial file", start)
+		}
+		f, err := os.OpenFile(partialPath, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			return nil, 0, err
+		}
+		fmt.Printf("Resuming download at %d bytes\n", start)
+		return f, start, nil
+	}
+
+	f, err := os.Create(partialPath)
+	if err != nil {
+		return nil, 0, err
+	}
+	if etag := strings.TrimSpace(resp.Header.Get("ETag")); etag != "" {
+		if err := os.WriteFile(etagFile(partialPath), []byte(etag), 0o600); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record ETag, download will not be resumable: %v\n", err)
+		}
+	}
+	return f, 0, nil
+}
+
+// contentRangeStart returns the first byte position of a "bytes start-end/size" header
+func contentRangeStart(header string) (int64, error) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes ")
+	first, _, found := strings.Cut(spec, "-")
+	if !ok || !found {
+		return 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+	return strconv.ParseInt(first, 10, 64)
+}
+
+// verifyFileChecksum compares the SHA-256 of a file with the expected hex digest
+func verifyFileChecksum(filePath, expected string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close file: %v\n", err)
+		}
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("checksum: %w", err)
+	}
+	actual := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return fmt.Errorf("checksum mismatch: expected sha256 %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// parallelChunkSize is the largest byte range fetched by a single request in parallel downloads.
+// Smaller chunks keep retries cheap and spread the tail of the transfer across workers.
+const parallelChunkSize = 64 << 20
+
+// artifactProbe describes the artifact as reported by a one-byte range request
+type artifactProbe struct {
+	resp *http.Response
+	size int64
+	etag string
+}
+
+// probeArtifact requests the first byte of the artifact to learn its size and entity tag,
+// waiting for the artifact to become ready. A nil probe means the server did not answer
+// with a byte range and the artifact must be downloaded as a single stream.
+func probeArtifact(ctx context.Context, httpClient *http.Client, urlStr string, deadline time.Time) (*artifactProbe, error) {
+	warned := false
+	for {
+		if ctx.Err() != nil || time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for artifact to become ready")
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
+		setAuthHeader(req)
+		req.Header.Set("Range", "bytes=0-0")
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			time.Sleep(3 * time.Second)
+			continue
+		}
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		_ = resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusPartialContent:
+			size, err := contentRangeSize(resp.Header.Get("Content-Range"))
+			if err != nil {
+				return nil, err
+			}
+			etag := strings.TrimSpace(resp.Header.Get("ETag"))
+			if etag == "" {
+				return nil, nil
+			}
+			return &artifactProbe{resp: resp, size: size, etag: etag}, nil
+		case resp.StatusCode == http.StatusOK:
+			return nil, nil
+		case shouldRetryResponse(resp.StatusCode, body):
+			if !warned {
+				fmt.Println("Artifact not ready yet. Waiting...")
+				warned = true
+			}
+			time.Sleep(3 * time.Second)
+			continue
+		default:
+			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+	}
+}
+
+// downloadArtifactParallel fetches the artifact as concurrent byte ranges written into a
+// preallocated file. Servers that do not support ranges for the artifact, such as
+// directory artifacts streamed as tar archives, fall back to a single-stream download.
+func downloadArtifactParallel(ctx context.Context, baseURL, name, outPath string, workers int) error {
+	outDir, userFilename := parseOutputPath(outPath)
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+
+	urlStr := withNamespace(strings.TrimRight(baseURL, "/") + "/v1/builds/" + url.PathEscape(name) + "/artifact")
+	httpClient := &http.Client{
+		Timeout: 30 * time.Minute,
+		Transport: newRetryTransport(&http.Transport{
+			ResponseHeaderTimeout: 2 * time.Minute,
+			IdleConnTimeout:       5 * time.Minute,
+			MaxConnsPerHost:       workers,
+			MaxIdleConnsPerHost:   workers,
+		}),
+	}
+
+	probe, err := probeArtifact(ctx, httpClient, urlStr, time.Now().Add(30*time.Minute))
+	if err != nil {
+		return err
+	}
+	if probe == nil || probe.size < 2 {
+		fmt.Println("Server does not support ranged downloads for this artifact, downloading as a single stream")
+		return downloadArtifactViaAPI(ctx, baseURL, name, outPath)
+	}
+
+	filename := resolveFilename(userFilename, extractServerFilename(probe.resp),
+		strings.TrimSpace(probe.resp.Header.Get("X-AIB-Compression")), name+".artifact")
+	finalPath := filepath.Join(outDir, filename)
+	tmp := finalPath + ".partial"
+
+	printArtifactMetadata(probe.resp)
+
+	// Chunks are written out of order, so a partial file from an earlier run cannot be resumed
+	discardPartial(tmp)
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(probe.size); err != nil {
+		_ = f.Close()
+		discardPartial(tmp)
+		return fmt.Errorf("preallocate %s: %w", tmp, err)
+	}
+
+	chunk := max(min((probe.size+int64(workers)-1)/int64(workers), parallelChunkSize), 1)
+	fmt.Printf("Downloading %d bytes with %d parallel connections\n", probe.size, workers)
+
+	bar := progressbar.NewOptions64(
+		probe.size,
+		progressbar.OptionSetDescription("Downloading"),
+		progressbar.OptionShowBytes(true),
+		progressbar.OptionSetWidth(15),
+		progressbar.OptionThrottle(65*time.Millisecond),
+		progressbar.OptionShowCount(),
+		progressbar.OptionClearOnFinish(),
+	)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ranges := make(chan byteRange)
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range ranges {
+				if err := fetchRange(ctx, httpClient, urlStr, probe.etag, f, bar, r); err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+sendLoop:
+	for start := int64(0); start < probe.size; start += chunk {
+		select {
+		case ranges <- byteRange{start: start, length: min(chunk, probe.size-start)}:
+		case <-ctx.Done():
+			break sendLoop
+		}
+	}
+	close(ranges)
+	wg.Wait()
+	close(errs)
+
+	closeErr := f.Close()
+	if err := <-errs; err != nil {
+		discardPartial(tmp)
+		return err
+	}
+	if closeErr != nil {
+		discardPartial(tmp)
+		return fmt.Errorf("failed to close file: %w", closeErr)
+	}
+	_ = bar.Finish()
+	fmt.Println()
+
+	if sum := strings.TrimSpace(probe.resp.Header.Get(headerArtifactSHA256)); sum != "" {
+		fmt.Println("Verifying checksum...")
+		if err := verifyFileChecksum(tmp, sum); err != nil {
+			discardPartial(tmp)
+			return err
+		}
+		fmt.Printf("Checksum verified (sha256:%s)\n", sum)
+	}
+
+	if err := os.Rename(tmp, finalPath); err != nil {
+		return err
+	}
+	fmt.Printf("Artifact downloaded to %s\n", finalPath)
+
+	return extractIfTarArchive(probe.resp.Header.Get("Content-Type"), finalPath)
+}
+
+// byteRange is a contiguous part of the artifact
+type byteRange struct {
+	start  int64
+	length int64
+}
+
+// fetchRange downloads one byte range into f at its offset. Interrupted transfers are
+// retried from the last byte written, so progress is never counted twice.
+func fetchRange(
+	ctx context.Context, httpClient *http.Client, urlStr, etag string,
+	f *os.File, bar *progressbar.ProgressBar, r byteRange,
+) error {
+	var lastErr error
+	for attempt := 0; attempt <= maxDownloadRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(3 * time.Second):
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		n, err := fetchRangeOnce(ctx, httpClient, urlStr, etag, f, bar, r)
+		r.start += n
+		r.length -= n
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentRangeError
+		if errors.As(err, &permanent) {
+			return err
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("bytes %d-%d: %w", r.start, r.start+r.length-1, lastErr)
+}
+
+// permanentRangeError reports a range response that retrying cannot fix, such as the
+// artifact changing while it is downloaded
+type permanentRangeError struct {
+	msg string
+}
+
+func (e *permanentRangeError) Error() string {
+	return e.msg
+}
+
+// fetchRangeOnce issues a single range request and returns the number of bytes written
+func fetchRangeOnce(
+	ctx context.Context, httpClient *http.Client, urlStr, etag string,
+	f *os.File, bar *progressbar.ProgressBar, r byteRange,
+) (int64, error) {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
+	setAuthHeader(req)
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", r.start, r.start+r.length-1))
+	req.Header.Set("If-Range", etag)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		return 0, &permanentRangeError{msg: "artifact changed during download, please retry"}
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return 0, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		return 0, &permanentRangeError{
+			msg: fmt.Sprintf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body))),
+		}
+	}
+
+	if start, err := contentRangeStart(resp.Header.Get("Content-Range")); err != nil || start != r.start {
+		return 0, &permanentRangeError{msg: fmt.Sprintf("unexpected Content-Range %q", resp.Header.Get("Content-Range"))}
+	}
+
+	w := io.NewOffsetWriter(f, r.start)
+	n, err := io.Copy(io.MultiWriter(w, bar), io.LimitReader(resp.Body, r.length))
+	if err == nil && n < r.length {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// contentRangeSize returns the complete length from a "bytes start-end/size" header
+func contentRangeSize(header string) (int64, error) {
+	_, size, ok := strings.Cut(strings.TrimSpace(header), "/")
+	if !ok || size == "*" {
+		return 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+	return strconv.ParseInt(size, 10, 64)
+}
+
+// setAuthHeader adds the bearer token, if any, to a build API request
+func setAuthHeader(req *http.Request) {
+	if strings.TrimSpace(authToken) != "" {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(authToken))
+	}
+}
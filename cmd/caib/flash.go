@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+var (
+	flashSelector      string
+	flashImageRef      string
+	flashLeaseDuration time.Duration
+	flashFollow        bool
+)
+
+// newFlashCmd creates the command flashing a completed build onto a Jumpstarter device
+func newFlashCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flash <build-name>",
+		Short: "Flash a completed build onto a device leased from Jumpstarter",
+		Long: `Flash the image of a completed build onto a test device managed by Jumpstarter.
+
+The operator leases an exporter of the build's target, runs the flash command against it and releases
+the lease when the flash ends. The exporters and the flash command are set by the Jumpstarter target
+mapping of the build's target in the OperatorConfig; --selector only narrows the exporters further.`,
+		Example: `  # Flash onto any exporter of the build's target
+  caib flash qm-minimal
+
+  # Flash onto an exporter in a specific lab, keeping the lease for at most an hour
+  caib flash qm-minimal --selector lab=lab-1 --lease-duration 1h`,
+		Args: cobra.ExactArgs(1),
+		Run:  runFlash,
+	}
+	cmd.Flags().StringVar(&serverURL, "server", os.Getenv("CAIB_SERVER"), "REST API server base URL")
+	cmd.Flags().StringVar(&authToken, "token", os.Getenv("CAIB_TOKEN"), "Bearer token for authentication")
+	cmd.Flags().StringVar(&flashSelector, "selector", "", "label requirements narrowing the exporters to lease")
+	cmd.Flags().StringVar(&flashImageRef, "image", "", "image to flash (defaults to the build's OCI image or artifact)")
+	cmd.Flags().DurationVar(&flashLeaseDuration, "lease-duration", 0, "how long to lease the exporter (default 30m)")
+	cmd.Flags().BoolVarP(&flashFollow, "follow", "f", true, "follow flash logs")
+	addNamespaceFlag(cmd)
+	return cmd
+}
+
+func runFlash(_ *cobra.Command, args []string) {
+	if strings.TrimSpace(serverURL) == "" {
+		handleError(fmt.Errorf("--server is required (or set CAIB_SERVER env)"))
+	}
+	if flashLeaseDuration < 0 || flashLeaseDuration%time.Minute != 0 {
+		handleError(fmt.Errorf("--lease-duration must be a whole number of minutes"))
+	}
+
+	api, err := createBuildAPIClient(serverURL, &authToken)
+	if err != nil {
+		handleError(err)
+	}
+	ctx := context.Background()
+	job, err := api.FlashBuild(ctx, args[0], buildapi.FlashRequest{
+		ExporterSelector:     flashSelector,
+		ImageRef:             flashImageRef,
+		LeaseDurationMinutes: int32(flashLeaseDuration / time.Minute),
+	})
+	if err != nil {
+		handleError(err)
+	}
+	fmt.Printf("Flash job %s created for build %s\n", job.Name, job.BuildName)
+	if !flashFollow {
+		return
+	}
+
+	if logs, err := api.FlashJobLogs(ctx, job.Name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not follow flash logs: %v\n", err)
+	} else {
+		_, _ = io.Copy(os.Stdout, logs)
+		_ = logs.Close()
+	}
+
+	// The log stream ends with the flash pod; wait for the outcome to be recorded
+	for {
+		job, err = api.GetFlashJob(ctx, job.Name)
+		if err != nil {
+			handleError(err)
+		}
+		if job.Phase == "Succeeded" || job.Phase == "Failed" {
+			break
+		}
+		time.Sleep(3 * time.Second)
+	}
+
+	fmt.Printf("\nFlash %s: %s\n", strings.ToLower(job.Phase), job.Message)
+	if job.ExporterName != "" {
+		fmt.Printf("Exporter: %s\n", job.ExporterName)
+	}
+	if job.Phase == "Failed" {
+		os.Exit(1)
+	}
+}
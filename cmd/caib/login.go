@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/auth"
+)
+
+var loginBrowser bool
+
+// newLoginCmd creates the command logging in to the build API with its OpenID provider
+func newLoginCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in to the build API with its OpenID provider",
+		Long: `Log in to a build API server configured with an OpenID Connect provider, such as Keycloak.
+
+Providers supporting it use the device code flow: caib prints a URL and code to enter in any browser.
+Otherwise, or with --browser, caib opens a browser and waits for the login redirect on localhost.
+
+The tokens are cached in the caib config directory and refreshed automatically, so later commands
+against the same server need no --token.`,
+		Args: cobra.NoArgs,
+		Run:  runLogin,
+	}
+	cmd.Flags().StringVar(&serverURL, "server", os.Getenv("CAIB_SERVER"), "REST API server base URL")
+	cmd.Flags().BoolVar(&loginBrowser, "browser", false, "log in with a browser redirect instead of a device code")
+	return cmd
+}
+
+// newLogoutCmd creates the command forgetting the cached login of a build API server
+func newLogoutCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Forget the cached login of the build API",
+		Args:  cobra.NoArgs,
+		Run:   runLogout,
+	}
+	cmd.Flags().StringVar(&serverURL, "server", os.Getenv("CAIB_SERVER"), "REST API server base URL")
+	return cmd
+}
+
+func runLogin(_ *cobra.Command, _ []string) {
+	if strings.TrimSpace(serverURL) == "" {
+		handleError(fmt.Errorf("--server is required (or set CAIB_SERVER env)"))
+	}
+	creds, err := auth.Login(context.Background(), serverURL, auth.LoginOptions{Browser: loginBrowser})
+	if err != nil {
+		handleError(fmt.Errorf("login failed: %w", err))
+	}
+	fmt.Printf("Logged in to %s with %s\n", serverURL, creds.IssuerURL)
+}
+
+func runLogout(_ *cobra.Command, _ []string) {
+	if strings.TrimSpace(serverURL) == "" {
+		handleError(fmt.Errorf("--server is required (or set CAIB_SERVER env)"))
+	}
+	if err := auth.Logout(serverURL); err != nil {
+		handleError(fmt.Errorf("logout failed: %w", err))
+	}
+	fmt.Printf("Logged out of %s\n", serverURL)
+}
@@ -7,6 +7,7 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,11 +22,11 @@ import (
 	"github.com/containers/image/v5/copy"
 	"github.com/containers/image/v5/docker"
 	"github.com/containers/image/v5/oci/layout"
-	"github.com/containers/image/v5/signature"
-	"github.com/containers/image/v5/types"
 	"gopkg.in/yaml.v3"
 
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/auth"
 	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/catalog"
+	"github.com/centos-automotive-suite/automotive-dev-operator/cmd/caib/ociutil"
 	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
 	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
 	progressbar "github.com/schollz/progressbar/v3"
@@ -52,6 +53,7 @@ func getDefaultArch() string {
 
 var (
 	serverURL              string
+	buildNamespace         string
 	manifest               string
 	buildName              string
 	distro                 string
@@ -68,6 +70,7 @@ var (
 	followLogs             bool
 	version                string
 	compressArtifacts      bool
+	downloadParallel       int
 	compressionAlgo        string
 	authToken              string
 
@@ -78,23 +81,80 @@ var (
 	builderImage   string
 
 	containerRef string
+
+	s3Destination string
+	s3Endpoint    string
+	s3Region      string
+	s3Secret      string
+
+	deltaFromBuild   string
+	deltaFromCatalog string
+	deltaMethod      string
+
+	publishToCatalog   bool
+	catalogName        string
+	catalogTags        []string
+	catalogTargetNotes string
 )
 
-// createBuildAPIClient creates a build API client with authentication token from flags or kubeconfig
+// createBuildAPIClient creates a build API client with authentication token from flags, the cached
+// caib login or kubeconfig
 func createBuildAPIClient(serverURL string, authToken *string) (*buildapiclient.Client, error) {
+	if strings.TrimSpace(*authToken) == "" {
+		tok, err := auth.CachedToken(context.Background(), serverURL)
+		if err != nil {
+			return nil, err
+		}
+		*authToken = tok
+	}
 	if strings.TrimSpace(*authToken) == "" {
 		if tok, err := loadTokenFromKubeconfig(); err == nil && strings.TrimSpace(tok) != "" {
 			*authToken = tok
 		}
 	}
 
-	var opts []buildapiclient.Option
+	opts := []buildapiclient.Option{buildapiclient.WithRetryNotify(printRateLimitRetry)}
 	if strings.TrimSpace(*authToken) != "" {
 		opts = append(opts, buildapiclient.WithAuthToken(strings.TrimSpace(*authToken)))
 	}
+	if strings.TrimSpace(buildNamespace) != "" {
+		opts = append(opts, buildapiclient.WithNamespace(strings.TrimSpace(buildNamespace)))
+	}
 	return buildapiclient.New(serverURL, opts...)
 }
 
+// newRetryTransport wraps base to retry rate-limited requests, telling the user about each wait
+func newRetryTransport(base http.RoundTripper) http.RoundTripper {
+	retry := buildapiclient.NewRetryTransport(base)
+	retry.OnRetry = printRateLimitRetry
+	return retry
+}
+
+func printRateLimitRetry(wait time.Duration) {
+	fmt.Fprintf(os.Stderr, "Rate limited by the build API, retrying in %s\n", wait)
+}
+
+// addNamespaceFlag adds the flag selecting the tenant namespace of builds
+func addNamespaceFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&buildNamespace, "namespace", os.Getenv("CAIB_NAMESPACE"),
+		"tenant namespace of the build (defaults to your team or personal namespace)",
+	)
+}
+
+// withNamespace adds the selected tenant namespace to a build API URL
+func withNamespace(rawURL string) string {
+	ns := strings.TrimSpace(buildNamespace)
+	if ns == "" {
+		return rawURL
+	}
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+	}
+	return rawURL + sep + "namespace=" + url.QueryEscape(ns)
+}
+
 // extractRegistryCredentials extracts registry URL and returns registry URL and credentials from env vars
 func extractRegistryCredentials(primaryRef, secondaryRef string) (string, string, string) {
 	// Get credentials from environment variables only
@@ -126,6 +186,103 @@ func extractRegistryCredentials(primaryRef, secondaryRef string) (string, string
 	return "docker.io", username, password
 }
 
+// addS3UploadFlags registers the flags for uploading the finished artifact to S3-compatible storage
+func addS3UploadFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&s3Destination, "upload-s3", "", "upload artifact to S3-compatible storage (s3://bucket[/prefix])")
+	cmd.Flags().StringVar(&s3Endpoint, "s3-endpoint", os.Getenv("CAIB_S3_ENDPOINT"), "S3 endpoint URL")
+	cmd.Flags().StringVar(&s3Region, "s3-region", "", "S3 bucket region (default us-east-1)")
+	cmd.Flags().StringVar(
+		&s3Secret, "s3-secret", os.Getenv("CAIB_S3_SECRET"),
+		"secret in the build namespace with AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY",
+	)
+}
+
+// s3UploadFromFlags builds the S3 upload target from the --upload-s3 flags, or nil if not requested
+func s3UploadFromFlags() (*buildapitypes.S3Upload, error) {
+	if s3Destination == "" {
+		return nil, nil
+	}
+	rest, ok := strings.CutPrefix(s3Destination, "s3://")
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if !ok || bucket == "" {
+		return nil, fmt.Errorf("--upload-s3 must be of the form s3://bucket[/prefix]")
+	}
+	if s3Endpoint == "" {
+		return nil, fmt.Errorf("--s3-endpoint (or CAIB_S3_ENDPOINT) is required with --upload-s3")
+	}
+	if s3Secret == "" {
+		return nil, fmt.Errorf("--s3-secret (or CAIB_S3_SECRET) is required with --upload-s3")
+	}
+	return &buildapitypes.S3Upload{
+		Endpoint:  s3Endpoint,
+		Bucket:    bucket,
+		Prefix:    strings.Trim(prefix, "/"),
+		Region:    s3Region,
+		SecretRef: s3Secret,
+	}, nil
+}
+
+// addDeltaFlags registers the flags for generating a binary delta against a base image
+func addDeltaFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&deltaFromBuild, "delta-from", "", "generate a delta from the artifact of this completed build")
+	cmd.Flags().StringVar(
+		&deltaFromCatalog, "delta-from-catalog", "", "generate a delta from the disk image of this catalog image",
+	)
+	cmd.Flags().StringVar(&deltaMethod, "delta-method", "", "delta algorithm (zstd, bsdiff, ostree; default zstd)")
+}
+
+// deltaFromFlags builds the delta request from the --delta-from flags, or nil if not requested
+func deltaFromFlags() (*buildapitypes.DeltaRequest, error) {
+	if deltaFromBuild == "" && deltaFromCatalog == "" {
+		if deltaMethod != "" {
+			return nil, fmt.Errorf("--delta-method requires --delta-from or --delta-from-catalog")
+		}
+		return nil, nil
+	}
+	if deltaFromBuild != "" && deltaFromCatalog != "" {
+		return nil, fmt.Errorf("--delta-from and --delta-from-catalog are mutually exclusive")
+	}
+	return &buildapitypes.DeltaRequest{
+		BaseBuild:        deltaFromBuild,
+		BaseCatalogImage: deltaFromCatalog,
+		Method:           deltaMethod,
+	}, nil
+}
+
+// printBuildAccepted reports an accepted build with the server's warnings, such as a deprecated delta base
+func printBuildAccepted(resp *buildapitypes.BuildResponse) {
+	fmt.Printf("Build %s accepted: %s - %s\n", resp.Name, resp.Phase, resp.Message)
+	for _, warning := range resp.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
+
+// addCatalogPublishFlags registers the flags for publishing the pushed image to the catalog
+func addCatalogPublishFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&publishToCatalog, "publish-to-catalog", false, "publish the pushed image to the catalog on success")
+	cmd.Flags().StringVar(
+		&catalogName, "catalog-name", "",
+		"catalog image name, may use {{.Name}}, {{.Distro}}, {{.Target}}, {{.Architecture}} (default: build name)",
+	)
+	cmd.Flags().StringSliceVar(&catalogTags, "catalog-tag", nil, "tag to apply to the catalog image (repeatable)")
+	cmd.Flags().StringVar(&catalogTargetNotes, "catalog-target-notes", "", "notes recorded on the catalog image's target")
+}
+
+// catalogPublishFromFlags builds the catalog publish request from the flags, or nil if not requested
+func catalogPublishFromFlags() (*buildapitypes.CatalogPublish, error) {
+	if !publishToCatalog {
+		if catalogName != "" || len(catalogTags) > 0 || catalogTargetNotes != "" {
+			return nil, fmt.Errorf("--catalog-name, --catalog-tag and --catalog-target-notes require --publish-to-catalog")
+		}
+		return nil, nil
+	}
+	return &buildapitypes.CatalogPublish{
+		NameTemplate: catalogName,
+		Tags:         catalogTags,
+		TargetNotes:  catalogTargetNotes,
+	}, nil
+}
+
 // validateRegistryCredentials validates registry credentials and returns an error for partial credentials
 func validateRegistryCredentials(registryURL, username, password string) error {
 	// If no registry URL, no credentials needed
@@ -293,6 +450,9 @@ Examples:
 		&compressArtifacts, "compress", true,
 		"compress directory artifacts (tar.gz). For directories, server always compresses.",
 	)
+	downloadCmd.Flags().IntVar(
+		&downloadParallel, "parallel", 1, "number of concurrent range requests used to download the artifact",
+	)
 
 	listCmd.Flags().StringVar(
 		&serverURL, "server", os.Getenv("CAIB_SERVER"), "REST API server base URL (e.g. https://api.example)",
@@ -345,11 +505,27 @@ Examples:
 	buildDevCmd.Flags().IntVar(&timeout, "timeout", 60, "timeout in minutes")
 	buildDevCmd.Flags().BoolVarP(&waitForBuild, "wait", "w", false, "wait for build to complete")
 	buildDevCmd.Flags().BoolVarP(&followLogs, "follow", "f", true, "follow build logs")
+
+	addS3UploadFlags(buildCmd)
+	addS3UploadFlags(diskCmd)
+	addS3UploadFlags(buildDevCmd)
+	addDeltaFlags(buildCmd)
+	addDeltaFlags(diskCmd)
+	addDeltaFlags(buildDevCmd)
+	addCatalogPublishFlags(buildCmd)
+	addCatalogPublishFlags(diskCmd)
+	addCatalogPublishFlags(buildDevCmd)
+	for _, cmd := range []*cobra.Command{buildCmd, diskCmd, buildDevCmd, downloadCmd, listCmd} {
+		addNamespaceFlag(cmd)
+	}
 	_ = buildDevCmd.MarkFlagRequired("mode")
 	_ = buildDevCmd.MarkFlagRequired("format")
 
 	// Add all commands
-	rootCmd.AddCommand(buildCmd, diskCmd, buildDevCmd, downloadCmd, listCmd, catalog.NewCatalogCmd())
+	rootCmd.AddCommand(
+		buildCmd, diskCmd, buildDevCmd, downloadCmd, listCmd, catalog.NewCatalogCmd(), newTokenCmd(),
+		newLoginCmd(), newLogoutCmd(), newUsageCmd(), newFlashCmd(),
+	)
 	// Add deprecated aliases for backwards compatibility
 	rootCmd.AddCommand(buildBootcAliasCmd, buildLegacyAliasCmd, buildTraditionalAliasCmd)
 
@@ -441,11 +617,29 @@ func runBuild(_ *cobra.Command, args []string) {
 		}
 	}
 
+	s3Upload, err := s3UploadFromFlags()
+	if err != nil {
+		handleError(err)
+	}
+	req.S3Upload = s3Upload
+
+	delta, err := deltaFromFlags()
+	if err != nil {
+		handleError(err)
+	}
+	req.Delta = delta
+
+	catalogPublish, err := catalogPublishFromFlags()
+	if err != nil {
+		handleError(err)
+	}
+	req.PublishToCatalog = catalogPublish
+
 	resp, err := api.CreateBuild(ctx, req)
 	if err != nil {
 		handleError(err)
 	}
-	fmt.Printf("Build %s accepted: %s - %s\n", resp.Name, resp.Phase, resp.Message)
+	printBuildAccepted(resp)
 
 	// Handle local file uploads if needed
 	localRefs, err := findLocalFileReferences(string(manifestBytes))
@@ -542,11 +736,29 @@ func runDisk(_ *cobra.Command, args []string) {
 		}
 	}
 
+	s3Upload, err := s3UploadFromFlags()
+	if err != nil {
+		handleError(err)
+	}
+	req.S3Upload = s3Upload
+
+	delta, err := deltaFromFlags()
+	if err != nil {
+		handleError(err)
+	}
+	req.Delta = delta
+
+	catalogPublish, err := catalogPublishFromFlags()
+	if err != nil {
+		handleError(err)
+	}
+	req.PublishToCatalog = catalogPublish
+
 	resp, err := api.CreateBuild(ctx, req)
 	if err != nil {
 		handleError(err)
 	}
-	fmt.Printf("Build %s accepted: %s - %s\n", resp.Name, resp.Phase, resp.Message)
+	printBuildAccepted(resp)
 
 	if waitForBuild || followLogs || outputDir != "" {
 		waitForBuildCompletion(ctx, api, resp.Name, "")
@@ -586,13 +798,9 @@ func pullOCIArtifact(ociRef, destPath, username, password string) error {
 	ctx := context.Background()
 
 	// Set up system context with authentication
-	systemCtx := &types.SystemContext{}
-	if username != "" && password != "" {
+	systemCtx := ociutil.SystemContext(username, password)
+	if systemCtx.DockerAuthConfig != nil {
 		fmt.Printf("Using provided username/password credentials\n")
-		systemCtx.DockerAuthConfig = &types.DockerAuthConfig{
-			Username: username,
-			Password: password,
-		}
 	} else {
 		fmt.Printf("No explicit credentials provided, will use Docker/Podman auth files if available\n")
 		// containers/image will automatically use:
@@ -602,15 +810,6 @@ func pullOCIArtifact(ociRef, destPath, username, password string) error {
 		// - $HOME/.config/containers/auth.json
 	}
 
-	// Set up policy context (allow all)
-	policy := &signature.Policy{
-		Default: []signature.PolicyRequirement{signature.NewPRInsecureAcceptAnything()},
-	}
-	policyCtx, err := signature.NewPolicyContext(policy)
-	if err != nil {
-		return fmt.Errorf("create policy context: %w", err)
-	}
-
 	// Source: docker registry reference
 	srcRef, err := docker.ParseReference("//" + ociRef)
 	if err != nil {
@@ -636,13 +835,13 @@ func pullOCIArtifact(ociRef, destPath, username, password string) error {
 
 	// Copy the image from registry to local OCI layout
 	fmt.Printf("Downloading OCI artifact...")
-	_, err = copy.Image(ctx, policyCtx, destRef, srcRef, &copy.Options{
+	_, err = ociutil.CopyImage(ctx, destRef, srcRef, &copy.Options{
 		ReportWriter:   os.Stdout,
 		SourceCtx:      systemCtx,
 		DestinationCtx: systemCtx,
 	})
 	if err != nil {
-		return fmt.Errorf("copy image: %w", err)
+		return err
 	}
 
 	fmt.Printf("\nExtracting artifact to %s\n", destPath)
@@ -813,11 +1012,29 @@ func runBuildDev(_ *cobra.Command, args []string) {
 		}
 	}
 
+	s3Upload, err := s3UploadFromFlags()
+	if err != nil {
+		handleError(err)
+	}
+	req.S3Upload = s3Upload
+
+	delta, err := deltaFromFlags()
+	if err != nil {
+		handleError(err)
+	}
+	req.Delta = delta
+
+	catalogPublish, err := catalogPublishFromFlags()
+	if err != nil {
+		handleError(err)
+	}
+	req.PublishToCatalog = catalogPublish
+
 	resp, err := api.CreateBuild(ctx, req)
 	if err != nil {
 		handleError(err)
 	}
-	fmt.Printf("Build %s accepted: %s - %s\n", resp.Name, resp.Phase, resp.Message)
+	printBuildAccepted(resp)
 
 	// Handle local file uploads if needed
 	localRefs, err := findLocalFileReferences(string(manifestBytes))
@@ -907,89 +1124,125 @@ func waitForBuildCompletion(ctx context.Context, api *buildapiclient.Client, nam
 
 	logClient := &http.Client{
 		Timeout: 10 * time.Minute,
-		Transport: &http.Transport{
+		Transport: newRetryTransport(&http.Transport{
 			ResponseHeaderTimeout: 30 * time.Second,
 			IdleConnTimeout:       2 * time.Minute,
-		},
+		}),
 	}
 	streamState := &logStreamState{}
 
+	// Status changes are pushed by the build API; servers without the watch API are polled
+	events, err := api.WatchBuild(timeoutCtx, name)
+	if err != nil {
+		events = nil
+	}
+	var latest *buildapitypes.BuildResponse
+
 	for {
+		var st *buildapitypes.BuildResponse
+		showStatus := !streamState.active && (!userFollowRequested || !streamState.canRetry())
 		select {
 		case <-timeoutCtx.Done():
 			handleError(fmt.Errorf("timed out waiting for build"))
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if event.Type == buildapitypes.BuildEventDeleted {
+				handleError(fmt.Errorf("build %s was deleted", name))
+			}
+			if event.Type == buildapitypes.BuildEventStep && event.Step != nil && showStatus {
+				fmt.Printf("step %s: %s\n", event.Step.Task, event.Step.Phase)
+			}
+			latest = &event.Build
+			st = latest
 		case <-ticker.C:
+			if events != nil && latest != nil {
+				st = latest
+				break
+			}
 			reqCtx, cancelReq := context.WithTimeout(ctx, 2*time.Minute)
-			st, err := api.GetBuild(reqCtx, name)
+			polled, err := api.GetBuild(reqCtx, name)
 			cancelReq()
 			if err != nil {
 				fmt.Printf("status check failed: %v\n", err)
 				continue
 			}
+			st = polled
+		}
 
-			// Update status display (only when not streaming)
-			if !streamState.active && (!userFollowRequested || !streamState.canRetry()) {
-				if st.Phase != lastPhase || st.Message != lastMessage {
-					fmt.Printf("status: %s - %s\n", st.Phase, st.Message)
-					lastPhase = st.Phase
-					lastMessage = st.Message
-				}
+		// Update status display (only when not streaming)
+		if showStatus {
+			if st.Phase != lastPhase || st.Message != lastMessage {
+				fmt.Printf("status: %s - %s\n", st.Phase, st.Message)
+				lastPhase = st.Phase
+				lastMessage = st.Message
 			}
+		}
 
-			// Handle terminal build states
-			if st.Phase == "Completed" {
-				fmt.Println("Build completed successfully!")
-				if st.Jumpstarter != nil && st.Jumpstarter.Available {
-					fmt.Println("\nJumpstarter is available for device flashing.")
-					if st.Jumpstarter.ExporterSelector != "" {
-						fmt.Printf("  Exporter selector: %s\n", st.Jumpstarter.ExporterSelector)
-					}
-					if st.Jumpstarter.FlashCmd != "" {
-						fmt.Printf("  Flash command: %s\n", st.Jumpstarter.FlashCmd)
-					}
+		// Handle terminal build states
+		if st.Phase == "Completed" {
+			fmt.Println("Build completed successfully!")
+			if latest != nil {
+				// Watch events leave out Jumpstarter details
+				if full, err := api.GetBuild(ctx, name); err == nil {
+					st = full
 				}
-				if downloadTo != "" {
-					if err := downloadArtifactViaAPI(ctx, serverURL, name, downloadTo); err != nil {
-						fmt.Printf("Download failed: %v\n", err)
-					}
+			}
+			if st.CatalogImageName != "" {
+				fmt.Printf("Published to catalog as %s\n", st.CatalogImageName)
+			}
+			if st.Jumpstarter != nil && st.Jumpstarter.Available {
+				fmt.Println("\nJumpstarter is available for device flashing.")
+				if st.Jumpstarter.ExporterSelector != "" {
+					fmt.Printf("  Exporter selector: %s\n", st.Jumpstarter.ExporterSelector)
+				}
+				if st.Jumpstarter.FlashCmd != "" {
+					fmt.Printf("  Flash command: %s\n", st.Jumpstarter.FlashCmd)
 				}
-				return
 			}
-			if st.Phase == "Failed" {
-				handleError(fmt.Errorf("build failed: %s", st.Message))
+			if downloadTo != "" {
+				if err := downloadArtifactViaAPI(ctx, serverURL, name, downloadTo); err != nil {
+					fmt.Printf("Download failed: %v\n", err)
+				}
 			}
+			return
+		}
+		if st.Phase == "Failed" {
+			handleError(fmt.Errorf("build failed: %s", st.Message))
+		}
 
-			// Attempt log streaming for active builds
-			if !followLogs || streamState.active || !streamState.canRetry() {
-				continue
-			}
+		// Attempt log streaming for active builds
+		if !followLogs || streamState.active || !streamState.canRetry() {
+			continue
+		}
 
-			if st.Phase == "Pending" {
-				streamState.reset()
-				if userFollowRequested && !pendingWarningShown {
-					fmt.Println("Waiting for build to start before streaming logs...")
-					pendingWarningShown = true
-				}
-				continue
+		if st.Phase == "Pending" {
+			streamState.reset()
+			if userFollowRequested && !pendingWarningShown {
+				fmt.Println("Waiting for build to start before streaming logs...")
+				pendingWarningShown = true
 			}
+			continue
+		}
 
-			if isBuildActive(st.Phase) {
-				if streamState.retryCount == 0 {
-					fmt.Println("Build is active. Attempting to stream logs...")
-					pendingWarningShown = false
-				}
+		if isBuildActive(st.Phase) {
+			if streamState.retryCount == 0 {
+				fmt.Println("Build is active. Attempting to stream logs...")
+				pendingWarningShown = false
+			}
 
-				if err := tryLogStreaming(ctx, logClient, name, streamState); err != nil {
-					streamState.retryCount++
-					if !streamState.canRetry() && !retryLimitWarningShown {
-						msg := "Log streaming failed after %d attempts (~2 minutes). " +
-							"Falling back to status updates only.\n"
-						fmt.Printf(msg, maxLogRetries)
-						retryLimitWarningShown = true
-					}
-				} else {
-					followLogs = userFollowRequested
+			if err := tryLogStreaming(ctx, logClient, name, streamState); err != nil {
+				streamState.retryCount++
+				if !streamState.canRetry() && !retryLimitWarningShown {
+					msg := "Log streaming failed after %d attempts (~2 minutes). " +
+						"Falling back to status updates only.\n"
+					fmt.Printf(msg, maxLogRetries)
+					retryLimitWarningShown = true
 				}
+			} else {
+				followLogs = userFollowRequested
 			}
 		}
 	}
@@ -1050,7 +1303,7 @@ func buildLogURL(buildName string, startTime time.Time) string {
 	if !startTime.IsZero() {
 		logURL += "&since=" + url.QueryEscape(startTime.Format(time.RFC3339))
 	}
-	return logURL
+	return withNamespace(logURL)
 }
 
 func streamLogsToStdout(body io.Reader, state *logStreamState) error {
@@ -1298,23 +1551,24 @@ func detectFileCompression(filePath string) string {
 	return ""
 }
 
-// downloadWithProgress downloads content with a progress bar
-func downloadWithProgress(resp *http.Response, destFile *os.File) error {
+// downloadWithProgress downloads content with a progress bar, starting the bar at offset
+// when the response continues a partial download
+func downloadWithProgress(resp *http.Response, destFile *os.File, offset int64) error {
 	cl := strings.TrimSpace(resp.Header.Get("Content-Length"))
 	if cl != "" {
-		return downloadWithKnownSize(resp, destFile, cl)
+		return downloadWithKnownSize(resp, destFile, cl, offset)
 	}
 	return downloadWithUnknownSize(resp, destFile)
 }
 
 // downloadWithKnownSize downloads with a sized progress bar
-func downloadWithKnownSize(resp *http.Response, destFile *os.File, contentLength string) error {
+func downloadWithKnownSize(resp *http.Response, destFile *os.File, contentLength string, offset int64) error {
 	var total int64
 	if _, err := fmt.Sscan(contentLength, &total); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to parse Content-Length: %v\n", err)
 	}
 	bar := progressbar.NewOptions64(
-		total,
+		offset+total,
 		progressbar.OptionSetDescription("Downloading"),
 		progressbar.OptionShowBytes(true),
 		progressbar.OptionSetWidth(15),
@@ -1322,6 +1576,9 @@ func downloadWithKnownSize(resp *http.Response, destFile *os.File, contentLength
 		progressbar.OptionShowCount(),
 		progressbar.OptionClearOnFinish(),
 	)
+	if offset > 0 {
+		_ = bar.Set64(offset)
+	}
 	reader := io.TeeReader(resp.Body, bar)
 	if _, copyErr := io.Copy(destFile, reader); copyErr != nil {
 		return copyErr
@@ -1373,8 +1630,10 @@ func extractServerFilename(resp *http.Response) string {
 	return ""
 }
 
-// handleArtifactDownload processes a successful artifact response
-func handleArtifactDownload(resp *http.Response, outDir, userFilename, name string) error {
+// handleArtifactDownload processes a successful artifact response. A partial file left by an
+// earlier attempt is continued when allowResume is set and the server reports the same ETag;
+// in that case a *resumeRequest is returned and the caller re-requests the remaining bytes.
+func handleArtifactDownload(resp *http.Response, outDir, userFilename, name string, allowResume bool) error {
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", err)
@@ -1386,27 +1645,60 @@ func handleArtifactDownload(resp *http.Response, outDir, userFilename, name stri
 	compression := strings.TrimSpace(resp.Header.Get("X-AIB-Compression"))
 	filename := resolveFilename(userFilename, serverFilename, compression, name+".artifact")
 
-	printArtifactMetadata(resp)
-
 	outPath := filepath.Join(outDir, filename)
 	tmp := outPath + ".partial"
-	f, err := os.Create(tmp)
+
+	if allowResume && resp.StatusCode == http.StatusOK {
+		if resume := partialResumeRequest(resp, tmp); resume != nil {
+			return resume
+		}
+	}
+
+	f, offset, err := openPartialForResponse(resp, tmp)
 	if err != nil {
+		if resp.StatusCode == http.StatusPartialContent {
+			// The partial file cannot be continued, start over
+			discardPartial(tmp)
+			return &resumeRequest{path: tmp}
+		}
 		return err
 	}
 
-	if err := downloadWithProgress(resp, f); err != nil {
+	if offset == 0 {
+		printArtifactMetadata(resp)
+	}
+
+	if err := downloadWithProgress(resp, f, offset); err != nil {
 		_ = f.Close()
-		_ = os.Remove(tmp)
-		return err
+		etag := strings.TrimSpace(resp.Header.Get("ETag"))
+		info, statErr := os.Stat(tmp)
+		if etag == "" || statErr != nil {
+			discardPartial(tmp)
+			return err
+		}
+		return &downloadInterruptedError{
+			err:    err,
+			resume: &resumeRequest{path: tmp, offset: info.Size(), etag: etag},
+		}
 	}
 
 	if err := f.Close(); err != nil {
 		return fmt.Errorf("failed to close file: %w", err)
 	}
+
+	if sum := strings.TrimSpace(resp.Header.Get(headerArtifactSHA256)); sum != "" {
+		fmt.Println("Verifying checksum...")
+		if err := verifyFileChecksum(tmp, sum); err != nil {
+			discardPartial(tmp)
+			return err
+		}
+		fmt.Printf("Checksum verified (sha256:%s)\n", sum)
+	}
+
 	if err := os.Rename(tmp, outPath); err != nil {
 		return err
 	}
+	_ = os.Remove(etagFile(tmp))
 	fmt.Printf("Artifact downloaded to %s\n", outPath)
 
 	return extractIfTarArchive(contentType, outPath)
@@ -1449,26 +1741,31 @@ func downloadArtifactViaAPI(ctx context.Context, baseURL, name, outPath string)
 	}
 
 	base := strings.TrimRight(baseURL, "/")
-	urlStr := base + "/v1/builds/" + url.PathEscape(name) + "/artifact"
+	urlStr := withNamespace(base + "/v1/builds/" + url.PathEscape(name) + "/artifact")
 	deadline := time.Now().Add(30 * time.Minute)
 
 	httpClient := &http.Client{
 		Timeout: 30 * time.Minute,
-		Transport: &http.Transport{
+		Transport: newRetryTransport(&http.Transport{
 			ResponseHeaderTimeout: 2 * time.Minute,
 			IdleConnTimeout:       5 * time.Minute,
-		},
+		}),
 	}
 
 	warned := false
+	retries := 0
+	var resume *resumeRequest
 	for {
 		if ctx.Err() != nil || time.Now().After(deadline) {
 			return fmt.Errorf("timed out waiting for artifact to become ready")
 		}
 
 		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-		if strings.TrimSpace(authToken) != "" {
-			req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(authToken))
+		setAuthHeader(req)
+		rangeRequested := resume != nil && resume.offset > 0
+		if rangeRequested {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", resume.offset))
+			req.Header.Set("If-Range", resume.etag)
 		}
 
 		resp, err := httpClient.Do(req)
@@ -1477,13 +1774,35 @@ func downloadArtifactViaAPI(ctx context.Context, baseURL, name, outPath string)
 			continue
 		}
 
-		if resp.StatusCode == http.StatusOK {
-			return handleArtifactDownload(resp, outDir, userFilename, name)
+		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusPartialContent {
+			err := handleArtifactDownload(resp, outDir, userFilename, name, !rangeRequested)
+			var next *resumeRequest
+			var interrupted *downloadInterruptedError
+			switch {
+			case errors.As(err, &next):
+				resume = next
+				continue
+			case errors.As(err, &interrupted) && retries < maxDownloadRetries:
+				retries++
+				fmt.Printf("Download interrupted (%v), resuming (attempt %d/%d)...\n",
+					interrupted.err, retries, maxDownloadRetries)
+				resume = interrupted.resume
+				time.Sleep(3 * time.Second)
+				continue
+			}
+			return err
 		}
 
 		body, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
 
+		if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && resume != nil {
+			// The partial file no longer matches the artifact, start over
+			discardPartial(resume.path)
+			resume = nil
+			continue
+		}
+
 		if shouldRetryResponse(resp.StatusCode, body) {
 			if !warned {
 				fmt.Println("Artifact not ready yet. Waiting...")
@@ -1587,7 +1906,18 @@ func runDownload(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	if err := downloadArtifactViaAPI(ctx, serverURL, buildName, outputDir); err != nil {
+	if downloadParallel < 1 {
+		fmt.Println("Error: --parallel must be at least 1")
+		os.Exit(1)
+	}
+
+	download := downloadArtifactViaAPI
+	if downloadParallel > 1 {
+		download = func(ctx context.Context, baseURL, name, outPath string) error {
+			return downloadArtifactParallel(ctx, baseURL, name, outPath, downloadParallel)
+		}
+	}
+	if err := download(ctx, serverURL, buildName, outputDir); err != nil {
 		fmt.Printf("Download failed: %v\n", err)
 		os.Exit(1)
 	}
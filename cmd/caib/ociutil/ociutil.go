@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ociutil copies images between container registries and local OCI layouts for caib.
+package ociutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+)
+
+// SystemContext returns a system context authenticating with the given credentials. Without credentials,
+// containers/image falls back to the Docker/Podman auth files.
+func SystemContext(username, password string) *types.SystemContext {
+	systemCtx := &types.SystemContext{}
+	if username != "" && password != "" {
+		systemCtx.DockerAuthConfig = &types.DockerAuthConfig{
+			Username: username,
+			Password: password,
+		}
+	}
+	return systemCtx
+}
+
+// CopyImage copies an image from src to dest without verifying signatures and returns its manifest
+func CopyImage(
+	ctx context.Context,
+	dest, src types.ImageReference,
+	options *copy.Options,
+) ([]byte, error) {
+	// Set up policy context (allow all)
+	policy := &signature.Policy{
+		Default: []signature.PolicyRequirement{signature.NewPRInsecureAcceptAnything()},
+	}
+	policyCtx, err := signature.NewPolicyContext(policy)
+	if err != nil {
+		return nil, fmt.Errorf("create policy context: %w", err)
+	}
+	defer func() {
+		_ = policyCtx.Destroy()
+	}()
+
+	manifest, err := copy.Image(ctx, policyCtx, dest, src, options)
+	if err != nil {
+		return nil, fmt.Errorf("copy image: %w", err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	buildapitypes "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+	buildapiclient "github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/client"
+)
+
+var (
+	tokenDescription string
+	tokenScopes      []string
+	tokenExpiresIn   int
+)
+
+// newTokenCmd creates the command managing build API tokens for CI pipelines
+func newTokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage API tokens for CI pipelines",
+		Long: `Manage long-lived build API tokens acting as you, for CI jobs that cannot log in to the cluster.
+
+A token can only perform the operations of its scopes, and only those your RBAC permits:
+  build:create     create builds and upload their files
+  build:read       list and get builds, follow their logs
+  artifact:read    download build artifacts and images
+  catalog:read     list and get catalog images
+  catalog:publish  add, publish, promote, verify and remove catalog images, approve promotions
+
+Pass the token to caib with --token or CAIB_TOKEN. Managing tokens requires a cluster or caib login.`,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an API token",
+		Example: `  # Token for a CI job that builds and downloads images
+  caib token create --description "nightly CI" --scope build:create --scope build:read --scope artifact:read`,
+		Args: cobra.NoArgs,
+		Run:  runTokenCreate,
+	}
+	createCmd.Flags().StringVar(&tokenDescription, "description", "", "what the token is used for")
+	createCmd.Flags().StringSliceVar(
+		&tokenScopes, "scope", []string{"build:create", "build:read", "artifact:read"}, "scopes granted to the token",
+	)
+	createCmd.Flags().IntVar(&tokenExpiresIn, "expires-in", 30, "days until the token expires (at most 90)")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List your API tokens",
+		Args:  cobra.NoArgs,
+		Run:   runTokenList,
+	}
+
+	revokeCmd := &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API token",
+		Args:  cobra.ExactArgs(1),
+		Run:   runTokenRevoke,
+	}
+
+	for _, c := range []*cobra.Command{createCmd, listCmd, revokeCmd} {
+		c.Flags().StringVar(&serverURL, "server", os.Getenv("CAIB_SERVER"), "REST API server base URL")
+		c.Flags().StringVar(
+			&authToken, "token", "", "login bearer token (defaults to the caib or kubeconfig login, not CAIB_TOKEN)",
+		)
+	}
+	cmd.AddCommand(createCmd, listCmd, revokeCmd)
+	return cmd
+}
+
+func runTokenCreate(_ *cobra.Command, _ []string) {
+	api := tokenAPIClient()
+	resp, err := api.CreateToken(context.Background(), buildapitypes.APITokenRequest{
+		Description:   tokenDescription,
+		Scopes:        tokenScopes,
+		ExpiresInDays: tokenExpiresIn,
+	})
+	if err != nil {
+		fmt.Printf("Error creating token: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Created token %s (scopes: %s, expires %s)\n", resp.ID, strings.Join(resp.Scopes, ","), resp.ExpiresAt)
+	fmt.Println("Store it now, it cannot be shown again:")
+	fmt.Println(resp.Token)
+}
+
+func runTokenList(_ *cobra.Command, _ []string) {
+	api := tokenAPIClient()
+	tokens, err := api.ListTokens(context.Background())
+	if err != nil {
+		fmt.Printf("Error listing tokens: %v\n", err)
+		os.Exit(1)
+	}
+	if len(tokens) == 0 {
+		fmt.Println("No API tokens found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tDESCRIPTION\tSCOPES\tCREATED\tEXPIRES")
+	for _, t := range tokens {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			t.ID, t.Description, strings.Join(t.Scopes, ","), t.CreatedAt, t.ExpiresAt)
+	}
+	_ = w.Flush()
+}
+
+func runTokenRevoke(_ *cobra.Command, args []string) {
+	api := tokenAPIClient()
+	if err := api.RevokeToken(context.Background(), args[0]); err != nil {
+		fmt.Printf("Error revoking token: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Revoked token %s\n", args[0])
+}
+
+// tokenAPIClient creates a build API client authenticated with a login token, as API tokens
+// cannot manage API tokens
+func tokenAPIClient() *buildapiclient.Client {
+	if strings.TrimSpace(serverURL) == "" {
+		fmt.Println("Error: --server is required (or set CAIB_SERVER)")
+		os.Exit(1)
+	}
+	api, err := createBuildAPIClient(serverURL, &authToken)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return api
+}
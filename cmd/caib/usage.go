@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	usageSince    string
+	usageUntil    string
+	usageUser     string
+	usageAllUsers bool
+)
+
+// newUsageCmd creates the command reporting build usage and quotas
+func newUsageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show build usage and quota",
+		Long: `Show build counts, build minutes and artifact storage, computed from the ImageBuilds on the cluster.
+
+Counts and minutes cover the builds created in the time range (the last 30 days by default); active
+builds and artifact storage cover all existing builds. Deleted builds are not counted.
+Reporting other users requires permission to list imagebuilds in all namespaces.`,
+		Example: `  # Your usage and quota over the last 30 days
+  caib usage
+
+  # Usage of every user in September
+  caib usage --all-users --since 2026-09-01 --until 2026-10-01`,
+		Args: cobra.NoArgs,
+		Run:  runUsage,
+	}
+	cmd.Flags().StringVar(&serverURL, "server", os.Getenv("CAIB_SERVER"), "REST API server base URL")
+	cmd.Flags().StringVar(&authToken, "token", os.Getenv("CAIB_TOKEN"), "Bearer token for authentication")
+	cmd.Flags().StringVar(&usageSince, "since", "", "start of the time range (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().StringVar(&usageUntil, "until", "", "end of the time range (YYYY-MM-DD or RFC 3339, defaults to now)")
+	cmd.Flags().StringVar(&usageUser, "user", "", "report another user")
+	cmd.Flags().BoolVar(&usageAllUsers, "all-users", false, "report every user")
+	return cmd
+}
+
+func runUsage(_ *cobra.Command, _ []string) {
+	if strings.TrimSpace(serverURL) == "" {
+		handleError(fmt.Errorf("--server is required (or set CAIB_SERVER env)"))
+	}
+	since, err := parseUsageTime(usageSince)
+	if err != nil {
+		handleError(fmt.Errorf("invalid --since: %w", err))
+	}
+	until, err := parseUsageTime(usageUntil)
+	if err != nil {
+		handleError(fmt.Errorf("invalid --until: %w", err))
+	}
+
+	api, err := createBuildAPIClient(serverURL, &authToken)
+	if err != nil {
+		handleError(err)
+	}
+	usage, err := api.GetUsage(context.Background(), since, until, usageUser, usageAllUsers)
+	if err != nil {
+		handleError(err)
+	}
+
+	fmt.Printf("Usage from %s to %s\n\n", usage.Since, usage.Until)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "USER\tBUILDS\tSUCCEEDED\tFAILED\tBUILD MINUTES\tACTIVE\tARTIFACT STORAGE")
+	for _, u := range usage.Users {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.0f\t%d\t%s\n",
+			u.User, u.Builds, u.Succeeded, u.Failed, u.BuildMinutes, u.ActiveBuilds, formatStorage(u.ArtifactStorageBytes))
+	}
+	_ = w.Flush()
+
+	if q := usage.Quota; q != nil {
+		fmt.Println("\nQuota:")
+		fmt.Printf("  Concurrent builds:  %s\n", formatLimit(int64(q.MaxConcurrentBuilds), strconvLimit))
+		fmt.Printf("  Builds per day:     %s\n", formatLimit(int64(q.MaxBuildsPerDay), strconvLimit))
+		fmt.Printf("  Artifact storage:   %s\n", formatLimit(q.MaxArtifactStorageBytes, formatStorage))
+	}
+}
+
+// parseUsageTime parses a date or RFC 3339 time, an empty value meaning the server default
+func parseUsageTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func strconvLimit(n int64) string {
+	return fmt.Sprintf("%d", n)
+}
+
+func formatLimit(limit int64, format func(int64) string) string {
+	if limit <= 0 {
+		return "unlimited"
+	}
+	return format(limit)
+}
+
+// formatStorage formats a byte count with binary units
+func formatStorage(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -40,7 +41,9 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/notify"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/catalogimage"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/flashjob"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/image"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/imagebuild"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/operatorconfig"
@@ -70,6 +73,7 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var fakeJumpstarterExporter bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -81,6 +85,9 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&fakeJumpstarterExporter, "fake-jumpstarter-exporter", false,
+		"If set, FlashJobs lease a fake exporter that only prints the flash command instead of using Jumpstarter. "+
+			"For development and testing without hardware.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -144,10 +151,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	notifier := notify.New(mgr.GetClient(), imagebuild.OperatorNamespace, ctrl.Log.WithName("notifications"))
+	if err = mgr.Add(notifier); err != nil {
+		setupLog.Error(err, "unable to add notifier")
+		os.Exit(1)
+	}
+
 	imageBuildReconciler := &imagebuild.ImageBuildReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Log:    ctrl.Log.WithName("controllers").WithName("ImageBuild"),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      ctrl.Log.WithName("controllers").WithName("ImageBuild"),
+		Notifier: notifier,
 	}
 
 	if err = imageBuildReconciler.SetupWithManager(mgr); err != nil {
@@ -171,16 +185,29 @@ func main() {
 		Scheme: mgr.GetScheme(),
 		Log:    ctrl.Log.WithName("controllers").WithName("OperatorConfig"),
 	}
+	if fakeJumpstarterExporter {
+		// Report Jumpstarter as available so the build API accepts flash requests
+		operatorConfigReconciler.IsJumpstarter = ptr.To(true)
+	}
 
 	if err = operatorConfigReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
 		os.Exit(1)
 	}
 
+	catalogImageLog := ctrl.Log.WithName("controllers").WithName("CatalogImage")
 	catalogImageReconciler := &catalogimage.CatalogImageReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
-		Log:    ctrl.Log.WithName("controllers").WithName("CatalogImage"),
+		Log:    catalogImageLog,
+		RegistryClient: catalogimage.NewCircuitBreakerRegistryClient(
+			catalogimage.NewRegistryClient(),
+			catalogimage.NewCircuitBreakerRegistry(catalogimage.DefaultCircuitBreakerConfig(), catalogImageLog),
+		),
+		AuditRecorder: catalogimage.NewAuditRecorder(
+			mgr.GetEventRecorderFor("catalogimage-controller"), mgr.GetScheme()),
+		ConfigNamespace: imagebuild.OperatorNamespace,
+		Notifier:        notifier,
 	}
 
 	if err = catalogImageReconciler.SetupWithManager(mgr); err != nil {
@@ -188,6 +215,25 @@ func main() {
 		os.Exit(1)
 	}
 
+	var exporterLeaser flashjob.ExporterLeaser = flashjob.NewJumpstarterLeaser(
+		mgr.GetClient(), imagebuild.OperatorNamespace)
+	if fakeJumpstarterExporter {
+		setupLog.Info("using fake Jumpstarter exporter for FlashJobs")
+		exporterLeaser = flashjob.NewFakeExporterLeaser()
+	}
+	flashJobReconciler := &flashjob.FlashJobReconciler{
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		Log:             ctrl.Log.WithName("controllers").WithName("FlashJob"),
+		Leaser:          exporterLeaser,
+		ConfigNamespace: imagebuild.OperatorNamespace,
+	}
+
+	if err = flashJobReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FlashJob")
+		os.Exit(1)
+	}
+
 	// Health checks
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -0,0 +1,505 @@
+package buildapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	// correlationIDHeader carries the correlation ID of a request, accepted from callers and returned
+	correlationIDHeader = "X-Correlation-ID"
+	// apiTokenIDKey holds the ID of the API token a request authenticated with
+	apiTokenIDKey = "apiTokenID"
+	// auditNameKey holds the name of an object created by a request, when it is not in the path
+	auditNameKey = "auditName"
+	// auditResource is the resource callers need to list to query the audit trail
+	auditResource = "auditevents"
+
+	auditLogFile           = "audit.log"
+	defaultAuditMaxSizeMB  = 100
+	defaultAuditMaxBackups = 5
+	// maxRecentAuditEvents bounds the in-memory history queried when no audit file is configured
+	maxRecentAuditEvents  = 10000
+	auditWebhookQueueSize = 1000
+	defaultAuditLimit     = 100
+	maxAuditLimit         = 1000
+)
+
+// validCorrelationID restricts caller-supplied correlation IDs to safe annotation values
+var validCorrelationID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// auditRoute identifies an audited operation by method and route pattern
+type auditRoute struct {
+	method string
+	path   string
+}
+
+// auditActions names the audited build API operations
+var auditActions = map[auditRoute]string{
+	{http.MethodPost, "/v1/builds"}:                         "build.create",
+	{http.MethodPost, "/v1/builds/:name/uploads"}:           "build.upload",
+	{http.MethodPost, "/v1/builds/:name/flash"}:             "flash.create",
+	{http.MethodGet, "/v1/builds/:name/artifact"}:           "artifact.download",
+	{http.MethodGet, "/v1/builds/:name/artifacts/:file"}:    "artifact.download",
+	{http.MethodGet, "/v1/builds/:name/artifact/:filename"}: "artifact.download",
+	{http.MethodGet, "/v1/images/:name/download"}:           "image.download",
+	{http.MethodPost, "/v1/tokens"}:                         "token.create",
+	{http.MethodDelete, "/v1/tokens/:id"}:                   "token.revoke",
+	{http.MethodPost, "/v1/catalog/images"}:                 "catalog.add",
+	{http.MethodDelete, "/v1/catalog/images/:name"}:         "catalog.remove",
+	{http.MethodPost, "/v1/catalog/images/:name/verify"}:    "catalog.verify",
+	{http.MethodPost, "/v1/catalog/images/:name/promote"}:   "catalog.promote",
+	{http.MethodPost, "/v1/catalog/publish"}:                "catalog.publish",
+	{http.MethodPost, "/v1/catalog/webhooks/registry"}:      "catalog.registry-notification",
+	{http.MethodGet, "/v1/audit"}:                           "audit.query",
+}
+
+// auditLog writes audit events to stdout and the configured sinks, and keeps recent events for
+// queries when no audit file is configured
+type auditLog struct {
+	log    logr.Logger
+	stdout io.Writer
+	file   *rotatingFile
+	hook   *auditWebhook
+
+	mu     sync.Mutex
+	recent []AuditEvent
+}
+
+func newAuditLog(log logr.Logger) *auditLog {
+	return &auditLog{log: log, stdout: os.Stdout}
+}
+
+// record writes an event to every sink. Sink failures are logged and counted, never returned, so
+// they cannot fail the audited request.
+func (l *auditLog) record(event AuditEvent) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		l.log.Error(err, "failed to encode audit event")
+		return
+	}
+
+	if stdoutLine, err := json.Marshal(struct {
+		Audit AuditEvent `json:"audit"`
+	}{event}); err == nil {
+		l.write("stdout", l.stdout, append(stdoutLine, '\n'))
+	}
+	if l.file != nil {
+		l.write("file", l.file, append(line, '\n'))
+	} else {
+		l.mu.Lock()
+		l.recent = append(l.recent, event)
+		if len(l.recent) > maxRecentAuditEvents {
+			l.recent = slices.Clone(l.recent[len(l.recent)-maxRecentAuditEvents:])
+		}
+		l.mu.Unlock()
+	}
+	if l.hook != nil {
+		l.hook.enqueue(event)
+	}
+}
+
+func (l *auditLog) write(sink string, w io.Writer, line []byte) {
+	if _, err := w.Write(line); err != nil {
+		auditEventsTotal.WithLabelValues(sink, "failed").Inc()
+		l.log.Error(err, "failed to write audit event", "sink", sink)
+		return
+	}
+	auditEventsTotal.WithLabelValues(sink, "written").Inc()
+}
+
+// auditFilter selects audit events in a query
+type auditFilter struct {
+	user          string
+	action        string
+	correlationID string
+	since, until  time.Time
+	limit         int
+}
+
+func (f auditFilter) matches(event AuditEvent) bool {
+	if f.user != "" && event.User != f.user {
+		return false
+	}
+	if f.action != "" && event.Action != f.action {
+		return false
+	}
+	if f.correlationID != "" && event.CorrelationID != f.correlationID {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339Nano, event.Time)
+	if err != nil {
+		return false
+	}
+	return !t.Before(f.since) && (f.until.IsZero() || t.Before(f.until))
+}
+
+// query returns the newest events matching the filter, newest first
+func (l *auditLog) query(filter auditFilter) ([]AuditEvent, error) {
+	var matched []AuditEvent
+	if l.file != nil {
+		err := l.file.scan(func(line []byte) {
+			var event AuditEvent
+			if json.Unmarshal(line, &event) == nil && filter.matches(event) {
+				matched = append(matched, event)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		l.mu.Lock()
+		for _, event := range l.recent {
+			if filter.matches(event) {
+				matched = append(matched, event)
+			}
+		}
+		l.mu.Unlock()
+	}
+
+	slices.Reverse(matched)
+	if len(matched) > filter.limit {
+		matched = matched[:filter.limit]
+	}
+	return matched, nil
+}
+
+// rotatingFile is an append-only file renamed to path.1, path.2, ... once it reaches maxSize
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		_ = os.Rename(r.backup(i), r.backup(i+1))
+	}
+	if err := os.Rename(r.path, r.backup(1)); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) backup(i int) string {
+	return r.path + "." + strconv.Itoa(i)
+}
+
+// scan calls fn with every line of the backups and the current file, oldest first
+func (r *rotatingFile) scan(fn func(line []byte)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := r.maxBackups; i >= 0; i-- {
+		path := r.path
+		if i > 0 {
+			path = r.backup(i)
+		}
+		file, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			fn(scanner.Bytes())
+		}
+		err = scanner.Err()
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// auditWebhook posts audit events to an HTTP endpoint in the background, dropping events when the
+// endpoint falls behind so requests are never blocked
+type auditWebhook struct {
+	url    string
+	token  string
+	client *http.Client
+	log    logr.Logger
+	events chan AuditEvent
+}
+
+func (w *auditWebhook) enqueue(event AuditEvent) {
+	select {
+	case w.events <- event:
+	default:
+		auditEventsTotal.WithLabelValues("webhook", "dropped").Inc()
+	}
+}
+
+func (w *auditWebhook) run() {
+	for event := range w.events {
+		if err := w.post(event); err != nil {
+			auditEventsTotal.WithLabelValues("webhook", "failed").Inc()
+			w.log.Error(err, "failed to post audit event", "url", w.url)
+			continue
+		}
+		auditEventsTotal.WithLabelValues("webhook", "written").Inc()
+	}
+}
+
+func (w *auditWebhook) post(event AuditEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.token)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// EnableAudit adds the file and webhook sinks of the audit configuration to the audit trail
+func (a *APIServer) EnableAudit(cfg *automotivev1alpha1.AuditConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if file := cfg.File; file != nil {
+		maxSizeMB, maxBackups := int64(defaultAuditMaxSizeMB), defaultAuditMaxBackups
+		if file.MaxSizeMB > 0 {
+			maxSizeMB = int64(file.MaxSizeMB)
+		}
+		if file.MaxBackups > 0 {
+			maxBackups = int(file.MaxBackups)
+		}
+		path := filepath.Join(automotivev1alpha1.BuildAPIAuditLogDir, auditLogFile)
+		rotating, err := openRotatingFile(path, maxSizeMB<<20, maxBackups)
+		if err != nil {
+			return err
+		}
+		a.audit.file = rotating
+	}
+	if hook := cfg.Webhook; hook != nil {
+		token := ""
+		if hook.SecretName != "" {
+			kc, err := loadKubeClients()
+			if err != nil {
+				return err
+			}
+			secret := &corev1.Secret{}
+			key := types.NamespacedName{Name: hook.SecretName, Namespace: resolveNamespace()}
+			if err := kc.client.Get(context.Background(), key, secret); err != nil {
+				return fmt.Errorf("failed to read audit webhook secret: %w", err)
+			}
+			token = string(secret.Data["token"])
+		}
+		a.audit.hook = &auditWebhook{
+			url:    hook.URL,
+			token:  token,
+			client: &http.Client{Timeout: 10 * time.Second},
+			log:    a.log,
+			events: make(chan AuditEvent, auditWebhookQueueSize),
+		}
+		go a.audit.hook.run()
+	}
+	return nil
+}
+
+// correlationMiddleware assigns every request a correlation ID, reusing a valid one sent by the
+// caller, and returns it in the response
+func (a *APIServer) correlationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqID := c.GetHeader(correlationIDHeader)
+		if !validCorrelationID.MatchString(reqID) {
+			reqID = uuid.New().String()
+		}
+		c.Set("reqID", reqID)
+		c.Header(correlationIDHeader, reqID)
+		a.log.Info("http request", "method", c.Request.Method, "path", c.Request.URL.Path, "reqID", reqID)
+		c.Next()
+	}
+}
+
+// auditMiddleware records audited operations once they completed, including rejected attempts
+func (a *APIServer) auditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		action, ok := auditActions[auditRoute{c.Request.Method, c.FullPath()}]
+		if !ok {
+			return
+		}
+		event := AuditEvent{
+			Time:          time.Now().UTC().Format(time.RFC3339Nano),
+			CorrelationID: c.GetString("reqID"),
+			Action:        action,
+			Outcome:       auditOutcome(c.Writer.Status()),
+			Status:        c.Writer.Status(),
+			APITokenID:    c.GetString(apiTokenIDKey),
+			SourceIP:      c.ClientIP(),
+			Method:        c.Request.Method,
+			Path:          c.Request.URL.Path,
+			Namespace:     c.GetString(namespaceKey),
+			Name:          c.Param("name"),
+		}
+		if event.Namespace == "" {
+			event.Namespace = c.Query("namespace")
+		}
+		if name := c.GetString(auditNameKey); name != "" {
+			event.Name = name
+		} else if event.Name == "" {
+			event.Name = c.Param("id")
+		}
+		if user, ok := c.Get(userInfoKey); ok {
+			userInfo, _ := user.(authnv1.UserInfo)
+			event.User, event.Groups = userInfo.Username, userInfo.Groups
+		}
+		a.audit.record(event)
+	}
+}
+
+func auditOutcome(status int) string {
+	switch {
+	case status < 400:
+		return "success"
+	case status == http.StatusUnauthorized:
+		return "unauthenticated"
+	case status == http.StatusForbidden:
+		return "denied"
+	case status < 500:
+		return "rejected"
+	default:
+		return "error"
+	}
+}
+
+// handleQueryAudit returns audit events, newest first, to callers allowed to list auditevents
+// cluster-wide
+func (a *APIServer) handleQueryAudit(c *gin.Context) {
+	allowed, err := a.reviewAccess(c, authzv1.ResourceAttributes{
+		Verb:     "list",
+		Group:    automotivev1alpha1.GroupVersion.Group,
+		Resource: auditResource,
+	})
+	if err != nil {
+		a.log.Error(err, "access review failed", "reqID", c.GetString("reqID"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to authorize request"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden: cannot list " + auditResource})
+		return
+	}
+
+	filter := auditFilter{
+		user:          c.Query("user"),
+		action:        c.Query("action"),
+		correlationID: c.Query("correlationID"),
+		limit:         defaultAuditLimit,
+	}
+	if v := c.Query("since"); v != "" {
+		if filter.since, err = time.Parse(time.RFC3339, v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid since %q, expected RFC 3339 time", v)})
+			return
+		}
+	}
+	if v := c.Query("until"); v != "" {
+		if filter.until, err = time.Parse(time.RFC3339, v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid until %q, expected RFC 3339 time", v)})
+			return
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxAuditLimit)})
+			return
+		}
+		filter.limit = limit
+	}
+
+	events, err := a.audit.query(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error reading audit log: %v", err)})
+		return
+	}
+	if events == nil {
+		events = []AuditEvent{}
+	}
+	writeJSON(c, http.StatusOK, events)
+}
@@ -0,0 +1,116 @@
+package buildapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	authnv1 "k8s.io/api/authentication/v1"
+)
+
+var _ = Describe("Audit log", func() {
+	event := func(user, action string, t time.Time) AuditEvent {
+		return AuditEvent{Time: t.UTC().Format(time.RFC3339Nano), User: user, Action: action, Outcome: "success"}
+	}
+	now := time.Now()
+
+	It("should query recent events newest first", func() {
+		log := newAuditLog(logr.Discard())
+		log.stdout = &bytes.Buffer{}
+		log.record(event("jane", "build.create", now.Add(-3*time.Hour)))
+		log.record(event("joe", "build.create", now.Add(-2*time.Hour)))
+		log.record(event("jane", "artifact.download", now.Add(-time.Hour)))
+
+		events, err := log.query(auditFilter{user: "jane", limit: 10})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].Action).To(Equal("artifact.download"))
+
+		events, err = log.query(auditFilter{since: now.Add(-150 * time.Minute), limit: 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Action).To(Equal("artifact.download"))
+
+		Expect(log.stdout.(*bytes.Buffer).String()).To(HavePrefix(`{"audit":{`))
+	})
+
+	It("should rotate the audit file and query across backups", func() {
+		path := filepath.Join(GinkgoT().TempDir(), auditLogFile)
+		file, err := openRotatingFile(path, 300, 2)
+		Expect(err).NotTo(HaveOccurred())
+		log := newAuditLog(logr.Discard())
+		log.stdout = &bytes.Buffer{}
+		log.file = file
+
+		for i := range 10 {
+			log.record(event("jane", "build.create", now.Add(time.Duration(i)*time.Minute)))
+		}
+		Expect(path + ".2").To(BeAnExistingFile())
+		Expect(path + ".3").NotTo(BeAnExistingFile())
+		for _, p := range []string{path, path + ".1"} {
+			info, err := os.Stat(p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Size()).To(BeNumerically("<=", 300))
+		}
+
+		events, err := log.query(auditFilter{limit: 100})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(events)).To(BeNumerically(">", 1))
+		Expect(len(events)).To(BeNumerically("<", 10))
+		Expect(events[0].Time).To(Equal(now.Add(9 * time.Minute).UTC().Format(time.RFC3339Nano)))
+		Expect(log.recent).To(BeEmpty())
+	})
+
+	It("should record audited routes with their outcome and correlation ID", func() {
+		api := &APIServer{log: logr.Discard(), audit: newAuditLog(logr.Discard())}
+		api.audit.stdout = &bytes.Buffer{}
+		router := gin.New()
+		router.Use(api.correlationMiddleware(), api.auditMiddleware())
+		router.DELETE("/v1/tokens/:id", func(c *gin.Context) {
+			c.Set(userInfoKey, authnv1.UserInfo{Username: "jane", Groups: []string{"dev"}})
+			c.Status(http.StatusForbidden)
+		})
+		router.GET("/v1/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodDelete, "/v1/tokens/abc123", nil)
+		req.Header.Set(correlationIDHeader, "ci-run-42")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		Expect(w.Header().Get(correlationIDHeader)).To(Equal("ci-run-42"))
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/healthz", nil))
+
+		events, err := api.audit.query(auditFilter{limit: 10})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Action).To(Equal("token.revoke"))
+		Expect(events[0].Outcome).To(Equal("denied"))
+		Expect(events[0].CorrelationID).To(Equal("ci-run-42"))
+		Expect(events[0].User).To(Equal("jane"))
+		Expect(events[0].Name).To(Equal("abc123"))
+	})
+
+	It("should replace invalid correlation IDs", func() {
+		api := &APIServer{log: logr.Discard(), audit: newAuditLog(logr.Discard())}
+		router := gin.New()
+		router.Use(api.correlationMiddleware())
+		router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(correlationIDHeader, "bad id\n"+strings.Repeat("x", 80))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		id := w.Header().Get(correlationIDHeader)
+		Expect(validCorrelationID.MatchString(id)).To(BeTrue())
+		Expect(id).NotTo(ContainSubstring("bad"))
+	})
+})
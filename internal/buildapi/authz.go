@@ -0,0 +1,90 @@
+package buildapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// authorize returns middleware allowing a request only if the caller may perform verb on the
+// resource named in the request path, in the namespace resolved for the request. Subresources
+// such as imagebuilds/logs let cluster roles grant API operations that have no Kubernetes verb.
+func (a *APIServer) authorize(verb, resource, subresource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		attrs := authzv1.ResourceAttributes{
+			Namespace:   requestNamespace(c),
+			Verb:        verb,
+			Group:       automotivev1alpha1.GroupVersion.Group,
+			Resource:    resource,
+			Subresource: subresource,
+			Name:        c.Param("name"),
+		}
+
+		allowed, err := a.reviewAccess(c, attrs)
+		if err != nil {
+			a.log.Error(err, "access review failed", "verb", verb, "resource", resource, "reqID", c.GetString("reqID"))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to authorize request"})
+			return
+		}
+		if !allowed {
+			if subresource != "" {
+				resource += "/" + subresource
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": fmt.Sprintf("forbidden: cannot %s %s in namespace %q", verb, resource, attrs.Namespace),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// reviewAccess runs a SubjectAccessReview for the authenticated caller of the request. Callers
+// using an API token are further limited to the token's scopes.
+func (a *APIServer) reviewAccess(c *gin.Context, attrs authzv1.ResourceAttributes) (bool, error) {
+	user, ok := c.Get(userInfoKey)
+	if !ok {
+		return false, nil
+	}
+	userInfo, _ := user.(authnv1.UserInfo)
+	if !tokenScopesAllow(c, attrs) {
+		return false, nil
+	}
+
+	kc, err := loadKubeClients()
+	if err != nil {
+		return false, err
+	}
+
+	review := &authzv1.SubjectAccessReview{Spec: subjectAccessReviewSpec(userInfo, attrs)}
+	res, err := kc.clientset.AuthorizationV1().SubjectAccessReviews().Create(
+		c.Request.Context(), review, metav1.CreateOptions{},
+	)
+	if err != nil {
+		return false, fmt.Errorf("subject access review: %w", err)
+	}
+	return res.Status.Allowed, nil
+}
+
+// subjectAccessReviewSpec describes an operation of the user identified by a TokenReview
+func subjectAccessReviewSpec(user authnv1.UserInfo, attrs authzv1.ResourceAttributes) authzv1.SubjectAccessReviewSpec {
+	spec := authzv1.SubjectAccessReviewSpec{
+		ResourceAttributes: &attrs,
+		User:               user.Username,
+		Groups:             user.Groups,
+		UID:                user.UID,
+	}
+	if len(user.Extra) > 0 {
+		spec.Extra = make(map[string]authzv1.ExtraValue, len(user.Extra))
+		for key, value := range user.Extra {
+			spec.Extra[key] = authzv1.ExtraValue(value)
+		}
+	}
+	return spec
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	authzv1 "k8s.io/api/authorization/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// catalogImagesResource is the resource name of CatalogImages in access reviews
+const catalogImagesResource = "catalogimages"
+
+// AccessControl authenticates catalog API callers and authorizes their operations.
+// Its zero value allows every request.
+type AccessControl struct {
+	// Authenticate rejects requests without valid credentials
+	Authenticate gin.HandlerFunc
+
+	// Authorize reports whether the authenticated caller may perform the operation described by attrs
+	Authorize func(c *gin.Context, attrs authzv1.ResourceAttributes) (bool, error)
+
+	// Requester returns the user name of the authenticated caller, or an empty string if unknown
+	Requester func(c *gin.Context) string
+}
+
+// authorize returns middleware checking that the caller may perform verb on the catalog image named
+// in the request path. Lists without a namespace span all namespaces and are reviewed cluster-wide.
+func (h *Handler) authorize(verb, subresource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace := c.Query("namespace")
+		if namespace == "" && verb != "list" {
+			namespace = defaultNamespace
+		}
+		attrs := authzv1.ResourceAttributes{
+			Namespace:   namespace,
+			Verb:        verb,
+			Group:       automotivev1alpha1.GroupVersion.Group,
+			Resource:    catalogImagesResource,
+			Subresource: subresource,
+			Name:        c.Param("name"),
+		}
+		if !h.allowed(c, attrs) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// requester returns the user name of the authenticated caller, or an empty string if unknown
+func (h *Handler) requester(c *gin.Context) string {
+	if h.access.Requester == nil {
+		return ""
+	}
+	return h.access.Requester(c)
+}
+
+// allowed reports whether the caller may perform an operation, aborting the request if not
+func (h *Handler) allowed(c *gin.Context, attrs authzv1.ResourceAttributes) bool {
+	if h.access.Authorize == nil {
+		return true
+	}
+
+	ok, err := h.access.Authorize(c, attrs)
+	if err != nil {
+		h.log.Error(err, "access review failed", "verb", attrs.Verb, "resource", attrs.Resource)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to authorize request"})
+		return false
+	}
+	if !ok {
+		resource := attrs.Resource
+		if attrs.Subresource != "" {
+			resource += "/" + attrs.Subresource
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": fmt.Sprintf("forbidden: cannot %s %s in namespace %q", attrs.Verb, resource, attrs.Namespace),
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package catalog
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	authzv1 "k8s.io/api/authorization/v1"
+)
+
+var _ = Describe("Access control", func() {
+	var (
+		router   *gin.Engine
+		reviewed []authzv1.ResourceAttributes
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		reviewed = nil
+		router = gin.New()
+		RegisterRoutes(router.Group("/v1"), nil, logr.Discard(), "default", AccessControl{
+			Authorize: func(_ *gin.Context, attrs authzv1.ResourceAttributes) (bool, error) {
+				reviewed = append(reviewed, attrs)
+				return false, nil
+			},
+		})
+	})
+
+	It("should review catalog operations as catalogimages verbs", func() {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/catalog/images/autosd/promote", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusForbidden))
+		Expect(rec.Body.String()).To(ContainSubstring("cannot create catalogimages/promote"))
+		Expect(reviewed).To(ConsistOf(authzv1.ResourceAttributes{
+			Namespace:   "default",
+			Verb:        "create",
+			Group:       "automotive.sdv.cloud.redhat.com",
+			Resource:    catalogImagesResource,
+			Subresource: "promote",
+			Name:        "autosd",
+		}))
+	})
+
+	It("should review lists without a namespace cluster-wide", func() {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/catalog/images", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusForbidden))
+		Expect(reviewed).To(HaveLen(1))
+		Expect(reviewed[0].Verb).To(Equal("list"))
+		Expect(reviewed[0].Namespace).To(BeEmpty())
+	})
+})
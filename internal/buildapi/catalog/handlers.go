@@ -19,11 +19,13 @@ package catalog
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-logr/logr"
+	authzv1 "k8s.io/api/authorization/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -32,23 +34,39 @@ import (
 
 const (
 	defaultNamespace = "default"
+
+	// listPageSize is the page size used when reading catalog images from the API server
+	listPageSize = 500
+
+	// correlationIDKey and auditNameKey are the request context keys the build API uses for the
+	// correlation ID of a request and the name of the object it created
+	correlationIDKey = "reqID"
+	auditNameKey     = "auditName"
 )
 
 // Handler handles catalog API requests
 type Handler struct {
 	client client.Client
 	log    logr.Logger
+	// configNamespace is the namespace of the OperatorConfig defining promotion channels
+	configNamespace string
+	// access authorizes operations of the caller
+	access AccessControl
 }
 
 // NewHandler creates a new catalog API handler
-func NewHandler(client client.Client, log logr.Logger) *Handler {
+func NewHandler(client client.Client, log logr.Logger, configNamespace string, access AccessControl) *Handler {
 	return &Handler{
-		client: client,
-		log:    log.WithName("catalog-handler"),
+		client:          client,
+		log:             log.WithName("catalog-handler"),
+		configNamespace: configNamespace,
+		access:          access,
 	}
 }
 
-// HandleListCatalogImages lists catalog images with filtering
+// HandleListCatalogImages lists catalog images matching a query.
+// Label filters are applied by the API server; the query, sorting and pagination are evaluated here
+// over the full set, so continue tokens are cursors into the sorted result rather than Kubernetes tokens.
 func (h *Handler) HandleListCatalogImages(c *gin.Context) {
 	ctx := context.Background()
 
@@ -59,6 +77,36 @@ func (h *Handler) HandleListCatalogImages(c *gin.Context) {
 		return
 	}
 
+	query, err := parseQuery(params.Query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query", "details": err.Error()})
+		return
+	}
+	if params.Phase != "" {
+		query = append(query, queryTerm{field: "phase", op: "=", value: params.Phase})
+	}
+	if params.Tags != "" {
+		for _, tag := range strings.Split(params.Tags, ",") {
+			query = append(query, queryTerm{field: "tag", op: "=", value: strings.TrimSpace(tag)})
+		}
+	}
+
+	order, err := parseSortOrder(params.Sort)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort order", "details": err.Error()})
+		return
+	}
+	cursor, err := decodeCursor(params.Continue, order)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid continue token", "details": err.Error()})
+		return
+	}
+
+	limit := params.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
 	// Build list options
 	listOpts := []client.ListOption{}
 
@@ -79,6 +127,9 @@ func (h *Handler) HandleListCatalogImages(c *gin.Context) {
 	if params.Target != "" {
 		labelRequirements = append(labelRequirements, automotivev1alpha1.LabelTarget+"="+params.Target)
 	}
+	if params.Channel != "" {
+		labelRequirements = append(labelRequirements, automotivev1alpha1.LabelChannel+"="+params.Channel)
+	}
 
 	if len(labelRequirements) > 0 {
 		selectorStr := strings.Join(labelRequirements, ",")
@@ -90,52 +141,48 @@ func (h *Handler) HandleListCatalogImages(c *gin.Context) {
 		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
 	}
 
-	// Apply limit
-	if params.Limit > 0 && params.Limit <= 100 {
-		listOpts = append(listOpts, client.Limit(int64(params.Limit)))
-	} else {
-		listOpts = append(listOpts, client.Limit(20))
-	}
-
-	// Apply continue token
-	if params.Continue != "" {
-		listOpts = append(listOpts, client.Continue(params.Continue))
-	}
-
-	// List catalog images
-	catalogImages := &automotivev1alpha1.CatalogImageList{}
-	if err := h.client.List(ctx, catalogImages, listOpts...); err != nil {
+	matched, err := h.listMatchingImages(ctx, query, listOpts)
+	if err != nil {
 		h.log.Error(err, "failed to list catalog images")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list catalog images"})
 		return
 	}
 
-	// Filter by phase if specified (post-filter since phase is in status)
-	if params.Phase != "" {
-		filtered := []automotivev1alpha1.CatalogImage{}
-		for _, img := range catalogImages.Items {
-			if string(img.Status.Phase) == params.Phase {
-				filtered = append(filtered, img)
-			}
+	order.sortImages(matched)
+	page, next := order.paginate(matched, cursor, limit)
+
+	response := ToCatalogImageListResponse(&automotivev1alpha1.CatalogImageList{Items: page}, next)
+	response.Total = len(matched)
+	c.JSON(http.StatusOK, response)
+}
+
+// listMatchingImages lists all catalog images selected by listOpts that match the query
+func (h *Handler) listMatchingImages(
+	ctx context.Context, query catalogQuery, listOpts []client.ListOption,
+) ([]automotivev1alpha1.CatalogImage, error) {
+	var matched []automotivev1alpha1.CatalogImage
+	continueToken := ""
+	for {
+		opts := append(append([]client.ListOption{}, listOpts...), client.Limit(listPageSize))
+		if continueToken != "" {
+			opts = append(opts, client.Continue(continueToken))
 		}
-		catalogImages.Items = filtered
-	}
 
-	// Filter by tags if specified
-	if params.Tags != "" {
-		requestedTags := strings.Split(params.Tags, ",")
-		filtered := []automotivev1alpha1.CatalogImage{}
-		for _, img := range catalogImages.Items {
-			if hasAllTags(img.Spec.Tags, requestedTags) {
-				filtered = append(filtered, img)
+		catalogImages := &automotivev1alpha1.CatalogImageList{}
+		if err := h.client.List(ctx, catalogImages, opts...); err != nil {
+			return nil, err
+		}
+		for i := range catalogImages.Items {
+			if query.matches(&catalogImages.Items[i]) {
+				matched = append(matched, catalogImages.Items[i])
 			}
 		}
-		catalogImages.Items = filtered
-	}
 
-	// Convert to response
-	response := ToCatalogImageListResponse(catalogImages, catalogImages.Continue)
-	c.JSON(http.StatusOK, response)
+		continueToken = catalogImages.Continue
+		if continueToken == "" {
+			return matched, nil
+		}
+	}
 }
 
 // HandleGetCatalogImage gets a specific catalog image
@@ -159,6 +206,10 @@ func (h *Handler) HandleGetCatalogImage(c *gin.Context) {
 		return
 	}
 
+	if warning := LifecycleWarning(catalogImage); warning != "" {
+		c.Header("Warning", fmt.Sprintf("299 - %q", warning))
+	}
+
 	// Increment access count (best effort, don't fail the request on error)
 	catalogImage.Status.AccessCount++
 	if err := h.client.Status().Update(ctx, catalogImage); err != nil {
@@ -197,9 +248,14 @@ func (h *Handler) HandleCreateCatalogImage(c *gin.Context) {
 	catalogImage := &automotivev1alpha1.CatalogImage{}
 	catalogImage.Name = req.Name
 	catalogImage.Namespace = namespace
+	catalogImage.Annotations = map[string]string{
+		automotivev1alpha1.CorrelationIDAnnotation: c.GetString(correlationIDKey),
+	}
+	c.Set(auditNameKey, req.Name)
 	catalogImage.Spec = automotivev1alpha1.CatalogImageSpec{
 		RegistryURL: req.RegistryURL,
 		Digest:      req.Digest,
+		PinDigest:   req.PinDigest,
 		Tags:        req.Tags,
 	}
 
@@ -317,6 +373,18 @@ func (h *Handler) HandlePublishImageBuild(c *gin.Context) {
 		return
 	}
 
+	// Publishing reads the ImageBuild and creates a CatalogImage next to it
+	for _, attrs := range []authzv1.ResourceAttributes{
+		{Verb: "get", Resource: "imagebuilds", Name: req.ImageBuildName},
+		{Verb: "create", Resource: catalogImagesResource},
+	} {
+		attrs.Namespace = req.ImageBuildNamespace
+		attrs.Group = automotivev1alpha1.GroupVersion.Group
+		if !h.allowed(c, attrs) {
+			return
+		}
+	}
+
 	// Get the ImageBuild
 	imageBuild := &automotivev1alpha1.ImageBuild{}
 	if err := h.client.Get(
@@ -360,6 +428,10 @@ func (h *Handler) HandlePublishImageBuild(c *gin.Context) {
 	catalogImage := &automotivev1alpha1.CatalogImage{}
 	catalogImage.Name = catalogImageName
 	catalogImage.Namespace = req.ImageBuildNamespace
+	catalogImage.Annotations = map[string]string{
+		automotivev1alpha1.CorrelationIDAnnotation: c.GetString(correlationIDKey),
+	}
+	c.Set(auditNameKey, catalogImageName)
 	catalogImage.Spec = automotivev1alpha1.CatalogImageSpec{
 		RegistryURL: registryURL,
 		Tags:        req.Tags,
@@ -390,17 +462,3 @@ func (h *Handler) HandlePublishImageBuild(c *gin.Context) {
 	response := ToCatalogImageResponse(catalogImage)
 	c.JSON(http.StatusCreated, response)
 }
-
-// hasAllTags checks if the image has all the requested tags
-func hasAllTags(imageTags, requestedTags []string) bool {
-	tagSet := make(map[string]bool)
-	for _, t := range imageTags {
-		tagSet[t] = true
-	}
-	for _, t := range requestedTags {
-		if !tagSet[strings.TrimSpace(t)] {
-			return false
-		}
-	}
-	return true
-}
@@ -17,8 +17,11 @@ limitations under the License.
 package catalog
 
 import (
+	"fmt"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
 )
 
@@ -46,9 +49,44 @@ type CatalogImageResponse struct {
 	Labels           map[string]string     `json:"labels,omitempty"`
 	ArtifactRefs     []ArtifactRefInfo     `json:"artifactRefs,omitempty"`
 	DownloadURL      string                `json:"downloadUrl,omitempty"`
+	MirrorURL        string                `json:"mirrorUrl,omitempty"`
 	IsMultiArch      bool                  `json:"isMultiArch,omitempty"`
 	PlatformVariants []PlatformVariantInfo `json:"platformVariants,omitempty"`
 	AccessCount      int64                 `json:"accessCount,omitempty"`
+	Channel          string                `json:"channel,omitempty"`
+	Lifecycle        string                `json:"lifecycle"`
+	LifecycleReason  string                `json:"lifecycleReason,omitempty"`
+	ReplacedBy       string                `json:"replacedBy,omitempty"`
+	PromotionHistory []PromotionRecordInfo `json:"promotionHistory,omitempty"`
+	PendingApprovals []PendingApprovalInfo `json:"pendingApprovals,omitempty"`
+	DigestChanged    bool                  `json:"digestChanged,omitempty"`
+	DigestHistory    []DigestRecordInfo    `json:"digestHistory,omitempty"`
+}
+
+// DigestRecordInfo represents a digest the image's tag resolved to in API responses
+type DigestRecordInfo struct {
+	Digest    string    `json:"digest"`
+	FirstSeen time.Time `json:"firstSeen"`
+}
+
+// PromotionRecordInfo represents a promotion history entry in API responses
+type PromotionRecordInfo struct {
+	SourceImage  string    `json:"sourceImage"`
+	SourceDigest string    `json:"sourceDigest,omitempty"`
+	FromChannel  string    `json:"fromChannel,omitempty"`
+	Channel      string    `json:"channel"`
+	PromotedBy   string    `json:"promotedBy,omitempty"`
+	ApprovedBy   string    `json:"approvedBy,omitempty"`
+	PromotedAt   time.Time `json:"promotedAt"`
+}
+
+// PendingApprovalInfo represents a promotion into a protected channel awaiting approval
+type PendingApprovalInfo struct {
+	Channel     string    `json:"channel"`
+	TargetName  string    `json:"targetName,omitempty"`
+	Digest      string    `json:"digest,omitempty"`
+	RequestedBy string    `json:"requestedBy"`
+	RequestedAt time.Time `json:"requestedAt"`
 }
 
 // ArtifactRefInfo represents artifact reference information in responses
@@ -80,9 +118,10 @@ type HardwareTargetInfo struct {
 //
 //nolint:revive // Name intentionally includes package name for clarity in external API
 type CatalogImageListResponse struct {
-	Items    []CatalogImageResponse `json:"items"`
-	Total    int                    `json:"total"`
-	Continue string                 `json:"continue,omitempty"`
+	Items []CatalogImageResponse `json:"items"`
+	// Total is the number of images matching the query across all pages
+	Total    int    `json:"total"`
+	Continue string `json:"continue,omitempty"`
 }
 
 // CreateCatalogImageRequest represents a request to create a catalog image
@@ -90,6 +129,7 @@ type CreateCatalogImageRequest struct {
 	Name           string               `json:"name" binding:"required"`
 	RegistryURL    string               `json:"registryUrl" binding:"required"`
 	Digest         string               `json:"digest,omitempty"`
+	PinDigest      bool                 `json:"pinDigest,omitempty"`
 	Tags           []string             `json:"tags,omitempty"`
 	AuthSecretName string               `json:"authSecretName,omitempty"`
 	Architecture   string               `json:"architecture,omitempty"`
@@ -107,12 +147,33 @@ type PublishImageBuildRequest struct {
 	Tags                []string `json:"tags,omitempty"`
 }
 
+// PromoteImageRequest represents a request to promote a catalog image into a channel
+type PromoteImageRequest struct {
+	Channel string `json:"channel" binding:"required"`
+	Name    string `json:"name,omitempty"`
+}
+
+// PromoteImageResponse represents the result of a promotion request
+type PromoteImageResponse struct {
+	Message  string                `json:"message"`
+	Pending  bool                  `json:"pending"`
+	Image    *CatalogImageResponse `json:"image,omitempty"`
+	Approval *PendingApprovalInfo  `json:"approval,omitempty"`
+}
+
 // VerifyImageResponse represents the response from verifying an image
 type VerifyImageResponse struct {
 	Message   string `json:"message"`
 	Triggered bool   `json:"triggered"`
 }
 
+// RegistryWebhookResponse reports the catalog images affected by a registry notification
+type RegistryWebhookResponse struct {
+	Events      int      `json:"events"`
+	Reverified  []string `json:"reverified,omitempty"`
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
 // ListQueryParams represents query parameters for listing catalog images
 type ListQueryParams struct {
 	Namespace    string `form:"namespace"`
@@ -121,6 +182,9 @@ type ListQueryParams struct {
 	Target       string `form:"target"`
 	Phase        string `form:"phase"`
 	Tags         string `form:"tags"`
+	Channel      string `form:"channel"`
+	Query        string `form:"q"`
+	Sort         string `form:"sort"`
 	Limit        int    `form:"limit,default=20"`
 	Continue     string `form:"continue"`
 }
@@ -184,6 +248,37 @@ func ToCatalogImageResponse(catalogImage *automotivev1alpha1.CatalogImage) Catal
 
 	response.SourceImageBuild = catalogImage.Status.SourceImageBuild
 	response.AccessCount = catalogImage.Status.AccessCount
+	response.Channel = catalogImage.Spec.Channel
+	response.Lifecycle = string(catalogImage.EffectiveLifecycleState())
+	if lifecycle := catalogImage.Spec.Lifecycle; lifecycle != nil {
+		response.LifecycleReason = lifecycle.Reason
+		response.ReplacedBy = lifecycle.ReplacedBy
+	}
+
+	// Extract promotion history and pending approvals
+	for _, record := range catalogImage.Status.PromotionHistory {
+		response.PromotionHistory = append(response.PromotionHistory, PromotionRecordInfo{
+			SourceImage:  record.SourceImage,
+			SourceDigest: record.SourceDigest,
+			FromChannel:  record.FromChannel,
+			Channel:      record.Channel,
+			PromotedBy:   record.PromotedBy,
+			ApprovedBy:   record.ApprovedBy,
+			PromotedAt:   record.PromotedAt.Time,
+		})
+	}
+	for _, record := range catalogImage.Status.DigestHistory {
+		response.DigestHistory = append(response.DigestHistory, DigestRecordInfo{
+			Digest:    record.Digest,
+			FirstSeen: record.FirstSeen.Time,
+		})
+	}
+	response.DigestChanged = meta.IsStatusConditionTrue(catalogImage.Status.Conditions,
+		automotivev1alpha1.CatalogImageConditionDigestChanged)
+	for i := range catalogImage.Status.PendingPromotions {
+		response.PendingApprovals = append(response.PendingApprovals,
+			toPendingApprovalInfo(&catalogImage.Status.PendingPromotions[i]))
+	}
 
 	// Extract artifact references
 	for _, ref := range catalogImage.Status.ArtifactRefs {
@@ -196,14 +291,54 @@ func ToCatalogImageResponse(catalogImage *automotivev1alpha1.CatalogImage) Catal
 		})
 	}
 
-	// Resolve download URL - prefer first artifact, fallback to registry URL for bootc images
+	// Resolve download URL - prefer the mirror, then the first artifact, fallback to registry URL for bootc images
 	response.DownloadURL = resolveDownloadURL(catalogImage)
+	if mirror := catalogImage.MirrorArtifact(); mirror != nil {
+		response.MirrorURL = mirror.URL
+	}
 
 	return response
 }
 
+// toPendingApprovalInfo converts a pending promotion to an API response
+func toPendingApprovalInfo(pending *automotivev1alpha1.PendingPromotion) PendingApprovalInfo {
+	return PendingApprovalInfo{
+		Channel:     pending.Channel,
+		TargetName:  pending.TargetName,
+		Digest:      pending.Digest,
+		RequestedBy: pending.RequestedBy,
+		RequestedAt: pending.RequestedAt.Time,
+	}
+}
+
+// LifecycleWarning returns a warning for clients using a deprecated or end-of-life image,
+// or an empty string for active images
+func LifecycleWarning(catalogImage *automotivev1alpha1.CatalogImage) string {
+	var warning string
+	switch catalogImage.EffectiveLifecycleState() {
+	case automotivev1alpha1.LifecycleDeprecated:
+		warning = fmt.Sprintf("catalog image %s is deprecated", catalogImage.Name)
+	case automotivev1alpha1.LifecycleEOL:
+		warning = fmt.Sprintf("catalog image %s is end-of-life and no longer verified", catalogImage.Name)
+	default:
+		return ""
+	}
+	if reason := catalogImage.Spec.Lifecycle.Reason; reason != "" {
+		warning += ": " + reason
+	}
+	if replacement := catalogImage.Spec.Lifecycle.ReplacedBy; replacement != "" {
+		warning += "; use " + replacement + " instead"
+	}
+	return warning
+}
+
 // resolveDownloadURL determines the best download URL for a catalog image
 func resolveDownloadURL(catalogImage *automotivev1alpha1.CatalogImage) string {
+	// Prefer the local mirror copy when the image is mirrored
+	if mirror := catalogImage.MirrorArtifact(); mirror != nil {
+		return mirror.URL
+	}
+
 	// If we have artifact references, use the first one
 	if len(catalogImage.Status.ArtifactRefs) > 0 {
 		return catalogImage.Status.ArtifactRefs[0].URL
@@ -0,0 +1,33 @@
+package catalog
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+var _ = Describe("Catalog image responses", func() {
+	It("should prefer the mirror for downloads", func() {
+		img := queryTestImage("autosd-qemu", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 1<<30)
+		img.Status.ArtifactRefs = []automotivev1alpha1.ArtifactReference{
+			{Type: "qcow2", URL: "https://example.com/autosd-qemu.qcow2"},
+			{
+				Type:   "container",
+				URL:    "registry.local/catalog/autosd-qemu@sha256:abc",
+				Format: automotivev1alpha1.ArtifactFormatMirror,
+			},
+		}
+
+		response := ToCatalogImageResponse(&img)
+		Expect(response.DownloadURL).To(Equal("registry.local/catalog/autosd-qemu@sha256:abc"))
+		Expect(response.MirrorURL).To(Equal(response.DownloadURL))
+
+		img.Status.ArtifactRefs = img.Status.ArtifactRefs[:1]
+		response = ToCatalogImageResponse(&img)
+		Expect(response.DownloadURL).To(Equal("https://example.com/autosd-qemu.qcow2"))
+		Expect(response.MirrorURL).To(BeEmpty())
+	})
+})
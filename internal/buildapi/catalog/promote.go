@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/catalogimage"
+)
+
+const (
+	// operatorConfigName is the name of the OperatorConfig defining promotion channels
+	operatorConfigName = "config"
+
+	// promoteTimeout bounds the registry copy of a promotion
+	promoteTimeout = 30 * time.Minute
+)
+
+// HandlePromoteCatalogImage promotes a catalog image into a promotion channel.
+// Promotions into protected channels are accepted as pending until a different user promotes the
+// image into the same channel, which approves and carries out the promotion.
+func (h *Handler) HandlePromoteCatalogImage(c *gin.Context) {
+	ctx := context.Background()
+	name := c.Param("name")
+	namespace := c.Query("namespace")
+
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	var req PromoteImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	requester := h.requester(c)
+	if requester == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "promotion requires an authenticated user"})
+		return
+	}
+
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	if err := h.client.Get(
+		ctx, client.ObjectKey{Name: operatorConfigName, Namespace: h.configNamespace}, operatorConfig,
+	); client.IgnoreNotFound(err) != nil {
+		h.log.Error(err, "failed to get operator config")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get promotion channels"})
+		return
+	}
+	channel := catalogimage.FindChannel(operatorConfig, req.Channel)
+	if channel == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown promotion channel %q", req.Channel)})
+		return
+	}
+
+	source := &automotivev1alpha1.CatalogImage{}
+	if err := h.client.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, source); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "catalog image not found"})
+			return
+		}
+		h.log.Error(err, "failed to get catalog image", "name", name, "namespace", namespace)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get catalog image"})
+		return
+	}
+	if source.Status.Phase != automotivev1alpha1.CatalogImagePhaseAvailable {
+		c.JSON(http.StatusConflict, gin.H{"error": "only available catalog images can be promoted",
+			"phase": source.Status.Phase})
+		return
+	}
+
+	promoteCtx, cancel := context.WithTimeout(ctx, promoteTimeout)
+	defer cancel()
+
+	publisher := catalogimage.NewPublisher(h.client, catalogimage.NewRegistryClient(), nil, h.log)
+	result, err := publisher.Promote(promoteCtx, catalogimage.PromoteOptions{
+		Source:          source,
+		Channel:         channel,
+		TargetName:      strings.TrimSpace(req.Name),
+		Requester:       requester,
+		ConfigNamespace: h.configNamespace,
+	})
+	switch {
+	case errors.Is(err, catalogimage.ErrAlreadyInChannel),
+		errors.Is(err, catalogimage.ErrApprovalPending),
+		errors.Is(err, catalogimage.ErrPromotionTargetExists),
+		errors.Is(err, catalogimage.ErrPromotionSourceChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		h.log.Error(err, "failed to promote catalog image", "name", name, "channel", channel.Name)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to promote catalog image", "details": err.Error()})
+		return
+	}
+
+	if result.Pending {
+		approval := toPendingApprovalInfo(catalogimage.PendingPromotionFor(source, channel.Name))
+		h.log.Info("promotion awaiting approval", "name", name, "channel", channel.Name, "requestedBy", requester)
+		c.JSON(http.StatusAccepted, PromoteImageResponse{
+			Message:  fmt.Sprintf("channel %s is protected: promotion awaits approval by a second user", channel.Name),
+			Pending:  true,
+			Approval: &approval,
+		})
+		return
+	}
+
+	h.log.Info("promoted catalog image", "name", name, "channel", channel.Name,
+		"promoted", result.CatalogImage.Name, "promotedBy", result.Record.PromotedBy,
+		"approvedBy", result.Record.ApprovedBy)
+	response := ToCatalogImageResponse(result.CatalogImage)
+	c.JSON(http.StatusCreated, PromoteImageResponse{
+		Message: fmt.Sprintf("promoted to channel %s as %s", channel.Name, result.CatalogImage.Name),
+		Image:   &response,
+	})
+}
@@ -0,0 +1,73 @@
+package catalog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// promoteClient serves the OperatorConfig and source CatalogImage read by the promote handler
+type promoteClient struct {
+	client.Client
+}
+
+func (promoteClient) Get(_ context.Context, _ client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	switch o := obj.(type) {
+	case *automotivev1alpha1.OperatorConfig:
+		o.Spec.Catalog = &automotivev1alpha1.CatalogConfig{
+			Channels: []automotivev1alpha1.PromotionChannel{{Name: "qa"}},
+		}
+	case *automotivev1alpha1.CatalogImage:
+		o.Status.Phase = automotivev1alpha1.CatalogImagePhasePending
+	}
+	return nil
+}
+
+var _ = Describe("Promotion requester", func() {
+	promote := func(access AccessControl) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		RegisterRoutes(router.Group("/v1"), promoteClient{}, logr.Discard(), "default", access)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/catalog/images/autosd/promote",
+			strings.NewReader(`{"channel":"qa"}`)))
+		return rec
+	}
+
+	It("should take the requester from the identity resolved by authentication", func() {
+		var requested []string
+		rec := promote(AccessControl{
+			// An OIDC login resolved by the server's auth middleware, with no cluster token to review
+			Authenticate: func(c *gin.Context) {
+				c.Set("user", "oidc:jane")
+				c.Next()
+			},
+			Requester: func(c *gin.Context) string {
+				user := c.GetString("user")
+				requested = append(requested, user)
+				return user
+			},
+		})
+
+		Expect(requested).To(ConsistOf("oidc:jane"))
+		Expect(rec.Code).To(Equal(http.StatusConflict))
+		Expect(rec.Body.String()).To(ContainSubstring("only available catalog images can be promoted"))
+	})
+
+	It("should reject promotions without an authenticated user", func() {
+		rec := promote(AccessControl{})
+
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		Expect(rec.Body.String()).To(ContainSubstring("promotion requires an authenticated user"))
+	})
+})
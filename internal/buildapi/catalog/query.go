@@ -0,0 +1,563 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/catalogimage"
+)
+
+// fieldKind determines how the values of a query field are compared
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindNumber
+	kindTime
+	kindBool
+)
+
+// queryField describes a field of a catalog image that queries can match on.
+// String and bool fields are read with values; number and time fields with number.
+type queryField struct {
+	kind   fieldKind
+	values func(img *automotivev1alpha1.CatalogImage) []string
+	number func(img *automotivev1alpha1.CatalogImage) (int64, bool)
+}
+
+// queryFields maps query field names, lowercased, to catalog image fields
+var queryFields = map[string]*queryField{
+	"name": stringField(func(img *automotivev1alpha1.CatalogImage) []string {
+		return []string{img.Name}
+	}),
+	"namespace": stringField(func(img *automotivev1alpha1.CatalogImage) []string {
+		return []string{img.Namespace}
+	}),
+	"registry": stringField(func(img *automotivev1alpha1.CatalogImage) []string {
+		return []string{img.Spec.RegistryURL}
+	}),
+	"digest": stringField(imageDigests),
+	"tag": stringField(func(img *automotivev1alpha1.CatalogImage) []string {
+		return img.Spec.Tags
+	}),
+	"arch": stringField(imageArchitectures),
+	"distro": metadataField(func(m *automotivev1alpha1.CatalogImageMetadata) string {
+		return m.Distro
+	}),
+	"distroversion": metadataField(func(m *automotivev1alpha1.CatalogImageMetadata) string {
+		return m.DistroVersion
+	}),
+	"target": stringField(imageTargets),
+	"mode": metadataField(func(m *automotivev1alpha1.CatalogImageMetadata) string {
+		return m.BuildMode
+	}),
+	"format": metadataField(func(m *automotivev1alpha1.CatalogImageMetadata) string {
+		return m.ExportFormat
+	}),
+	"bootc": {kind: kindBool, values: func(img *automotivev1alpha1.CatalogImage) []string {
+		return []string{fmt.Sprint(img.Spec.Metadata != nil && img.Spec.Metadata.Bootc)}
+	}},
+	"multiarch": {kind: kindBool, values: func(img *automotivev1alpha1.CatalogImage) []string {
+		return []string{fmt.Sprint(img.Status.RegistryMetadata != nil && img.Status.RegistryMetadata.IsMultiArch)}
+	}},
+	"phase": stringField(func(img *automotivev1alpha1.CatalogImage) []string {
+		return []string{string(img.Status.Phase)}
+	}),
+	"channel": stringField(func(img *automotivev1alpha1.CatalogImage) []string {
+		return []string{img.Spec.Channel}
+	}),
+	"lifecycle": stringField(func(img *automotivev1alpha1.CatalogImage) []string {
+		return []string{string(img.EffectiveLifecycleState())}
+	}),
+	"source": stringField(func(img *automotivev1alpha1.CatalogImage) []string {
+		return []string{img.Status.SourceImageBuild}
+	}),
+	"promotedby": promotionField(func(r *automotivev1alpha1.PromotionRecord) string {
+		return r.PromotedBy
+	}),
+	"approvedby": promotionField(func(r *automotivev1alpha1.PromotionRecord) string {
+		return r.ApprovedBy
+	}),
+	"published": timeField(func(img *automotivev1alpha1.CatalogImage) *metav1.Time {
+		return img.Status.PublishedAt
+	}),
+	"verified": timeField(func(img *automotivev1alpha1.CatalogImage) *metav1.Time {
+		return img.Status.LastVerificationTime
+	}),
+	"created": timeField(func(img *automotivev1alpha1.CatalogImage) *metav1.Time {
+		return &img.CreationTimestamp
+	}),
+	"size": {kind: kindNumber, number: func(img *automotivev1alpha1.CatalogImage) (int64, bool) {
+		if img.Status.RegistryMetadata == nil {
+			return 0, false
+		}
+		return img.Status.RegistryMetadata.SizeBytes, true
+	}},
+	"layers": {kind: kindNumber, number: func(img *automotivev1alpha1.CatalogImage) (int64, bool) {
+		if img.Status.RegistryMetadata == nil {
+			return 0, false
+		}
+		return int64(img.Status.RegistryMetadata.LayerCount), true
+	}},
+	"accesses": {kind: kindNumber, number: func(img *automotivev1alpha1.CatalogImage) (int64, bool) {
+		return img.Status.AccessCount, true
+	}},
+}
+
+// queryFieldAliases maps alternative field names to their canonical name
+var queryFieldAliases = map[string]string{
+	"architecture": "arch",
+	"tags":         "tag",
+	"registryurl":  "registry",
+	"version":      "distroversion",
+	"buildmode":    "mode",
+	"exportformat": "format",
+	"imagebuild":   "source",
+	"publishedat":  "published",
+	"accesscount":  "accesses",
+	"layercount":   "layers",
+}
+
+// queryOperators are the comparison operators of query terms, longest first
+var queryOperators = []string{">=", "<=", "!=", "!~", "=", "~", ">", "<", ":"}
+
+// queryTerm is a single condition of a catalog query. Terms without a field match text anywhere
+// in the image's name, registry URL, metadata, tags and channel.
+type queryTerm struct {
+	field string
+	op    string
+	value string
+
+	number int64
+}
+
+// catalogQuery is a parsed catalog query; an image matches if it matches all terms
+type catalogQuery []queryTerm
+
+// parseQuery parses a query such as `distro=autosd arch=arm64 target~rpi tag:release published>2026-01-01`.
+// Terms are separated by whitespace and values containing spaces may be double-quoted.
+func parseQuery(q string) (catalogQuery, error) {
+	tokens, err := tokenizeQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	query := make(catalogQuery, 0, len(tokens))
+	for _, token := range tokens {
+		term, err := parseQueryTerm(token)
+		if err != nil {
+			return nil, err
+		}
+		query = append(query, term)
+	}
+	return query, nil
+}
+
+// tokenizeQuery splits a query on whitespace outside of double quotes and removes the quotes
+func tokenizeQuery(q string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	inQuotes, inToken := false, false
+
+	for _, r := range q {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			inToken = true
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n'):
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in query")
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+// parseQueryTerm parses a single `field<op>value` term or a free-text word
+func parseQueryTerm(token string) (queryTerm, error) {
+	idx := strings.IndexAny(token, "=!<>~:")
+	if idx < 0 {
+		return queryTerm{value: token}, nil
+	}
+
+	var op string
+	for _, candidate := range queryOperators {
+		if strings.HasPrefix(token[idx:], candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return queryTerm{}, fmt.Errorf("invalid query term %q", token)
+	}
+
+	name := strings.ToLower(token[:idx])
+	if name == "" {
+		return queryTerm{}, fmt.Errorf("query term %q has no field", token)
+	}
+	if canonical, ok := queryFieldAliases[name]; ok {
+		name = canonical
+	}
+	field, ok := queryFields[name]
+	if !ok {
+		return queryTerm{}, fmt.Errorf("unknown query field %q", name)
+	}
+
+	term := queryTerm{field: name, op: op, value: token[idx+len(op):]}
+	if term.op == ":" {
+		term.op = "="
+	}
+
+	switch field.kind {
+	case kindString:
+		if term.op != "=" && term.op != "!=" && term.op != "~" && term.op != "!~" {
+			return queryTerm{}, fmt.Errorf("operator %s is not supported for field %s", term.op, name)
+		}
+		if name == "arch" {
+			term.value = catalogimage.NormalizeArchitecture(term.value)
+		}
+	case kindBool:
+		if term.op != "=" && term.op != "!=" {
+			return queryTerm{}, fmt.Errorf("operator %s is not supported for field %s", term.op, name)
+		}
+		switch strings.ToLower(term.value) {
+		case "true", "yes":
+			term.value = "true"
+		case "false", "no":
+			term.value = "false"
+		default:
+			return queryTerm{}, fmt.Errorf("field %s expects true or false, got %q", name, term.value)
+		}
+	case kindNumber:
+		if strings.Contains(term.op, "~") {
+			return queryTerm{}, fmt.Errorf("operator %s is not supported for field %s", term.op, name)
+		}
+		qty, err := resource.ParseQuantity(term.value)
+		if err != nil {
+			return queryTerm{}, fmt.Errorf("field %s expects a number such as 100, 500M or 2Gi, got %q", name, term.value)
+		}
+		term.number = qty.Value()
+	case kindTime:
+		if strings.Contains(term.op, "~") {
+			return queryTerm{}, fmt.Errorf("operator %s is not supported for field %s", term.op, name)
+		}
+		t, err := parseQueryTime(term.value)
+		if err != nil {
+			return queryTerm{}, fmt.Errorf("field %s expects a date such as 2026-01-01, got %q", name, term.value)
+		}
+		term.number = t.UnixNano()
+	}
+	return term, nil
+}
+
+// parseQueryTime parses an RFC 3339 timestamp or a date
+func parseQueryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// matches reports whether the image matches all terms of the query
+func (q catalogQuery) matches(img *automotivev1alpha1.CatalogImage) bool {
+	for i := range q {
+		if !q[i].matches(img) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether the image matches the term
+func (t *queryTerm) matches(img *automotivev1alpha1.CatalogImage) bool {
+	if t.field == "" {
+		return matchesText(img, t.value)
+	}
+
+	field := queryFields[t.field]
+	switch field.kind {
+	case kindNumber, kindTime:
+		n, ok := field.number(img)
+		if !ok {
+			return t.op == "!="
+		}
+		switch t.op {
+		case "=":
+			return n == t.number
+		case "!=":
+			return n != t.number
+		case ">":
+			return n > t.number
+		case ">=":
+			return n >= t.number
+		case "<":
+			return n < t.number
+		default:
+			return n <= t.number
+		}
+	default:
+		values := field.values(img)
+		if t.field == "arch" {
+			for i := range values {
+				values[i] = catalogimage.NormalizeArchitecture(values[i])
+			}
+		}
+		found := false
+		for _, v := range values {
+			if t.op == "~" || t.op == "!~" {
+				found = strings.Contains(strings.ToLower(v), strings.ToLower(t.value))
+			} else {
+				found = strings.EqualFold(v, t.value)
+			}
+			if found {
+				break
+			}
+		}
+		if strings.HasPrefix(t.op, "!") {
+			return !found
+		}
+		return found
+	}
+}
+
+// matchesText reports whether text occurs in any of the image's descriptive fields
+func matchesText(img *automotivev1alpha1.CatalogImage, text string) bool {
+	text = strings.ToLower(text)
+	haystack := []string{img.Name, img.Spec.RegistryURL, img.Spec.Channel, img.Status.SourceImageBuild}
+	haystack = append(haystack, img.Spec.Tags...)
+	if m := img.Spec.Metadata; m != nil {
+		haystack = append(haystack, m.Architecture, m.Distro, m.DistroVersion, m.BuildMode, m.ExportFormat)
+		for _, target := range m.Targets {
+			haystack = append(haystack, target.Name, target.Notes)
+		}
+	}
+	for _, s := range haystack {
+		if strings.Contains(strings.ToLower(s), text) {
+			return true
+		}
+	}
+	return false
+}
+
+func stringField(values func(img *automotivev1alpha1.CatalogImage) []string) *queryField {
+	return &queryField{kind: kindString, values: values}
+}
+
+func timeField(get func(img *automotivev1alpha1.CatalogImage) *metav1.Time) *queryField {
+	return &queryField{kind: kindTime, number: func(img *automotivev1alpha1.CatalogImage) (int64, bool) {
+		t := get(img)
+		if t == nil || t.IsZero() {
+			return 0, false
+		}
+		return t.UnixNano(), true
+	}}
+}
+
+func metadataField(get func(m *automotivev1alpha1.CatalogImageMetadata) string) *queryField {
+	return stringField(func(img *automotivev1alpha1.CatalogImage) []string {
+		if img.Spec.Metadata == nil {
+			return nil
+		}
+		return []string{get(img.Spec.Metadata)}
+	})
+}
+
+func imageDigests(img *automotivev1alpha1.CatalogImage) []string {
+	digests := []string{img.Spec.Digest}
+	if img.Status.RegistryMetadata != nil {
+		digests = append(digests, img.Status.RegistryMetadata.ResolvedDigest)
+	}
+	return digests
+}
+
+func imageArchitectures(img *automotivev1alpha1.CatalogImage) []string {
+	var archs []string
+	if img.Spec.Metadata != nil && img.Spec.Metadata.Architecture != "" {
+		archs = append(archs, img.Spec.Metadata.Architecture)
+	}
+	if img.Status.RegistryMetadata != nil {
+		for _, variant := range img.Status.RegistryMetadata.PlatformVariants {
+			archs = append(archs, variant.Architecture)
+		}
+	}
+	return archs
+}
+
+func imageTargets(img *automotivev1alpha1.CatalogImage) []string {
+	if img.Spec.Metadata == nil {
+		return nil
+	}
+	targets := make([]string, 0, len(img.Spec.Metadata.Targets))
+	for _, target := range img.Spec.Metadata.Targets {
+		targets = append(targets, target.Name)
+	}
+	return targets
+}
+
+func promotionField(get func(r *automotivev1alpha1.PromotionRecord) string) *queryField {
+	return stringField(func(img *automotivev1alpha1.CatalogImage) []string {
+		users := make([]string, 0, len(img.Status.PromotionHistory))
+		for i := range img.Status.PromotionHistory {
+			users = append(users, get(&img.Status.PromotionHistory[i]))
+		}
+		return users
+	})
+}
+
+// sortFields are the fields catalog listings can be sorted by
+var sortFields = map[string]bool{"name": true, "published": true, "created": true, "size": true, "accesses": true}
+
+// sortOrder orders catalog images by one field, then by namespace and name
+type sortOrder struct {
+	field      string
+	descending bool
+}
+
+// parseSortOrder parses a sort parameter such as `published` or `-size`; the default is by name
+func parseSortOrder(s string) (sortOrder, error) {
+	order := sortOrder{field: "name"}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return order, nil
+	}
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		order.descending = true
+		s = rest
+	}
+	name := strings.ToLower(s)
+	if canonical, ok := queryFieldAliases[name]; ok {
+		name = canonical
+	}
+	if !sortFields[name] {
+		return sortOrder{}, fmt.Errorf("cannot sort by %q: use name, published, created, size or accesses", s)
+	}
+	order.field = name
+	return order, nil
+}
+
+func (o sortOrder) String() string {
+	if o.descending {
+		return "-" + o.field
+	}
+	return o.field
+}
+
+// sortKey is the position of an image in a sorted listing, and doubles as the pagination cursor
+type sortKey struct {
+	Order     string `json:"o"`
+	Number    int64  `json:"v,omitempty"`
+	Namespace string `json:"ns"`
+	Name      string `json:"n"`
+}
+
+// keyOf returns the sort key of an image; images without a value sort as zero
+func (o sortOrder) keyOf(img *automotivev1alpha1.CatalogImage) sortKey {
+	key := sortKey{Order: o.String(), Namespace: img.Namespace, Name: img.Name}
+	if o.field != "name" {
+		key.Number, _ = queryFields[o.field].number(img)
+	}
+	return key
+}
+
+// compare orders two sort keys, returning a negative number if a comes first
+func (o sortOrder) compare(a, b sortKey) int {
+	cmp := 0
+	switch {
+	case a.Number < b.Number:
+		cmp = -1
+	case a.Number > b.Number:
+		cmp = 1
+	case a.Namespace != b.Namespace:
+		cmp = strings.Compare(a.Namespace, b.Namespace)
+	default:
+		cmp = strings.Compare(a.Name, b.Name)
+	}
+	if o.descending {
+		return -cmp
+	}
+	return cmp
+}
+
+// sortImages sorts images in place
+func (o sortOrder) sortImages(items []automotivev1alpha1.CatalogImage) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return o.compare(o.keyOf(&items[i]), o.keyOf(&items[j])) < 0
+	})
+}
+
+// encodeCursor returns the opaque continue token resuming a listing after key
+func encodeCursor(key sortKey) string {
+	data, _ := json.Marshal(key)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a continue token returned by encodeCursor for the same sort order
+func decodeCursor(token string, order sortOrder) (*sortKey, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("malformed continue token")
+	}
+	key := &sortKey{}
+	if err := json.Unmarshal(data, key); err != nil {
+		return nil, fmt.Errorf("malformed continue token")
+	}
+	if key.Order != order.String() {
+		return nil, fmt.Errorf("continue token was issued for sort order %q", key.Order)
+	}
+	return key, nil
+}
+
+// paginate returns the page of sorted items following the cursor and the token of the next page
+func (o sortOrder) paginate(
+	items []automotivev1alpha1.CatalogImage, cursor *sortKey, limit int,
+) ([]automotivev1alpha1.CatalogImage, string) {
+	start := 0
+	if cursor != nil {
+		start = sort.Search(len(items), func(i int) bool {
+			return o.compare(o.keyOf(&items[i]), *cursor) > 0
+		})
+	}
+	end := start + limit
+	if end >= len(items) {
+		return items[start:], ""
+	}
+	return items[start:end], encodeCursor(o.keyOf(&items[end-1]))
+}
@@ -0,0 +1,95 @@
+package catalog
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+func queryTestImage(name string, published time.Time, size int64) automotivev1alpha1.CatalogImage {
+	return automotivev1alpha1.CatalogImage{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: automotivev1alpha1.CatalogImageSpec{
+			RegistryURL: "quay.io/myorg/" + name + ":latest",
+			Tags:        []string{"release", "lts"},
+			Metadata: &automotivev1alpha1.CatalogImageMetadata{
+				Architecture: "aarch64",
+				Distro:       "autosd",
+				Targets:      []automotivev1alpha1.HardwareTarget{{Name: "rpi4", Notes: "needs firmware 1.2"}},
+			},
+		},
+		Status: automotivev1alpha1.CatalogImageStatus{
+			Phase:            automotivev1alpha1.CatalogImagePhaseAvailable,
+			PublishedAt:      &metav1.Time{Time: published},
+			RegistryMetadata: &automotivev1alpha1.RegistryMetadata{SizeBytes: size},
+		},
+	}
+}
+
+var _ = Describe("Catalog queries", func() {
+	img := queryTestImage("autosd-rpi", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 2<<30)
+
+	DescribeTable("matching",
+		func(q string, expected bool) {
+			query, err := parseQuery(q)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query.matches(&img)).To(Equal(expected))
+		},
+		Entry("empty query", "", true),
+		Entry("field equality", "distro=autosd", true),
+		Entry("normalized architecture", "arch=arm64", true),
+		Entry("substring", "target~rpi", true),
+		Entry("tag shorthand", "tag:release", true),
+		Entry("missing tag", "tag:beta", false),
+		Entry("negation", "distro!=autosd", false),
+		Entry("date comparison", "published>2026-01-01", true),
+		Entry("size with units", "size>=2Gi size<3G", true),
+		Entry("free text over notes", `"firmware 1.2"`, true),
+		Entry("all terms must match", "distro=autosd arch=amd64", false),
+		Entry("bool field", "bootc=false", true),
+	)
+
+	DescribeTable("rejecting invalid queries",
+		func(q string) {
+			_, err := parseQuery(q)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unknown field", "color=red"),
+		Entry("missing field", "=autosd"),
+		Entry("ordering a string field", "distro>autosd"),
+		Entry("malformed date", "published>yesterday"),
+		Entry("unterminated quote", `target~"rpi`),
+	)
+
+	It("should page through sorted results with continue tokens", func() {
+		items := []automotivev1alpha1.CatalogImage{
+			queryTestImage("b", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), 1),
+			queryTestImage("a", time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC), 1),
+			queryTestImage("c", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 1),
+		}
+		order, err := parseSortOrder("-published")
+		Expect(err).NotTo(HaveOccurred())
+		order.sortImages(items)
+
+		page, next := order.paginate(items, nil, 2)
+		Expect(page).To(HaveLen(2))
+		Expect(page[0].Name).To(Equal("a"))
+		Expect(page[1].Name).To(Equal("b"))
+		Expect(next).NotTo(BeEmpty())
+
+		cursor, err := decodeCursor(next, order)
+		Expect(err).NotTo(HaveOccurred())
+		page, next = order.paginate(items, cursor, 2)
+		Expect(page).To(HaveLen(1))
+		Expect(page[0].Name).To(Equal("c"))
+		Expect(next).To(BeEmpty())
+
+		nameOrder, _ := parseSortOrder("name")
+		_, err = decodeCursor(encodeCursor(order.keyOf(&items[0])), nameOrder)
+		Expect(err).To(HaveOccurred())
+	})
+})
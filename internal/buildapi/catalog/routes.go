@@ -22,29 +22,45 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// RegisterRoutes registers catalog API routes on the given router group
-func RegisterRoutes(group *gin.RouterGroup, k8sClient client.Client, log logr.Logger) {
-	handler := NewHandler(k8sClient, log)
+// RegisterRoutes registers catalog API routes on the given router group.
+// configNamespace is where the OperatorConfig defining promotion channels lives, and access
+// authenticates callers and authorizes each operation against their RBAC permissions.
+func RegisterRoutes(
+	group *gin.RouterGroup, k8sClient client.Client, log logr.Logger, configNamespace string, access AccessControl,
+) {
+	handler := NewHandler(k8sClient, log, configNamespace, access)
 
 	// Catalog image routes
 	catalogGroup := group.Group("/catalog")
 	{
+		// Receive registry push and delete notifications, authenticated with a shared secret
+		catalogGroup.POST("/webhooks/registry", handler.HandleRegistryWebhook)
+
+		// All other routes act on behalf of an authenticated user
+		userGroup := catalogGroup.Group("")
+		if access.Authenticate != nil {
+			userGroup.Use(access.Authenticate)
+		}
+
 		// List catalog images
-		catalogGroup.GET("/images", handler.HandleListCatalogImages)
+		userGroup.GET("/images", handler.authorize("list", ""), handler.HandleListCatalogImages)
 
 		// Create catalog image (add external image)
-		catalogGroup.POST("/images", handler.HandleCreateCatalogImage)
+		userGroup.POST("/images", handler.authorize("create", ""), handler.HandleCreateCatalogImage)
 
 		// Get catalog image details
-		catalogGroup.GET("/images/:name", handler.HandleGetCatalogImage)
+		userGroup.GET("/images/:name", handler.authorize("get", ""), handler.HandleGetCatalogImage)
 
 		// Delete catalog image
-		catalogGroup.DELETE("/images/:name", handler.HandleDeleteCatalogImage)
+		userGroup.DELETE("/images/:name", handler.authorize("delete", ""), handler.HandleDeleteCatalogImage)
 
 		// Verify catalog image
-		catalogGroup.POST("/images/:name/verify", handler.HandleVerifyCatalogImage)
+		userGroup.POST("/images/:name/verify", handler.authorize("update", ""), handler.HandleVerifyCatalogImage)
+
+		// Promote catalog image into a channel
+		userGroup.POST("/images/:name/promote", handler.authorize("create", "promote"), handler.HandlePromoteCatalogImage)
 
-		// Publish ImageBuild to catalog
-		catalogGroup.POST("/publish", handler.HandlePublishImageBuild)
+		// Publish ImageBuild to catalog, authorized in the handler as the namespace is part of the body
+		userGroup.POST("/publish", handler.HandlePublishImageBuild)
 	}
 }
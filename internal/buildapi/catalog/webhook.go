@@ -0,0 +1,381 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	// webhookSecretKey is the key of the shared secret in the webhook secret
+	webhookSecretKey = "token"
+	// webhookSecretHeader is the header registries without Authorization header support send the secret in
+	webhookSecretHeader = "X-Webhook-Secret"
+	// maxWebhookBodySize bounds the size of accepted notification payloads
+	maxWebhookBodySize = 1 << 20
+)
+
+// registryAction is the kind of change a registry notification reports
+type registryAction string
+
+const (
+	registryActionPush   registryAction = "push"
+	registryActionDelete registryAction = "delete"
+)
+
+// registryEvent is a push or delete of a tag or manifest, normalized from a registry notification
+type registryEvent struct {
+	action registryAction
+	// repository is the normalized repository name, e.g. quay.io/myorg/autosd
+	repository string
+	tag        string
+	digest     string
+}
+
+// distributionNotification is the notification envelope of the CNCF Distribution registry
+type distributionNotification struct {
+	Events []struct {
+		Action string `json:"action"`
+		Target struct {
+			Repository string `json:"repository"`
+			URL        string `json:"url"`
+			Tag        string `json:"tag"`
+			Digest     string `json:"digest"`
+		} `json:"target"`
+		Request struct {
+			Host string `json:"host"`
+		} `json:"request"`
+	} `json:"events"`
+}
+
+// harborNotification is the payload of a Harbor webhook
+type harborNotification struct {
+	Type      string `json:"type"`
+	EventData struct {
+		Resources []struct {
+			Digest      string `json:"digest"`
+			Tag         string `json:"tag"`
+			ResourceURL string `json:"resource_url"`
+		} `json:"resources"`
+	} `json:"event_data"`
+}
+
+// quayNotification is the payload of a Quay repository push notification
+type quayNotification struct {
+	DockerURL   string   `json:"docker_url"`
+	UpdatedTags []string `json:"updated_tags"`
+}
+
+// parseRegistryNotification normalizes a Quay, Harbor or Distribution notification payload
+func parseRegistryNotification(body []byte) ([]registryEvent, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return nil, fmt.Errorf("invalid notification payload: %w", err)
+	}
+
+	switch {
+	case probe["events"] != nil:
+		return parseDistributionNotification(body)
+	case probe["event_data"] != nil:
+		return parseHarborNotification(body)
+	case probe["docker_url"] != nil:
+		return parseQuayNotification(body)
+	}
+	return nil, fmt.Errorf("unrecognized notification payload")
+}
+
+func parseDistributionNotification(body []byte) ([]registryEvent, error) {
+	var notification distributionNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("invalid distribution notification: %w", err)
+	}
+
+	var events []registryEvent
+	for _, e := range notification.Events {
+		action := registryAction(e.Action)
+		if action != registryActionPush && action != registryActionDelete {
+			continue
+		}
+		host := e.Request.Host
+		if target, err := url.Parse(e.Target.URL); err == nil && target.Host != "" {
+			host = target.Host
+		}
+		repository, err := normalizeRepository(host + "/" + e.Target.Repository)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, registryEvent{
+			action:     action,
+			repository: repository,
+			tag:        e.Target.Tag,
+			digest:     e.Target.Digest,
+		})
+	}
+	return events, nil
+}
+
+func parseHarborNotification(body []byte) ([]registryEvent, error) {
+	var notification harborNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("invalid harbor notification: %w", err)
+	}
+
+	var action registryAction
+	switch notification.Type {
+	case "PUSH_ARTIFACT":
+		action = registryActionPush
+	case "DELETE_ARTIFACT":
+		action = registryActionDelete
+	default:
+		return nil, nil
+	}
+
+	var events []registryEvent
+	for _, resource := range notification.EventData.Resources {
+		named, err := reference.ParseNormalizedNamed(resource.ResourceURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid harbor resource URL %q: %w", resource.ResourceURL, err)
+		}
+		events = append(events, registryEvent{
+			action:     action,
+			repository: named.Name(),
+			tag:        resource.Tag,
+			digest:     resource.Digest,
+		})
+	}
+	return events, nil
+}
+
+func parseQuayNotification(body []byte) ([]registryEvent, error) {
+	var notification quayNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("invalid quay notification: %w", err)
+	}
+
+	repository, err := normalizeRepository(notification.DockerURL)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]registryEvent, 0, len(notification.UpdatedTags))
+	for _, tag := range notification.UpdatedTags {
+		events = append(events, registryEvent{action: registryActionPush, repository: repository, tag: tag})
+	}
+	return events, nil
+}
+
+// normalizeRepository returns the normalized name of a repository reference without tag
+func normalizeRepository(repository string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(strings.TrimSuffix(repository, "/"))
+	if err != nil {
+		return "", fmt.Errorf("invalid repository %q: %w", repository, err)
+	}
+	return named.Name(), nil
+}
+
+// matches reports whether the event concerns the image: its repository and tag for tagged images,
+// or its digest for deletions of a manifest
+func (e *registryEvent) matches(img *automotivev1alpha1.CatalogImage) bool {
+	named, err := reference.ParseNormalizedNamed(img.Spec.RegistryURL)
+	if err != nil || named.Name() != e.repository {
+		return false
+	}
+
+	if tagged, ok := named.(reference.Tagged); ok && e.tag != "" {
+		return tagged.Tag() == e.tag
+	}
+	if e.action != registryActionDelete || e.digest == "" {
+		return false
+	}
+	if digested, ok := named.(reference.Digested); ok && digested.Digest().String() == e.digest {
+		return true
+	}
+	if img.Spec.Digest == e.digest {
+		return true
+	}
+	return img.Status.RegistryMetadata != nil && img.Status.RegistryMetadata.ResolvedDigest == e.digest
+}
+
+// HandleRegistryWebhook receives push and delete notifications from registries. Pushes trigger an
+// immediate re-verification of the catalog images referencing the pushed tag; deletions mark them
+// Unavailable. Senders authenticate with the shared secret configured in the OperatorConfig.
+func (h *Handler) HandleRegistryWebhook(c *gin.Context) {
+	ctx := context.Background()
+
+	secret, err := h.webhookSecret(ctx)
+	if err != nil {
+		h.log.Error(err, "failed to get registry webhook secret")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get webhook secret"})
+		return
+	}
+	if secret == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "registry webhook is not configured"})
+		return
+	}
+	if !webhookAuthorized(c.Request, secret) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid webhook secret"})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+		return
+	}
+	events, err := parseRegistryNotification(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := RegistryWebhookResponse{Events: len(events)}
+	if len(events) == 0 {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	images, err := h.listMatchingImages(ctx, nil, nil)
+	if err != nil {
+		h.log.Error(err, "failed to list catalog images")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list catalog images"})
+		return
+	}
+
+	for i := range images {
+		img := &images[i]
+		action, ok := webhookActionFor(img, events)
+		if !ok {
+			continue
+		}
+		ref := img.Namespace + "/" + img.Name
+		if err := h.applyRegistryAction(ctx, img, action); err != nil {
+			h.log.Error(err, "failed to apply registry notification", "name", img.Name,
+				"namespace", img.Namespace, "action", action)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update catalog image " + ref})
+			return
+		}
+		h.log.Info("applied registry notification", "name", img.Name, "namespace", img.Namespace,
+			"action", action)
+		if action == registryActionDelete {
+			response.Unavailable = append(response.Unavailable, ref)
+		} else {
+			response.Reverified = append(response.Reverified, ref)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// webhookActionFor returns the action the events require for the image. A deletion outweighs pushes
+// of the same tag in one notification.
+func webhookActionFor(img *automotivev1alpha1.CatalogImage, events []registryEvent) (registryAction, bool) {
+	var action registryAction
+	for i := range events {
+		if !events[i].matches(img) {
+			continue
+		}
+		if action == "" || events[i].action == registryActionDelete {
+			action = events[i].action
+		}
+	}
+	return action, action != ""
+}
+
+// applyRegistryAction moves the image to Verifying for pushes and to Unavailable for deletions
+func (h *Handler) applyRegistryAction(
+	ctx context.Context,
+	img *automotivev1alpha1.CatalogImage,
+	action registryAction,
+) error {
+	patch := client.MergeFrom(img.DeepCopy())
+	switch action {
+	case registryActionPush:
+		img.Status.Phase = automotivev1alpha1.CatalogImagePhaseVerifying
+	case registryActionDelete:
+		message := "Image was deleted from the registry"
+		for _, conditionType := range []string{
+			automotivev1alpha1.CatalogImageConditionAvailable,
+			automotivev1alpha1.CatalogImageConditionReady,
+		} {
+			meta.SetStatusCondition(&img.Status.Conditions, metav1.Condition{
+				Type:               conditionType,
+				Status:             metav1.ConditionFalse,
+				Reason:             "DeletedInRegistry",
+				Message:            message,
+				ObservedGeneration: img.Generation,
+			})
+		}
+		img.Status.Phase = automotivev1alpha1.CatalogImagePhaseUnavailable
+	}
+	return h.client.Status().Patch(ctx, img, patch)
+}
+
+// webhookSecret returns the configured shared secret, or nil if the webhook is not configured
+func (h *Handler) webhookSecret(ctx context.Context) ([]byte, error) {
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	if err := h.client.Get(
+		ctx, client.ObjectKey{Name: operatorConfigName, Namespace: h.configNamespace}, operatorConfig,
+	); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if operatorConfig.Spec.Catalog == nil || operatorConfig.Spec.Catalog.Webhook == nil {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	secretName := operatorConfig.Spec.Catalog.Webhook.SecretName
+	if err := h.client.Get(ctx, client.ObjectKey{Name: secretName, Namespace: h.configNamespace}, secret); err != nil {
+		return nil, err
+	}
+	token := bytes.TrimSpace(secret.Data[webhookSecretKey])
+	if len(token) == 0 {
+		return nil, fmt.Errorf("secret %s has no %q key", secretName, webhookSecretKey)
+	}
+	return token, nil
+}
+
+// webhookAuthorized checks the shared secret sent as bearer token, verbatim Authorization header
+// (Harbor), X-Webhook-Secret header (Distribution) or secret query parameter (Quay)
+func webhookAuthorized(r *http.Request, secret []byte) bool {
+	candidates := []string{
+		strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")),
+		r.Header.Get(webhookSecretHeader),
+		r.URL.Query().Get("secret"),
+	}
+	for _, candidate := range candidates {
+		if candidate != "" && subtle.ConstantTimeCompare([]byte(candidate), secret) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package catalog
+
+import (
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+var _ = Describe("Registry webhooks", func() {
+	DescribeTable("parsing notifications",
+		func(payload string, expected []registryEvent) {
+			events, err := parseRegistryNotification([]byte(payload))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(Equal(expected))
+		},
+		Entry("quay push", `{"docker_url": "quay.io/myorg/autosd-rpi", "updated_tags": ["latest", "v1"]}`,
+			[]registryEvent{
+				{action: registryActionPush, repository: "quay.io/myorg/autosd-rpi", tag: "latest"},
+				{action: registryActionPush, repository: "quay.io/myorg/autosd-rpi", tag: "v1"},
+			}),
+		Entry("harbor delete", `{"type": "DELETE_ARTIFACT", "event_data": {"resources": [
+			{"digest": "`+testDigest+`", "tag": "latest", "resource_url": "harbor.example.com/lib/autosd:latest"}]}}`,
+			[]registryEvent{{
+				action: registryActionDelete, repository: "harbor.example.com/lib/autosd", tag: "latest",
+				digest: testDigest,
+			}}),
+		Entry("distribution events, ignoring pulls", `{"events": [
+			{"action": "pull", "target": {"repository": "myorg/autosd-rpi", "tag": "latest"},
+			 "request": {"host": "registry.local:5000"}},
+			{"action": "delete", "target": {"repository": "myorg/autosd-rpi", "digest": "`+testDigest+`",
+			 "url": "https://registry.local:5000/v2/myorg/autosd-rpi/manifests/`+testDigest+`"}}]}`,
+			[]registryEvent{{
+				action: registryActionDelete, repository: "registry.local:5000/myorg/autosd-rpi", digest: testDigest,
+			}}),
+	)
+
+	It("should reject unknown payloads", func() {
+		_, err := parseRegistryNotification([]byte(`{"hello": "world"}`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should match images by repository and tag, or by digest for deletions", func() {
+		img := queryTestImage("autosd-rpi", time.Now(), 1)
+		img.Spec.Digest = testDigest
+
+		Expect((&registryEvent{action: registryActionPush, repository: "quay.io/myorg/autosd-rpi",
+			tag: "latest"}).matches(&img)).To(BeTrue())
+		Expect((&registryEvent{action: registryActionPush, repository: "quay.io/myorg/autosd-rpi",
+			tag: "v2"}).matches(&img)).To(BeFalse())
+		Expect((&registryEvent{action: registryActionPush, repository: "quay.io/other/autosd-rpi",
+			tag: "latest"}).matches(&img)).To(BeFalse())
+		Expect((&registryEvent{action: registryActionDelete, repository: "quay.io/myorg/autosd-rpi",
+			digest: testDigest}).matches(&img)).To(BeTrue())
+		Expect((&registryEvent{action: registryActionPush, repository: "quay.io/myorg/autosd-rpi",
+			digest: testDigest}).matches(&img)).To(BeFalse())
+	})
+
+	It("should accept the shared secret from Authorization, header or query", func() {
+		secret := []byte("s3cret")
+
+		req := httptest.NewRequest("POST", "/v1/catalog/webhooks/registry", nil)
+		Expect(webhookAuthorized(req, secret)).To(BeFalse())
+
+		req.Header.Set("Authorization", "Bearer s3cret")
+		Expect(webhookAuthorized(req, secret)).To(BeTrue())
+
+		req = httptest.NewRequest("POST", "/v1/catalog/webhooks/registry", nil)
+		req.Header.Set(webhookSecretHeader, "wrong")
+		Expect(webhookAuthorized(req, secret)).To(BeFalse())
+
+		req = httptest.NewRequest("POST", "/v1/catalog/webhooks/registry?secret=s3cret", nil)
+		Expect(webhookAuthorized(req, secret)).To(BeTrue())
+	})
+})
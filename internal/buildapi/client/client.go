@@ -13,6 +13,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
 )
@@ -22,6 +23,8 @@ type Client struct {
 	baseURL    *url.URL
 	httpClient *http.Client
 	authToken  string
+	namespace  string
+	onRetry    func(wait time.Duration)
 }
 
 // New creates a new build API client with the given base URL and options.
@@ -33,25 +36,35 @@ func New(base string, opts ...Option) (*Client, error) {
 	if u.Scheme == "" || u.Host == "" {
 		return nil, fmt.Errorf("base URL must include scheme and host (e.g., https://api.example.com)")
 	}
+	retry := NewRetryTransport(nil)
 	c := &Client{
 		baseURL:    u,
-		httpClient: &http.Client{}, // No global timeout to avoid aborting large uploads
+		httpClient: &http.Client{Transport: retry}, // No global timeout to avoid aborting large uploads
 	}
 	for _, o := range opts {
 		o(c)
 	}
+	retry.OnRetry = c.onRetry
 	return c, nil
 }
 
 // Option is a function that configures a Client.
 type Option func(*Client)
 
-// WithHTTPClient sets a custom HTTP client for the build API client.
+// WithHTTPClient sets a custom HTTP client for the build API client. Wrap its transport with
+// NewRetryTransport to keep retrying rate-limited requests.
 func WithHTTPClient(h *http.Client) Option { return func(c *Client) { c.httpClient = h } }
 
 // WithAuthToken sets an authentication token for API requests.
 func WithAuthToken(t string) Option { return func(c *Client) { c.authToken = t } }
 
+// WithNamespace selects the tenant namespace API requests operate in.
+func WithNamespace(ns string) Option { return func(c *Client) { c.namespace = ns } }
+
+// WithRetryNotify sets a function called with the wait before a rate-limited request is retried. It
+// does not apply to HTTP clients set with WithHTTPClient, whose RetryTransport has its own OnRetry.
+func WithRetryNotify(fn func(wait time.Duration)) Option { return func(c *Client) { c.onRetry = fn } }
+
 // CreateBuild submits a new build request to the API server.
 func (c *Client) CreateBuild(ctx context.Context, req buildapi.BuildRequest) (*buildapi.BuildResponse, error) {
 	body, err := json.Marshal(req)
@@ -147,6 +160,146 @@ func (c *Client) ListBuilds(ctx context.Context) ([]buildapi.BuildListItem, erro
 	return out, nil
 }
 
+// CreateToken issues an API token acting as the caller with the requested scopes.
+func (c *Client) CreateToken(ctx context.Context, req buildapi.APITokenRequest) (*buildapi.APITokenResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.resolve("/v1/tokens"), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.authToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusCreated {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("create token failed: %s: %s", resp.Status, string(b))
+	}
+	var out buildapi.APITokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTokens retrieves the API tokens owned by the caller.
+func (c *Client) ListTokens(ctx context.Context) ([]buildapi.APITokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.resolve("/v1/tokens"), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("list tokens failed: %s: %s", resp.Status, string(b))
+	}
+	var out []buildapi.APITokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RevokeToken revokes an API token owned by the caller.
+func (c *Client) RevokeToken(ctx context.Context, id string) error {
+	endpoint := c.resolve(path.Join("/v1/tokens", url.PathEscape(id)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusNoContent {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("revoke token failed: %s: %s", resp.Status, string(b))
+	}
+	return nil
+}
+
+// GetUsage retrieves build usage between since and until, zero times selecting the server's
+// default range. An empty user reports the caller's usage, all the usage of every user.
+func (c *Client) GetUsage(
+	ctx context.Context, since, until time.Time, user string, all bool,
+) (*buildapi.UsageResponse, error) {
+	u, err := url.Parse(c.resolve("/v1/usage"))
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		query.Set("until", until.UTC().Format(time.RFC3339))
+	}
+	if user != "" {
+		query.Set("user", user)
+	}
+	if all {
+		query.Set("all", "true")
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close response body: %v\n", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("get usage failed: %s: %s", resp.Status, string(b))
+	}
+	var out buildapi.UsageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 func (c *Client) resolve(p string) string {
 	u := *c.baseURL
 	basePath := u.Path
@@ -155,6 +308,9 @@ func (c *Client) resolve(p string) string {
 	}
 	p = strings.TrimPrefix(p, "/")
 	u.Path = path.Join(basePath, p)
+	if c.namespace != "" {
+		u.RawQuery = url.Values{"namespace": {c.namespace}}.Encode()
+	}
 	return u.String()
 }
 
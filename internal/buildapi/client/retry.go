@@ -0,0 +1,85 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRateLimitRetries = 5
+	// defaultMaxRetryAfter is the longest Retry-After the transport waits for; longer waits are
+	// returned to the caller
+	defaultMaxRetryAfter = 2 * time.Minute
+)
+
+// RetryTransport retries requests rejected with 429 Too Many Requests and a Retry-After header,
+// waiting as long as the server asks. Responses without Retry-After, such as exceeded build quotas,
+// and requests whose body cannot be replayed are returned as they are.
+type RetryTransport struct {
+	// Base performs the requests, http.DefaultTransport if nil
+	Base http.RoundTripper
+	// MaxRetries is the number of retries of a request
+	MaxRetries int
+	// MaxWait is the longest Retry-After waited for
+	MaxWait time.Duration
+	// OnRetry, if set, is called with the wait before a rate-limited request is retried
+	OnRetry func(wait time.Duration)
+}
+
+// NewRetryTransport wraps base with the default rate limit retries
+func NewRetryTransport(base http.RoundTripper) *RetryTransport {
+	return &RetryTransport{Base: base, MaxRetries: defaultMaxRateLimitRetries, MaxWait: defaultMaxRetryAfter}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := base.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= t.MaxRetries {
+			return resp, err
+		}
+		wait, ok := retryAfter(resp.Header.Get("Retry-After"))
+		if !ok || wait > t.MaxWait || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			if retry.Body, err = req.GetBody(); err != nil {
+				return resp, nil
+			}
+		}
+		_ = resp.Body.Close()
+		if t.OnRetry != nil {
+			t.OnRetry(wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		req = retry
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(0, time.Until(at)), true
+	}
+	return 0, false
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi"
+)
+
+// WatchBuild streams the events of a build, starting with a snapshot of its state. The channel is
+// closed when the context is cancelled, the build is deleted or the server ends the stream, after
+// which callers may watch again or fall back to GetBuild.
+func (c *Client) WatchBuild(ctx context.Context, name string) (<-chan buildapi.BuildEvent, error) {
+	endpoint := c.resolve(path.Join("/v1/builds", url.PathEscape(name), "events"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("watch build failed: %s: %s", resp.Status, string(b))
+	}
+
+	events := make(chan buildapi.BuildEvent, 16)
+	go func() {
+		defer close(events)
+		defer func() { _ = resp.Body.Close() }()
+		_ = readBuildEvents(resp.Body, func(event buildapi.BuildEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return events, nil
+}
+
+// readBuildEvents parses a server-sent event stream of build events, calling emit for each until
+// it returns false or the stream ends
+func readBuildEvents(r io.Reader, emit func(buildapi.BuildEvent) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			var event buildapi.BuildEvent
+			err := json.Unmarshal([]byte(data.String()), &event)
+			data.Reset()
+			if err != nil {
+				continue
+			}
+			if !emit(event) {
+				return nil
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	return scanner.Err()
+}
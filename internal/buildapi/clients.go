@@ -0,0 +1,193 @@
+package buildapi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// cacheSyncTimeout bounds how long the first request waits for the informer cache to sync
+const cacheSyncTimeout = time.Minute
+
+// kubeClients are the Kubernetes clients shared by all requests of the build API process
+type kubeClients struct {
+	config    *rest.Config
+	client    client.Client
+	clientset kubernetes.Interface
+	// informers is the informer cache behind client, also feeding the watch API
+	informers cache.Cache
+
+	// stopInformers stops the informer cache behind client
+	stopInformers context.CancelFunc
+}
+
+var (
+	kubeClientsMu     sync.Mutex
+	sharedKubeClients *kubeClients
+)
+
+// loadKubeClients returns the shared clients, creating them and starting the informer cache on
+// first use. Creation is retried on the next request if it fails.
+func loadKubeClients() (*kubeClients, error) {
+	kubeClientsMu.Lock()
+	defer kubeClientsMu.Unlock()
+	if sharedKubeClients != nil {
+		return sharedKubeClients, nil
+	}
+
+	kc, err := newKubeClients()
+	if err != nil {
+		return nil, err
+	}
+	sharedKubeClients = kc
+	return kc, nil
+}
+
+func newKubeClients() (*kubeClients, error) {
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		kubeconfig := os.Getenv("KUBECONFIG")
+		cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build kube config: %w", err)
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	if err := automotivev1alpha1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add automotive scheme: %w", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add core scheme: %w", err)
+	}
+	if err := rbacv1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add rbac scheme: %w", err)
+	}
+
+	directClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create k8s client: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	// Only the pods of builds are cached, rather than every pod of the cluster: build pipeline,
+	// upload and artifact pods all carry the ImageBuild's name
+	buildPods, err := labels.Parse(imageBuildNameLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse build pod selector: %w", err)
+	}
+	informers, err := cache.New(cfg, cache.Options{
+		Scheme:           scheme,
+		DefaultTransform: cache.TransformStripManagedFields(),
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Pod{}: {Label: buildPods},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create informer cache: %w", err)
+	}
+	stopInformers, err := startInformers(informers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kubeClients{
+		config:        cfg,
+		client:        &cachedReadClient{Client: directClient, cache: informers},
+		clientset:     clientset,
+		informers:     informers,
+		stopInformers: stopInformers,
+	}, nil
+}
+
+// startInformers starts watching ImageBuilds and build pods, the objects requests poll, and waits
+// for the initial lists to sync
+func startInformers(informers cache.Cache) (context.CancelFunc, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	for _, obj := range []client.Object{&automotivev1alpha1.ImageBuild{}, &corev1.Pod{}} {
+		if _, err := informers.GetInformer(ctx, obj); err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to create informer for %T: %w", obj, err)
+		}
+	}
+
+	go func() {
+		if err := informers.Start(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "informer cache stopped: %v\n", err)
+		}
+	}()
+
+	syncCtx, syncCancel := context.WithTimeout(ctx, cacheSyncTimeout)
+	defer syncCancel()
+	if !informers.WaitForCacheSync(syncCtx) {
+		cancel()
+		return nil, fmt.Errorf("timed out waiting for the informer cache to sync")
+	}
+	return cancel, nil
+}
+
+// cachedReadClient serves reads of ImageBuilds and pods from the informer cache and all other
+// reads and writes from the API server. Only build pods are cached; other pods are read with
+// liveReader.
+type cachedReadClient struct {
+	client.Client
+	cache cache.Cache
+}
+
+func (c *cachedReadClient) Get(
+	ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption,
+) error {
+	if resource := cachedResource(obj); resource != "" {
+		clientReadsTotal.WithLabelValues(resource, "cache").Inc()
+		return c.cache.Get(ctx, key, obj, opts...)
+	}
+	clientReadsTotal.WithLabelValues("other", "apiserver").Inc()
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *cachedReadClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if resource := cachedResource(list); resource != "" {
+		clientReadsTotal.WithLabelValues(resource, "cache").Inc()
+		return c.cache.List(ctx, list, opts...)
+	}
+	clientReadsTotal.WithLabelValues("other", "apiserver").Inc()
+	return c.Client.List(ctx, list, opts...)
+}
+
+// liveReader returns a reader bypassing the informer cache of a request's client, for reads that
+// must see the latest writes
+func liveReader(c client.Client) client.Reader {
+	if cached, ok := c.(*cachedReadClient); ok {
+		return cached.Client
+	}
+	return c
+}
+
+// cachedResource returns the resource name of objects served from the informer cache, or "" for
+// objects read from the API server
+func cachedResource(obj runtime.Object) string {
+	switch obj.(type) {
+	case *automotivev1alpha1.ImageBuild, *automotivev1alpha1.ImageBuildList:
+		return imageBuildsResource
+	case *corev1.Pod, *corev1.PodList:
+		return "pods"
+	}
+	return ""
+}
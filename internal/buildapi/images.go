@@ -0,0 +1,220 @@
+package buildapi
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/imagelocation"
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func (a *APIServer) handleDownloadImage(c *gin.Context) {
+	name := c.Param("name")
+	a.log.Info("image download requested", "image", name, "reqID", c.GetString("reqID"))
+	a.downloadImage(c, name)
+}
+
+// downloadImage streams the file of an available Image from its HTTP, S3 or PVC location
+func (a *APIServer) downloadImage(c *gin.Context, name string) {
+	namespace := requestNamespace(c)
+	ctx := c.Request.Context()
+
+	k8sClient, err := getClientFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("k8s client error: %v", err)})
+		return
+	}
+
+	image := &automotivev1alpha1.Image{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, image); err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error fetching image: %v", err)})
+		return
+	}
+
+	if image.Status.Phase != "Available" {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("image is %s", strings.ToLower(image.Status.Phase))})
+		return
+	}
+
+	loc := image.Spec.Location
+	switch {
+	case loc.Type == imagelocation.TypeHTTP && loc.HTTP != nil:
+		a.serveHTTPImage(c, k8sClient, image)
+	case loc.Type == imagelocation.TypeS3 && loc.S3 != nil:
+		a.serveS3Image(c, k8sClient, image)
+	case loc.Type == imagelocation.TypePVC && loc.PVC != nil:
+		a.servePVCImage(c, k8sClient, image)
+	case loc.Type == imagelocation.TypeRegistry && loc.Registry != nil:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("registry images are pulled with a container tool, e.g. oras pull %s", loc.Registry.URL),
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported location type: %s", loc.Type)})
+	}
+}
+
+// imageHeaders sets the descriptive headers of an image download
+func imageHeaders(sha256 string) func(http.Header) {
+	return func(h http.Header) {
+		h.Set("X-AIB-Artifact-Type", "file")
+		if sha256 != "" {
+			h.Set(headerArtifactSHA256, sha256)
+		}
+	}
+}
+
+// serveHTTPImage proxies the image from its HTTP server. With ?redirect=true the client is sent
+// to the server directly, which is only possible when no credentials are needed.
+func (a *APIServer) serveHTTPImage(c *gin.Context, k8sClient client.Client, image *automotivev1alpha1.Image) {
+	loc := image.Spec.Location.HTTP
+
+	if c.Query("redirect") == "true" {
+		if loc.SecretRef != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "redirects are not available for images that need credentials"})
+			return
+		}
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(http.StatusTemporaryRedirect, loc.URL)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, loc.URL, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("invalid image URL: %v", err)})
+		return
+	}
+	req.Header = conditionalHeaders(c)
+
+	if loc.SecretRef != "" {
+		secret := &corev1.Secret{}
+		nsName := types.NamespacedName{Name: loc.SecretRef, Namespace: image.Namespace}
+		if err := k8sClient.Get(c.Request.Context(), nsName, secret); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error reading secret %s: %v", loc.SecretRef, err)})
+			return
+		}
+		authorization, err := imagelocation.AuthorizationHeader(secret)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := imagelocation.NewHTTPClient(loc.InsecureSkipTLSVerify).Do(req)
+	if err != nil {
+		a.log.Error(err, "failed to fetch image from HTTP server", "image", image.Name)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "image server unavailable"})
+		return
+	}
+	a.relayDownload(c, resp, imagelocation.FileName(image.Spec.Location), imageHeaders(loc.SHA256))
+}
+
+// serveS3Image proxies the image from S3-compatible storage or redirects to a presigned URL
+func (a *APIServer) serveS3Image(c *gin.Context, k8sClient client.Client, image *automotivev1alpha1.Image) {
+	loc := image.Spec.Location.S3
+	store, err := newObjectStoreClient(
+		c.Request.Context(), k8sClient, image.Namespace, loc.Secret, loc.Endpoint, loc.Region, loc.InsecureSkipTLSVerify,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	a.proxyS3Object(c, store, loc.Bucket, loc.Key, imageHeaders(""))
+}
+
+// servePVCImage streams the image from the reader pod the Image controller runs for the claim
+func (a *APIServer) servePVCImage(c *gin.Context, k8sClient client.Client, image *automotivev1alpha1.Image) {
+	ctx := c.Request.Context()
+
+	filePath, err := imagelocation.CleanPVCPath(image.Spec.Location.PVC.Path)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	podPath := path.Join(imagelocation.ReaderMountPath, filePath)
+	filename := path.Base(filePath)
+	if !safeFilename(filename) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid image filename"})
+		return
+	}
+
+	restCfg, err := getRESTConfigFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("rest config: %v", err)})
+		return
+	}
+	clientset, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("clientset: %v", err)})
+		return
+	}
+
+	readerPod, err := findReadyPod(ctx, k8sClient, image.Namespace,
+		imagelocation.ReaderPodLabels(image.Name), "image reader pod", time.Now().Add(2*time.Minute))
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	sizeReq := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(readerPod.Name).
+		Namespace(image.Namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: imagelocation.ReaderContainerName,
+			Command: []string{
+				"sh", "-c",
+				"if [ -f \"$1\" ]; then wc -c < \"$1\"; else echo MISSING; fi",
+				"--", podPath,
+			},
+			Stdout: true,
+			Stderr: true,
+		}, kscheme.ParameterCodec)
+
+	sizeExec, err := remotecommand.NewSPDYExecutor(restCfg, http.MethodPost, sizeReq.URL())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("executor (size): %v", err)})
+		return
+	}
+	var sizeStdout strings.Builder
+	if err := sizeExec.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &sizeStdout}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("size stream: %v", err)})
+		return
+	}
+
+	sz := strings.TrimSpace(sizeStdout.String())
+	if sz == "" || sz == statusMissing {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		return
+	}
+	size, err := strconv.ParseInt(sz, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid image size"})
+		return
+	}
+
+	h := c.Writer.Header()
+	h.Set("Content-Type", artifactContentType(filename))
+	h.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	imageHeaders("")(h)
+
+	etag := fmt.Sprintf("\"%s-%d-%d\"", image.UID, size, image.Generation)
+	servePodFile(c, restCfg, clientset.CoreV1().RESTClient(), image.Namespace, readerPod.Name, podPath, size, etag)
+}
@@ -0,0 +1,54 @@
+package buildapi
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsNamespace = "buildapi"
+
+var (
+	// tokenReviewCacheTotal counts bearer token lookups in the token review cache by result
+	tokenReviewCacheTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "token_review_cache_requests_total",
+			Help:      "Total number of token review cache lookups by result (hit or miss)",
+		},
+		[]string{"result"},
+	)
+
+	// clientReadsTotal counts Kubernetes reads of the build API by resource and where they were served from
+	clientReadsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "client_reads_total",
+			Help:      "Total number of Kubernetes reads by resource and source (cache or apiserver)",
+		},
+		[]string{"resource", "source"},
+	)
+
+	// auditEventsTotal counts audit events handed to each sink by result
+	auditEventsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "audit_events_total",
+			Help:      "Total number of audit events by sink and result (written, failed or dropped)",
+		},
+		[]string{"sink", "result"},
+	)
+
+	// rateLimitedRequestsTotal counts requests rejected by rate limits
+	rateLimitedRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "rate_limited_requests_total",
+			Help:      "Total number of requests rejected by rate limits, by operation and scope (user or ip)",
+		},
+		[]string{"operation", "scope"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(tokenReviewCacheTotal, clientReadsTotal, auditEventsTotal, rateLimitedRequestsTotal)
+}
@@ -0,0 +1,300 @@
+package buildapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	jose "github.com/go-jose/go-jose/v4"
+	authnv1 "k8s.io/api/authentication/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	// oidcClockSkew is the leeway allowed when checking token expiry and not-before times
+	oidcClockSkew = time.Minute
+	// oidcKeyRefreshInterval limits how often unknown key IDs trigger a JWKS refetch
+	oidcKeyRefreshInterval = time.Minute
+	// oidcNoPrefix configured as a prefix disables it
+	oidcNoPrefix = "-"
+	// reservedNamePrefix starts the users and groups of the cluster itself, which tokens may not claim
+	reservedNamePrefix = "system:"
+)
+
+// errNotOIDCToken marks tokens not issued by the configured issuer, which are reviewed by the cluster instead
+var errNotOIDCToken = errors.New("not issued by the OIDC issuer")
+
+var oidcSignatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512, jose.EdDSA,
+}
+
+// oidcDiscovery is the part of an OpenID provider's discovery document used by the build API and caib
+type oidcDiscovery struct {
+	Issuer                      string `json:"issuer"`
+	JWKSURI                     string `json:"jwks_uri"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint,omitempty"`
+}
+
+// oidcAuthenticator validates ID tokens of an OpenID Connect provider. Discovery and signing keys
+// are fetched on first use, and keys are refetched when a token is signed with an unknown key.
+type oidcAuthenticator struct {
+	config     automotivev1alpha1.OIDCConfig
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu          sync.Mutex
+	jwksURI     string
+	keys        jose.JSONWebKeySet
+	keysFetched time.Time
+}
+
+func newOIDCAuthenticator(cfg *automotivev1alpha1.OIDCConfig) *oidcAuthenticator {
+	config := *cfg
+	config.IssuerURL = strings.TrimSuffix(config.IssuerURL, "/")
+	if config.ClientID == "" {
+		config.ClientID = config.Audience
+	}
+	if config.UsernameClaim == "" {
+		config.UsernameClaim = "sub"
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+	// Like kube-apiserver, names are prefixed with the issuer by default so that they cannot collide
+	// with cluster users and groups
+	config.UsernamePrefix = oidcPrefix(config.UsernamePrefix, config.IssuerURL)
+	config.GroupsPrefix = oidcPrefix(config.GroupsPrefix, config.IssuerURL)
+	return &oidcAuthenticator{
+		config:     config,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		now:        time.Now,
+	}
+}
+
+// authenticate verifies a JWT and maps its claims to a user. Tokens of other issuers, such as
+// service account tokens, return errNotOIDCToken.
+func (o *oidcAuthenticator) authenticate(ctx context.Context, token string) (authnv1.UserInfo, error) {
+	if strings.Count(token, ".") != 2 {
+		return authnv1.UserInfo{}, errNotOIDCToken
+	}
+	jws, err := jose.ParseSignedCompact(token, oidcSignatureAlgorithms)
+	if err != nil || len(jws.Signatures) != 1 {
+		return authnv1.UserInfo{}, errNotOIDCToken
+	}
+	var unverified struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(jws.UnsafePayloadWithoutVerification(), &unverified); err != nil ||
+		strings.TrimSuffix(unverified.Issuer, "/") != o.config.IssuerURL {
+		return authnv1.UserInfo{}, errNotOIDCToken
+	}
+
+	payload, err := o.verify(ctx, jws)
+	if err != nil {
+		return authnv1.UserInfo{}, err
+	}
+	claims := map[string]any{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return authnv1.UserInfo{}, fmt.Errorf("invalid claims: %w", err)
+	}
+	if err := o.validateClaims(claims); err != nil {
+		return authnv1.UserInfo{}, err
+	}
+	return o.userInfo(claims)
+}
+
+// verify checks the token signature against the issuer's keys
+func (o *oidcAuthenticator) verify(ctx context.Context, jws *jose.JSONWebSignature) ([]byte, error) {
+	kid := jws.Signatures[0].Header.KeyID
+	keys, err := o.signingKeys(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if payload, err := jws.Verify(key); err == nil {
+			return payload, nil
+		}
+	}
+	return nil, errors.New("invalid token signature")
+}
+
+// signingKeys returns the issuer keys matching kid, or all keys if the token names none
+func (o *oidcAuthenticator) signingKeys(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	keys := o.matchingKeys(kid)
+	if len(keys) > 0 || o.now().Sub(o.keysFetched) < oidcKeyRefreshInterval {
+		return keys, nil
+	}
+	if err := o.fetchKeys(ctx); err != nil {
+		return nil, err
+	}
+	return o.matchingKeys(kid), nil
+}
+
+func (o *oidcAuthenticator) matchingKeys(kid string) []jose.JSONWebKey {
+	if kid == "" {
+		return o.keys.Keys
+	}
+	return o.keys.Key(kid)
+}
+
+// fetchKeys refreshes the issuer's JWKS, discovering its location on first use
+func (o *oidcAuthenticator) fetchKeys(ctx context.Context) error {
+	o.keysFetched = o.now()
+	if o.jwksURI == "" {
+		discovery, err := discoverOIDC(ctx, o.httpClient, o.config.IssuerURL)
+		if err != nil {
+			return err
+		}
+		o.jwksURI = discovery.JWKSURI
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := getJSON(ctx, o.httpClient, o.jwksURI, &keys); err != nil {
+		return fmt.Errorf("failed to fetch OIDC signing keys: %w", err)
+	}
+	o.keys = keys
+	return nil
+}
+
+func (o *oidcAuthenticator) validateClaims(claims map[string]any) error {
+	now := o.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("token has no expiry")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(oidcClockSkew)) {
+		return errors.New("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(oidcClockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token not valid yet")
+	}
+
+	var audiences []string
+	switch aud := claims["aud"].(type) {
+	case string:
+		audiences = []string{aud}
+	case []any:
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				audiences = append(audiences, s)
+			}
+		}
+	}
+	if !slices.Contains(audiences, o.config.Audience) {
+		return fmt.Errorf("token audience does not include %q", o.config.Audience)
+	}
+	return nil
+}
+
+// oidcPrefix resolves a configured username or groups prefix, defaulting to "<issuer>#"
+func oidcPrefix(prefix, issuer string) string {
+	switch prefix {
+	case "":
+		return issuer + "#"
+	case oidcNoPrefix:
+		return ""
+	}
+	return prefix
+}
+
+// userInfo maps token claims to the user and groups checked by SubjectAccessReviews. Names in the
+// system: namespace are rejected, as they would be granted the permissions of cluster components.
+func (o *oidcAuthenticator) userInfo(claims map[string]any) (authnv1.UserInfo, error) {
+	username, _ := claims[o.config.UsernameClaim].(string)
+	if username == "" {
+		return authnv1.UserInfo{}, fmt.Errorf("token has no %q claim", o.config.UsernameClaim)
+	}
+	if o.config.UsernameClaim == "email" {
+		if verified, ok := claims["email_verified"].(bool); ok && !verified {
+			return authnv1.UserInfo{}, errors.New("token email is not verified")
+		}
+	}
+
+	user := authnv1.UserInfo{Username: o.config.UsernamePrefix + username}
+	user.UID, _ = claims["sub"].(string)
+	switch groups := claims[o.config.GroupsClaim].(type) {
+	case string:
+		user.Groups = []string{o.config.GroupsPrefix + groups}
+	case []any:
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				user.Groups = append(user.Groups, o.config.GroupsPrefix+s)
+			}
+		}
+	}
+
+	if strings.HasPrefix(user.Username, reservedNamePrefix) {
+		return authnv1.UserInfo{}, fmt.Errorf("username %q is reserved", user.Username)
+	}
+	for _, group := range user.Groups {
+		if strings.HasPrefix(group, reservedNamePrefix) {
+			return authnv1.UserInfo{}, fmt.Errorf("group %q is reserved", group)
+		}
+	}
+	return user, nil
+}
+
+// discoverOIDC fetches the discovery document of an issuer
+func discoverOIDC(ctx context.Context, httpClient *http.Client, issuer string) (*oidcDiscovery, error) {
+	discovery := &oidcDiscovery{}
+	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(ctx, httpClient, wellKnown, discovery); err != nil {
+		return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return nil, fmt.Errorf("OIDC discovery returned issuer %q, expected %q", discovery.Issuer, issuer)
+	}
+	if discovery.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery returned no jwks_uri")
+	}
+	return discovery, nil
+}
+
+func getJSON(ctx context.Context, httpClient *http.Client, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
+
+// EnableOIDC makes the server accept ID tokens of an OpenID Connect provider alongside cluster tokens
+func (a *APIServer) EnableOIDC(cfg *automotivev1alpha1.OIDCConfig) {
+	if cfg == nil || cfg.IssuerURL == "" || cfg.Audience == "" {
+		a.oidc = nil
+		return
+	}
+	a.oidc = newOIDCAuthenticator(cfg)
+}
+
+// handleAuthConfig tells clients which OpenID provider to log in with
+func (a *APIServer) handleAuthConfig(c *gin.Context) {
+	resp := AuthConfigResponse{}
+	if a.oidc != nil {
+		resp.OIDC = &OIDCClientConfig{IssuerURL: a.oidc.config.IssuerURL, ClientID: a.oidc.config.ClientID}
+	}
+	writeJSON(c, http.StatusOK, resp)
+}
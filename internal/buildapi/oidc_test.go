@@ -0,0 +1,172 @@
+package buildapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+var _ = Describe("OIDC authentication", func() {
+	var (
+		issuer        *httptest.Server
+		signer        jose.Signer
+		authenticator *oidcAuthenticator
+		jwksRequests  int
+	)
+
+	BeforeEach(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		signer, err = jose.NewSigner(
+			jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: key, KeyID: "test-key"}}, nil,
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		jwksRequests = 0
+		mux := http.NewServeMux()
+		issuer = httptest.NewServer(mux)
+		mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]string{"issuer": issuer.URL, "jwks_uri": issuer.URL + "/keys"})
+		})
+		mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+			jwksRequests++
+			_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+				{Key: &key.PublicKey, KeyID: "test-key", Algorithm: string(jose.RS256), Use: "sig"},
+			}})
+		})
+
+		authenticator = newOIDCAuthenticator(&automotivev1alpha1.OIDCConfig{
+			IssuerURL:      issuer.URL,
+			Audience:       "caib",
+			UsernameClaim:  "email",
+			UsernamePrefix: "oidc:",
+			GroupsPrefix:   "oidc:",
+		})
+	})
+
+	AfterEach(func() {
+		issuer.Close()
+	})
+
+	sign := func(claims map[string]any) string {
+		payload, err := json.Marshal(claims)
+		Expect(err).NotTo(HaveOccurred())
+		jws, err := signer.Sign(payload)
+		Expect(err).NotTo(HaveOccurred())
+		token, err := jws.CompactSerialize()
+		Expect(err).NotTo(HaveOccurred())
+		return token
+	}
+
+	validClaims := func() map[string]any {
+		return map[string]any{
+			"iss":            issuer.URL,
+			"aud":            []string{"caib", "account"},
+			"sub":            "1234",
+			"email":          "jane@example.com",
+			"email_verified": true,
+			"groups":         []string{"platform"},
+			"exp":            time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	It("should map the claims of valid tokens to a user", func() {
+		user, err := authenticator.authenticate(context.Background(), sign(validClaims()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user.Username).To(Equal("oidc:jane@example.com"))
+		Expect(user.UID).To(Equal("1234"))
+		Expect(user.Groups).To(ConsistOf("oidc:platform"))
+
+		_, err = authenticator.authenticate(context.Background(), sign(validClaims()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jwksRequests).To(Equal(1))
+	})
+
+	It("should prefix names with the issuer by default", func() {
+		authenticator = newOIDCAuthenticator(&automotivev1alpha1.OIDCConfig{
+			IssuerURL:     issuer.URL + "/",
+			Audience:      "caib",
+			UsernameClaim: "email",
+		})
+		user, err := authenticator.authenticate(context.Background(), sign(validClaims()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user.Username).To(Equal(issuer.URL + "#jane@example.com"))
+		Expect(user.Groups).To(ConsistOf(issuer.URL + "#platform"))
+	})
+
+	It("should reject system: usernames and groups", func() {
+		authenticator = newOIDCAuthenticator(&automotivev1alpha1.OIDCConfig{
+			IssuerURL:      issuer.URL,
+			Audience:       "caib",
+			UsernamePrefix: "-",
+			GroupsPrefix:   "-",
+		})
+		claims := validClaims()
+		claims["sub"] = "system:admin"
+		_, err := authenticator.authenticate(context.Background(), sign(claims))
+		Expect(err).To(MatchError(ContainSubstring("reserved")))
+
+		claims = validClaims()
+		claims["groups"] = []string{"platform", "system:masters"}
+		_, err = authenticator.authenticate(context.Background(), sign(claims))
+		Expect(err).To(MatchError(ContainSubstring("reserved")))
+
+		authenticator = newOIDCAuthenticator(&automotivev1alpha1.OIDCConfig{
+			IssuerURL:    issuer.URL,
+			Audience:     "caib",
+			GroupsPrefix: "system:",
+		})
+		_, err = authenticator.authenticate(context.Background(), sign(validClaims()))
+		Expect(err).To(MatchError(ContainSubstring("reserved")))
+	})
+
+	It("should reject tokens for other audiences or past their expiry", func() {
+		claims := validClaims()
+		claims["aud"] = "other"
+		_, err := authenticator.authenticate(context.Background(), sign(claims))
+		Expect(err).To(MatchError(ContainSubstring("audience")))
+
+		claims = validClaims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+		_, err = authenticator.authenticate(context.Background(), sign(claims))
+		Expect(err).To(MatchError(ContainSubstring("expired")))
+
+		claims = validClaims()
+		claims["email_verified"] = false
+		_, err = authenticator.authenticate(context.Background(), sign(claims))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject tokens with invalid signatures", func() {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		signer, err = jose.NewSigner(
+			jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: otherKey, KeyID: "test-key"}}, nil,
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = authenticator.authenticate(context.Background(), sign(validClaims()))
+		Expect(err).To(MatchError(ContainSubstring("signature")))
+	})
+
+	It("should leave tokens of other issuers to the cluster", func() {
+		claims := validClaims()
+		claims["iss"] = "https://kubernetes.default.svc"
+		_, err := authenticator.authenticate(context.Background(), sign(claims))
+		Expect(err).To(MatchError(errNotOIDCToken))
+
+		_, err = authenticator.authenticate(context.Background(), "sha256~opaque")
+		Expect(err).To(MatchError(errNotOIDCToken))
+		Expect(jwksRequests).To(BeZero())
+	})
+})
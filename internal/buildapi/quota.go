@@ -0,0 +1,262 @@
+package buildapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	requestedByAnnotation = automotivev1alpha1.RequestedByAnnotation
+	// defaultUsageDays is the time range reported by /v1/usage without a since parameter
+	defaultUsageDays = 30
+)
+
+// errQuotaExceeded marks builds rejected because the user's build quota is used up
+var errQuotaExceeded = errors.New("build quota exceeded")
+
+// userLocks serializes the build creations of each user, so that concurrent requests cannot all pass
+// the quota check before any of their builds exists
+type userLocks struct {
+	mu    sync.Mutex
+	locks map[string]*userLock
+}
+
+type userLock struct {
+	sync.Mutex
+	// waiters counts the holder and the requests waiting for the lock
+	waiters int
+}
+
+// lock takes the lock of a user, returning the function releasing it
+func (l *userLocks) lock(user string) (unlock func()) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*userLock{}
+	}
+	ul := l.locks[user]
+	if ul == nil {
+		ul = &userLock{}
+		l.locks[user] = ul
+	}
+	ul.waiters++
+	l.mu.Unlock()
+
+	ul.Lock()
+	return func() {
+		ul.Unlock()
+		l.mu.Lock()
+		if ul.waiters--; ul.waiters == 0 {
+			delete(l.locks, user)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// checkBuildQuota returns errQuotaExceeded if the user may not start another build. Builds are
+// counted across all namespaces, by the user recorded as requested-by. They are listed from the API
+// server rather than the informer cache, which may not have seen the user's latest builds yet; the
+// caller holds the user's lock until its build is created.
+func checkBuildQuota(
+	ctx context.Context,
+	k8sClient client.Client,
+	quotas *automotivev1alpha1.BuildQuotasConfig,
+	user authnv1.UserInfo,
+) error {
+	quota := quotas.QuotaFor(user.Username, user.Groups)
+	if quota == nil {
+		return nil
+	}
+
+	list := &automotivev1alpha1.ImageBuildList{}
+	if err := liveReader(k8sClient).List(ctx, list); err != nil {
+		return fmt.Errorf("error listing builds: %w", err)
+	}
+	now := time.Now()
+	return exceededQuota(quota, summarizeUsage(list.Items, now.Add(-24*time.Hour), now, now)[user.Username])
+}
+
+// exceededQuota checks a user's usage over the last 24 hours against its quota
+func exceededQuota(quota *automotivev1alpha1.BuildQuota, usage *UserUsage) error {
+	if usage == nil {
+		return nil
+	}
+	if quota.MaxConcurrentBuilds > 0 && usage.ActiveBuilds >= int(quota.MaxConcurrentBuilds) {
+		return fmt.Errorf("%w: %d of %d concurrent builds running, wait for one to finish",
+			errQuotaExceeded, usage.ActiveBuilds, quota.MaxConcurrentBuilds)
+	}
+	if quota.MaxBuildsPerDay > 0 && usage.Builds >= int(quota.MaxBuildsPerDay) {
+		return fmt.Errorf("%w: %d of %d builds created in the last 24 hours",
+			errQuotaExceeded, usage.Builds, quota.MaxBuildsPerDay)
+	}
+	limit := quota.MaxArtifactStorage
+	if limit != nil && !limit.IsZero() && usage.ArtifactStorageBytes >= limit.Value() {
+		return fmt.Errorf("%w: artifacts use %d of %d bytes (%s), delete old builds to free storage",
+			errQuotaExceeded, usage.ArtifactStorageBytes, limit.Value(), limit.String())
+	}
+	return nil
+}
+
+// summarizeUsage computes the usage of every user with builds. Build counts and minutes cover the
+// builds created between since and until, active builds and artifact storage all existing builds.
+func summarizeUsage(builds []automotivev1alpha1.ImageBuild, since, until, now time.Time) map[string]*UserUsage {
+	usage := map[string]*UserUsage{}
+	for i := range builds {
+		build := &builds[i]
+		user := build.Annotations[requestedByAnnotation]
+		if user == "" {
+			user = statusUnknown
+		}
+		u := usage[user]
+		if u == nil {
+			u = &UserUsage{User: user}
+			usage[user] = u
+		}
+
+		if build.Status.Phase != phaseCompleted && build.Status.Phase != phaseFailed {
+			u.ActiveBuilds++
+		}
+		u.ArtifactStorageBytes += build.Status.ArtifactSize
+
+		created := build.CreationTimestamp.Time
+		if created.Before(since) || !created.Before(until) {
+			continue
+		}
+		u.Builds++
+		switch build.Status.Phase {
+		case phaseCompleted:
+			u.Succeeded++
+		case phaseFailed:
+			u.Failed++
+		}
+		if start := build.Status.StartTime; start != nil {
+			end := now
+			if build.Status.CompletionTime != nil {
+				end = build.Status.CompletionTime.Time
+			}
+			if end.After(start.Time) {
+				u.BuildMinutes += end.Sub(start.Time).Minutes()
+			}
+		}
+	}
+	return usage
+}
+
+// parseUsageRange reads the since and until query parameters, defaulting to the last 30 days
+func parseUsageRange(sinceParam, untilParam string, now time.Time) (time.Time, time.Time, error) {
+	since := now.AddDate(0, 0, -defaultUsageDays)
+	until := now
+	if sinceParam != "" {
+		t, err := time.Parse(time.RFC3339, sinceParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid since %q, expected RFC 3339 time", sinceParam)
+		}
+		since = t
+	}
+	if untilParam != "" {
+		t, err := time.Parse(time.RFC3339, untilParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid until %q, expected RFC 3339 time", untilParam)
+		}
+		until = t
+	}
+	if !since.Before(until) {
+		return time.Time{}, time.Time{}, errors.New("since must be before until")
+	}
+	return since, until, nil
+}
+
+// handleGetUsage reports build usage computed from ImageBuild history. Callers see their own
+// usage and quota; the usage of other users requires permission to list builds in all namespaces.
+func (a *APIServer) handleGetUsage(c *gin.Context) {
+	now := time.Now()
+	since, until, err := parseUsageRange(c.Query("since"), c.Query("until"), now)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	caller, _ := c.Get(userInfoKey)
+	callerInfo, _ := caller.(authnv1.UserInfo)
+	user := strings.TrimSpace(c.Query("user"))
+	all := c.Query("all") == "true"
+	if all || (user != "" && user != callerInfo.Username) {
+		allowed, err := a.reviewAccess(c, authzv1.ResourceAttributes{
+			Verb:     "list",
+			Group:    automotivev1alpha1.GroupVersion.Group,
+			Resource: imageBuildsResource,
+		})
+		if err != nil {
+			a.log.Error(err, "access review failed", "reqID", c.GetString("reqID"))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to authorize request"})
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "forbidden: the usage of other users requires listing imagebuilds in all namespaces",
+			})
+			return
+		}
+	}
+	if user == "" {
+		user = callerInfo.Username
+	}
+
+	k8sClient, err := getClientFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("k8s client error: %v", err)})
+		return
+	}
+	ctx := c.Request.Context()
+	list := &automotivev1alpha1.ImageBuildList{}
+	if err := k8sClient.List(ctx, list); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error listing builds: %v", err)})
+		return
+	}
+
+	usage := summarizeUsage(list.Items, since, until, now)
+	resp := UsageResponse{
+		Since: since.UTC().Format(time.RFC3339),
+		Until: until.UTC().Format(time.RFC3339),
+		Users: []UserUsage{},
+	}
+	if all {
+		for _, u := range usage {
+			resp.Users = append(resp.Users, *u)
+		}
+		sort.Slice(resp.Users, func(i, j int) bool { return resp.Users[i].User < resp.Users[j].User })
+	} else if u := usage[user]; u != nil {
+		resp.Users = append(resp.Users, *u)
+	} else {
+		resp.Users = append(resp.Users, UserUsage{User: user})
+	}
+
+	if !all && user == callerInfo.Username {
+		operatorConfig := &automotivev1alpha1.OperatorConfig{}
+		configKey := client.ObjectKey{Name: "config", Namespace: resolveNamespace()}
+		if err := k8sClient.Get(ctx, configKey, operatorConfig); err == nil && operatorConfig.Spec.BuildAPI != nil {
+			if quota := operatorConfig.Spec.BuildAPI.Quotas.QuotaFor(user, callerInfo.Groups); quota != nil {
+				resp.Quota = &BuildQuotaResponse{
+					MaxConcurrentBuilds: quota.MaxConcurrentBuilds,
+					MaxBuildsPerDay:     quota.MaxBuildsPerDay,
+				}
+				if quota.MaxArtifactStorage != nil {
+					resp.Quota.MaxArtifactStorageBytes = quota.MaxArtifactStorage.Value()
+				}
+			}
+		}
+	}
+	writeJSON(c, http.StatusOK, resp)
+}
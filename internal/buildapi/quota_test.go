@@ -0,0 +1,123 @@
+package buildapi
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+var _ = Describe("Build quotas", func() {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	build := func(user, phase string, created time.Time, minutes int, size int64) automotivev1alpha1.ImageBuild {
+		b := automotivev1alpha1.ImageBuild{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.NewTime(created),
+				Annotations:       map[string]string{requestedByAnnotation: user},
+			},
+			Status: automotivev1alpha1.ImageBuildStatus{Phase: phase, ArtifactSize: size},
+		}
+		start := metav1.NewTime(created)
+		b.Status.StartTime = &start
+		if phase == phaseCompleted || phase == phaseFailed {
+			end := metav1.NewTime(created.Add(time.Duration(minutes) * time.Minute))
+			b.Status.CompletionTime = &end
+		}
+		return b
+	}
+
+	builds := []automotivev1alpha1.ImageBuild{
+		build("jane", phaseCompleted, now.Add(-2*time.Hour), 30, 1000),
+		build("jane", phaseFailed, now.Add(-3*time.Hour), 10, 0),
+		build("jane", "Building", now.Add(-20*time.Minute), 0, 0),
+		build("jane", phaseCompleted, now.AddDate(0, 0, -3), 60, 500),
+		build("joe", phaseCompleted, now.Add(-time.Hour), 15, 2000),
+	}
+
+	It("should summarize usage in the time range per user", func() {
+		usage := summarizeUsage(builds, now.Add(-24*time.Hour), now, now)
+		Expect(usage).To(HaveLen(2))
+
+		jane := usage["jane"]
+		Expect(jane.Builds).To(Equal(3))
+		Expect(jane.Succeeded).To(Equal(1))
+		Expect(jane.Failed).To(Equal(1))
+		Expect(jane.ActiveBuilds).To(Equal(1))
+		Expect(jane.BuildMinutes).To(BeNumerically("~", 60, 0.01))
+		Expect(jane.ArtifactStorageBytes).To(Equal(int64(1500)))
+
+		Expect(usage["joe"].Builds).To(Equal(1))
+	})
+
+	It("should pick the first matching override or the default quota", func() {
+		quotas := &automotivev1alpha1.BuildQuotasConfig{
+			Default: &automotivev1alpha1.BuildQuota{MaxConcurrentBuilds: 1},
+			Overrides: []automotivev1alpha1.BuildQuotaOverride{
+				{Group: "release", BuildQuota: automotivev1alpha1.BuildQuota{MaxConcurrentBuilds: 5}},
+				{User: "jane", BuildQuota: automotivev1alpha1.BuildQuota{MaxConcurrentBuilds: 3}},
+			},
+		}
+		Expect(quotas.QuotaFor("jane", []string{"release"}).MaxConcurrentBuilds).To(Equal(int32(5)))
+		Expect(quotas.QuotaFor("jane", nil).MaxConcurrentBuilds).To(Equal(int32(3)))
+		Expect(quotas.QuotaFor("joe", nil).MaxConcurrentBuilds).To(Equal(int32(1)))
+
+		var unset *automotivev1alpha1.BuildQuotasConfig
+		Expect(unset.QuotaFor("jane", nil)).To(BeNil())
+	})
+
+	It("should reject builds over any limit", func() {
+		jane := summarizeUsage(builds, now.Add(-24*time.Hour), now, now)["jane"]
+		storage := resource.MustParse("1500")
+
+		Expect(exceededQuota(&automotivev1alpha1.BuildQuota{MaxConcurrentBuilds: 2}, jane)).To(Succeed())
+		Expect(exceededQuota(&automotivev1alpha1.BuildQuota{MaxConcurrentBuilds: 1}, jane)).
+			To(MatchError(ContainSubstring("1 of 1 concurrent builds")))
+		Expect(exceededQuota(&automotivev1alpha1.BuildQuota{MaxBuildsPerDay: 3}, jane)).
+			To(MatchError(errQuotaExceeded))
+		Expect(exceededQuota(&automotivev1alpha1.BuildQuota{MaxArtifactStorage: &storage}, jane)).
+			To(MatchError(errQuotaExceeded))
+		Expect(exceededQuota(&automotivev1alpha1.BuildQuota{MaxBuildsPerDay: 3}, nil)).To(Succeed())
+	})
+
+	It("should serialize the build creations of each user", func() {
+		var locks userLocks
+		unlockJane := locks.lock("jane")
+
+		acquired := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			unlock := locks.lock("jane")
+			close(acquired)
+			unlock()
+		}()
+		Consistently(acquired, 100*time.Millisecond).ShouldNot(BeClosed())
+
+		unlockJoe := locks.lock("joe")
+		unlockJoe()
+
+		unlockJane()
+		Eventually(acquired).Should(BeClosed())
+		Eventually(func() int {
+			locks.mu.Lock()
+			defer locks.mu.Unlock()
+			return len(locks.locks)
+		}).Should(BeZero())
+	})
+
+	It("should parse usage time ranges", func() {
+		since, until, err := parseUsageRange("", "", now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(since).To(Equal(now.AddDate(0, 0, -defaultUsageDays)))
+		Expect(until).To(Equal(now))
+
+		_, _, err = parseUsageRange("2026-09-01", "", now)
+		Expect(err).To(HaveOccurred())
+		_, _, err = parseUsageRange("2026-10-02T00:00:00Z", "", now)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,167 @@
+package buildapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// headerArtifactSHA256 carries the build-time SHA-256 checksum of the artifact
+const headerArtifactSHA256 = "X-AIB-Artifact-SHA256"
+
+// errRangeNotSatisfiable is returned when a Range header cannot be served for the file size
+var errRangeNotSatisfiable = errors.New("requested range not satisfiable")
+
+// byteRange is a single resolved range of a file
+type byteRange struct {
+	start  int64
+	length int64
+}
+
+// contentRange returns the Content-Range header value for the range
+func (r byteRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.start+r.length-1, size)
+}
+
+// parseByteRange resolves a Range header against a file of the given size. A nil range with
+// a nil error means the header should be ignored and the whole file sent, which is what
+// RFC 9110 allows for malformed headers, other units and multi-range requests.
+func parseByteRange(header string, size int64) (*byteRange, error) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return nil, nil
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil, nil
+	}
+
+	if first == "" {
+		// Suffix range: the last N bytes
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return nil, nil
+		}
+		if n == 0 || size == 0 {
+			return nil, errRangeNotSatisfiable
+		}
+		n = min(n, size)
+		return &byteRange{start: size - n, length: n}, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return nil, nil
+	}
+	if start >= size {
+		return nil, errRangeNotSatisfiable
+	}
+	end := size - 1
+	if last != "" {
+		e, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || e < start {
+			return nil, nil
+		}
+		end = min(e, end)
+	}
+	return &byteRange{start: start, length: end - start + 1}, nil
+}
+
+// requestedRange returns the range to serve for the request, honoring If-Range.
+// Only entity tags are compared, so date validators always yield the full file.
+func requestedRange(r *http.Request, size int64, etag string) (*byteRange, error) {
+	header := r.Header.Get("Range")
+	if header == "" {
+		return nil, nil
+	}
+	if ifRange := strings.TrimSpace(r.Header.Get("If-Range")); ifRange != "" {
+		if etag == "" || ifRange != etag {
+			return nil, nil
+		}
+	}
+	return parseByteRange(header, size)
+}
+
+// artifactETag returns a strong entity tag for a file of a completed build. Artifacts do not
+// change once the build completes, so the build-time checksum is used when it belongs to the
+// file, otherwise the build UID, file size and completion time identify the content.
+func artifactETag(build *automotivev1alpha1.ImageBuild, filename string, size int64) string {
+	if build.Status.ArtifactSHA256 != "" && filename == strings.TrimSpace(build.Status.ArtifactFileName) {
+		return fmt.Sprintf("\"sha256-%s\"", build.Status.ArtifactSHA256)
+	}
+	var completed int64
+	if build.Status.CompletionTime != nil {
+		completed = build.Status.CompletionTime.Unix()
+	}
+	return fmt.Sprintf("\"%s-%d-%d\"", build.UID, size, completed)
+}
+
+// servePodFile streams a file from the artifact pod, honoring Range and If-Range.
+// Callers set the descriptive headers (Content-Type, Content-Disposition, ...) beforehand.
+func servePodFile(
+	c *gin.Context, restCfg *rest.Config, podClient rest.Interface,
+	namespace, podName, podPath string, size int64, etag string,
+) {
+	h := c.Writer.Header()
+	h.Set("Accept-Ranges", "bytes")
+	h.Set("ETag", etag)
+
+	rng, err := requestedRange(c.Request, size, etag)
+	if err != nil {
+		h.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+		c.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	length := size
+	command := []string{"cat", podPath}
+	if rng != nil {
+		status = http.StatusPartialContent
+		length = rng.length
+		h.Set("Content-Range", rng.contentRange(size))
+		// tail seeks to the offset, so resuming large images does not re-read the prefix
+		command = []string{
+			"sh", "-c", "tail -c +\"$2\" \"$1\" | head -c \"$3\"",
+			"--", podPath, strconv.FormatInt(rng.start+1, 10), strconv.FormatInt(rng.length, 10),
+		}
+	}
+
+	streamReq := podClient.Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: "fileserver",
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, kscheme.ParameterCodec)
+
+	streamExec, err := remotecommand.NewSPDYExecutor(restCfg, http.MethodPost, streamReq.URL())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("executor (stream): %v", err)})
+		return
+	}
+
+	h.Set("Content-Length", strconv.FormatInt(length, 10))
+	c.Status(status)
+	if f, ok := c.Writer.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	_ = streamExec.StreamWithContext(
+		c.Request.Context(), remotecommand.StreamOptions{Stdout: c.Writer, Stderr: io.Discard},
+	)
+}
@@ -0,0 +1,68 @@
+package buildapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Artifact range requests", func() {
+	const size = int64(1000)
+
+	DescribeTable("parseByteRange",
+		func(header string, expected *byteRange, expectErr bool) {
+			rng, err := parseByteRange(header, size)
+			if expectErr {
+				Expect(err).To(MatchError(errRangeNotSatisfiable))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rng).To(Equal(expected))
+		},
+		Entry("open-ended range", "bytes=100-", &byteRange{start: 100, length: 900}, false),
+		Entry("bounded range", "bytes=0-9", &byteRange{start: 0, length: 10}, false),
+		Entry("end past the file is clamped", "bytes=990-5000", &byteRange{start: 990, length: 10}, false),
+		Entry("suffix range", "bytes=-10", &byteRange{start: 990, length: 10}, false),
+		Entry("suffix larger than the file", "bytes=-5000", &byteRange{start: 0, length: 1000}, false),
+		Entry("start past the end", "bytes=1000-", nil, true),
+		Entry("empty suffix", "bytes=-0", nil, true),
+		Entry("multiple ranges are ignored", "bytes=0-1,5-6", nil, false),
+		Entry("other units are ignored", "items=0-1", nil, false),
+		Entry("malformed ranges are ignored", "bytes=abc-", nil, false),
+		Entry("reversed ranges are ignored", "bytes=9-0", nil, false),
+	)
+
+	It("should honor If-Range only when the entity tag matches", func() {
+		req := httptest.NewRequest(http.MethodGet, "/v1/builds/b/artifact", nil)
+		req.Header.Set("Range", "bytes=500-")
+
+		req.Header.Set("If-Range", `"sha256-abc"`)
+		rng, err := requestedRange(req, size, `"sha256-abc"`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rng).To(Equal(&byteRange{start: 500, length: 500}))
+
+		req.Header.Set("If-Range", `"sha256-other"`)
+		rng, err = requestedRange(req, size, `"sha256-abc"`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rng).To(BeNil())
+	})
+
+	It("should derive entity tags from the build-time checksum", func() {
+		build := &automotivev1alpha1.ImageBuild{
+			ObjectMeta: metav1.ObjectMeta{UID: "uid-1"},
+			Status: automotivev1alpha1.ImageBuildStatus{
+				ArtifactFileName: "autosd-qemu.qcow2.gz",
+				ArtifactSHA256:   "abc123",
+				CompletionTime:   &metav1.Time{},
+			},
+		}
+		Expect(artifactETag(build, "autosd-qemu.qcow2.gz", size)).To(Equal(`"sha256-abc123"`))
+		Expect(artifactETag(build, "part-1.tar.gz", size)).To(HavePrefix(`"uid-1-1000-`))
+		Expect(byteRange{start: 10, length: 5}.contentRange(size)).To(Equal("bytes 10-14/1000"))
+	})
+})
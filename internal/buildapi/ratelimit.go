@@ -0,0 +1,156 @@
+package buildapi
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authnv1 "k8s.io/api/authentication/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// rateLimitedOperation names an operation limited by the rateLimits settings
+type rateLimitedOperation string
+
+const (
+	rateLimitBuildCreation     rateLimitedOperation = "build creation"
+	rateLimitLogStreams        rateLimitedOperation = "log streams"
+	rateLimitArtifactDownloads rateLimitedOperation = "artifact downloads"
+
+	// maxRateLimitBuckets bounds the buckets kept per limiter before idle ones are evicted
+	maxRateLimitBuckets = 10000
+)
+
+// tokenBucket holds the tokens left for one user or client IP
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// rateLimiter keeps a token bucket per key, refilled at perSecond tokens per second up to burst
+type rateLimiter struct {
+	perSecond float64
+	burst     float64
+	now       func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// newRateLimiter returns a limiter for the limit, or nil if the operation is not limited
+func newRateLimiter(limit *automotivev1alpha1.RateLimit) *rateLimiter {
+	if limit == nil || limit.RequestsPerMinute <= 0 {
+		return nil
+	}
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = limit.RequestsPerMinute
+	}
+	return &rateLimiter{
+		perSecond: float64(limit.RequestsPerMinute) / 60,
+		burst:     float64(burst),
+		now:       time.Now,
+		buckets:   map[string]*tokenBucket{},
+	}
+}
+
+// take uses a token of the key's bucket. If none is left, it returns false and how long until the
+// next token. A nil limiter allows everything.
+func (l *rateLimiter) take(key string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxRateLimitBuckets {
+			l.evictFull(now)
+		}
+		bucket = &tokenBucket{tokens: l.burst, updated: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.updated).Seconds()*l.perSecond)
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / l.perSecond * float64(time.Second))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// evictFull drops buckets that have refilled completely, as they are the same as new ones
+func (l *rateLimiter) evictFull(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.updated).Seconds()*l.perSecond >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// operationLimiters holds the limiter of each rate-limited operation
+type operationLimiters map[rateLimitedOperation]*rateLimiter
+
+func newOperationLimiters(cfg *automotivev1alpha1.OperationRateLimits) operationLimiters {
+	if cfg == nil {
+		return nil
+	}
+	return operationLimiters{
+		rateLimitBuildCreation:     newRateLimiter(cfg.BuildCreation),
+		rateLimitLogStreams:        newRateLimiter(cfg.LogStreams),
+		rateLimitArtifactDownloads: newRateLimiter(cfg.ArtifactDownloads),
+	}
+}
+
+// EnableRateLimits applies the per-user and per-IP rate limits of the configuration
+func (a *APIServer) EnableRateLimits(cfg *automotivev1alpha1.RateLimitsConfig) {
+	if cfg == nil {
+		a.userLimits, a.ipLimits = nil, nil
+		return
+	}
+	a.userLimits = newOperationLimiters(cfg.PerUser)
+	a.ipLimits = newOperationLimiters(cfg.PerIP)
+}
+
+// EnableTrustedProxies trusts the X-Forwarded-For header of the given proxy addresses or CIDRs in
+// addition to the oauth-proxy sidecar
+func (a *APIServer) EnableTrustedProxies(proxies []string) error {
+	return a.router.SetTrustedProxies(append(slices.Clone(sidecarProxies), proxies...))
+}
+
+// rateLimit rejects requests of users or client IPs that exceeded the operation's rate limit with
+// 429 and a Retry-After header
+func (a *APIServer) rateLimit(op rateLimitedOperation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if allowed, wait := a.ipLimits[op].take(c.ClientIP()); !allowed {
+			rejectRateLimited(c, op, "ip", wait)
+			return
+		}
+		user, _ := c.Get(userInfoKey)
+		if userInfo, ok := user.(authnv1.UserInfo); ok && userInfo.Username != "" {
+			if allowed, wait := a.userLimits[op].take(userInfo.Username); !allowed {
+				rejectRateLimited(c, op, "user", wait)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+func rejectRateLimited(c *gin.Context, op rateLimitedOperation, scope string, wait time.Duration) {
+	rateLimitedRequestsTotal.WithLabelValues(string(op), scope).Inc()
+	seconds := max(1, int(math.Ceil(wait.Seconds())))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"error": fmt.Sprintf("rate limit exceeded for %s, retry in %ds", op, seconds),
+	})
+}
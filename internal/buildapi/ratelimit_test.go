@@ -0,0 +1,130 @@
+package buildapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	authnv1 "k8s.io/api/authentication/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+var _ = Describe("Rate limits", func() {
+	var (
+		limiter *rateLimiter
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		limiter = newRateLimiter(&automotivev1alpha1.RateLimit{RequestsPerMinute: 6, Burst: 2})
+		limiter.now = func() time.Time { return now }
+	})
+
+	It("should allow bursts and refill over time", func() {
+		for range 2 {
+			allowed, _ := limiter.take("jane")
+			Expect(allowed).To(BeTrue())
+		}
+		allowed, wait := limiter.take("jane")
+		Expect(allowed).To(BeFalse())
+		Expect(wait).To(BeNumerically("~", 10*time.Second, time.Millisecond))
+
+		allowed, _ = limiter.take("joe")
+		Expect(allowed).To(BeTrue())
+
+		now = now.Add(10 * time.Second)
+		allowed, _ = limiter.take("jane")
+		Expect(allowed).To(BeTrue())
+	})
+
+	It("should default the burst and leave unset operations unlimited", func() {
+		Expect(newRateLimiter(&automotivev1alpha1.RateLimit{RequestsPerMinute: 30}).burst).To(Equal(30.0))
+		Expect(newRateLimiter(nil)).To(BeNil())
+
+		var unlimited *rateLimiter
+		allowed, _ := unlimited.take("jane")
+		Expect(allowed).To(BeTrue())
+	})
+
+	It("should evict refilled buckets when full", func() {
+		for i := range maxRateLimitBuckets {
+			limiter.take(string(rune(i)))
+		}
+		now = now.Add(time.Minute)
+		limiter.take("jane")
+		Expect(limiter.buckets).To(HaveLen(1))
+	})
+
+	It("should reject requests over the limit with Retry-After", func() {
+		api := &APIServer{}
+		api.EnableRateLimits(&automotivev1alpha1.RateLimitsConfig{
+			PerUser: &automotivev1alpha1.OperationRateLimits{
+				BuildCreation: &automotivev1alpha1.RateLimit{RequestsPerMinute: 1},
+			},
+		})
+		router := gin.New()
+		router.POST("/v1/builds", func(c *gin.Context) {
+			c.Set(userInfoKey, authnv1.UserInfo{Username: "jane"})
+		}, api.rateLimit(rateLimitBuildCreation), func(c *gin.Context) { c.Status(http.StatusAccepted) })
+		router.GET("/v1/builds/:name/logs", api.rateLimit(rateLimitLogStreams), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/builds", nil))
+		Expect(w.Code).To(Equal(http.StatusAccepted))
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/builds", nil))
+		Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(w.Header().Get("Retry-After")).To(Equal("60"))
+
+		for range 3 {
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/builds/b/logs", nil))
+			Expect(w.Code).To(Equal(http.StatusOK))
+		}
+	})
+
+	It("should ignore forwarded client addresses of untrusted peers", func() {
+		api := NewAPIServer(":0", GinkgoLogr)
+		api.EnableRateLimits(&automotivev1alpha1.RateLimitsConfig{
+			PerIP: &automotivev1alpha1.OperationRateLimits{
+				BuildCreation: &automotivev1alpha1.RateLimit{RequestsPerMinute: 1},
+			},
+		})
+		api.router.POST("/test/builds", api.rateLimit(rateLimitBuildCreation), func(c *gin.Context) {
+			c.String(http.StatusAccepted, c.ClientIP())
+		})
+		post := func(remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/test/builds", nil)
+			req.RemoteAddr = remoteAddr
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+			w := httptest.NewRecorder()
+			api.router.ServeHTTP(w, req)
+			return w
+		}
+
+		w := post("192.0.2.10:40000", "198.51.100.1")
+		Expect(w.Code).To(Equal(http.StatusAccepted))
+		Expect(w.Body.String()).To(Equal("192.0.2.10"))
+		Expect(post("192.0.2.10:40001", "198.51.100.2").Code).To(Equal(http.StatusTooManyRequests))
+
+		By("believing the oauth-proxy sidecar")
+		w = post("127.0.0.1:40000", "198.51.100.3")
+		Expect(w.Code).To(Equal(http.StatusAccepted))
+		Expect(w.Body.String()).To(Equal("198.51.100.3"))
+
+		By("believing configured proxies")
+		Expect(api.EnableTrustedProxies([]string{"192.0.2.0/24"})).To(Succeed())
+		w = post("192.0.2.10:40002", "198.51.100.4")
+		Expect(w.Code).To(Equal(http.StatusAccepted))
+		Expect(w.Body.String()).To(Equal("198.51.100.4"))
+		Expect(api.EnableTrustedProxies([]string{"not-an-address"})).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,185 @@
+package buildapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/objectstore"
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// s3PresignExpiry is how long redirect URLs to S3-compatible storage stay valid
+	s3PresignExpiry = time.Hour
+
+	// s3ChecksumsFile and s3ProvenanceFile are uploaded next to the artifact
+	s3ChecksumsFile  = "SHA256SUMS"
+	s3ProvenanceFile = "provenance.json"
+)
+
+// s3ArtifactKey returns the object key of the build's artifact in S3-compatible storage,
+// or an empty string if the artifact was not uploaded there
+func s3ArtifactKey(build *automotivev1alpha1.ImageBuild) string {
+	if build.Spec.Publishers == nil || build.Spec.Publishers.S3 == nil {
+		return ""
+	}
+	return strings.TrimSpace(build.Status.S3ArtifactKey)
+}
+
+// s3ObjectKeyFor maps a requested file name to an object key next to the uploaded artifact.
+// Only the artifact itself, its checksums and its provenance are reachable.
+func s3ObjectKeyFor(build *automotivev1alpha1.ImageBuild, filename string) (string, bool) {
+	key := s3ArtifactKey(build)
+	if key == "" {
+		return "", false
+	}
+	switch filename {
+	case path.Base(key):
+		return key, true
+	case s3ChecksumsFile, s3ProvenanceFile:
+		return path.Join(path.Dir(key), filename), true
+	default:
+		return "", false
+	}
+}
+
+// newObjectStoreClient creates a client for an S3 endpoint using the given credentials secret
+func newObjectStoreClient(
+	ctx context.Context, k8sClient client.Client, namespace, secretName, endpoint, region string, insecure bool,
+) (*objectstore.Client, error) {
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("error reading S3 credentials secret %s: %w", secretName, err)
+	}
+	return objectstore.New(endpoint, region, objectstore.CredentialsFromSecret(secret), insecure)
+}
+
+// serveS3Object sends an object of the build's S3 publisher to the client
+func (a *APIServer) serveS3Object(
+	c *gin.Context, k8sClient client.Client, build *automotivev1alpha1.ImageBuild, key string,
+) {
+	pub := build.Spec.Publishers.S3
+	store, err := newObjectStoreClient(
+		c.Request.Context(), k8sClient, build.Namespace, pub.Secret, pub.Endpoint, pub.Region, pub.InsecureSkipTLSVerify,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	a.proxyS3Object(c, store, pub.Bucket, key, func(h http.Header) {
+		if path.Base(key) != path.Base(build.Status.S3ArtifactKey) {
+			return
+		}
+		h.Set("X-AIB-Artifact-Type", "file")
+		if build.Spec.Compression != "" {
+			h.Set("X-AIB-Compression", build.Spec.Compression)
+		}
+		if build.Status.ArtifactSHA256 != "" {
+			h.Set(headerArtifactSHA256, build.Status.ArtifactSHA256)
+		}
+	})
+}
+
+// proxyS3Object sends an object from S3-compatible storage to the client. By default the object
+// is proxied through the API server; with ?redirect=true the client is redirected to a
+// presigned URL instead, which requires the storage endpoint to be reachable by the client.
+// decorate may add descriptive headers before the response is written.
+func (a *APIServer) proxyS3Object(
+	c *gin.Context, store *objectstore.Client, bucket, key string, decorate func(http.Header),
+) {
+	if c.Query("redirect") == "true" {
+		presigned, err := store.PresignGetObject(bucket, key, s3PresignExpiry)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("presign: %v", err)})
+			return
+		}
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(http.StatusTemporaryRedirect, presigned)
+		return
+	}
+
+	resp, err := store.GetObject(c.Request.Context(), bucket, key, conditionalHeaders(c))
+	if err != nil {
+		a.log.Error(err, "failed to fetch object from S3 storage", "bucket", bucket, "key", key)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "object storage unavailable"})
+		return
+	}
+	a.relayDownload(c, resp, path.Base(key), decorate)
+}
+
+// conditionalHeaders returns the range and cache validation headers of the request,
+// which are forwarded to upstream storage
+func conditionalHeaders(c *gin.Context) http.Header {
+	forward := http.Header{}
+	for _, h := range []string{"Range", "If-Range", "If-None-Match", "If-Modified-Since"} {
+		if v := c.GetHeader(h); v != "" {
+			forward.Set(h, v)
+		}
+	}
+	return forward
+}
+
+// relayDownload sends a download response fetched from object storage or an HTTP server to
+// the client, passing through the headers that range requests and caching depend on
+func (a *APIServer) relayDownload(c *gin.Context, resp *http.Response, filename string, decorate func(http.Header)) {
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			a.log.Error(err, "failed to close upstream response body", "file", filename)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		return
+	case resp.StatusCode >= http.StatusBadRequest && resp.StatusCode != http.StatusRequestedRangeNotSatisfiable:
+		a.log.Info("upstream storage returned an error", "file", filename, "status", resp.Status)
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("upstream storage returned %s", resp.Status)})
+		return
+	}
+
+	for _, h := range []string{"Content-Length", "Content-Range", "Accept-Ranges", "ETag", "Last-Modified"} {
+		if v := resp.Header.Get(h); v != "" {
+			c.Writer.Header().Set(h, v)
+		}
+	}
+	c.Writer.Header().Set("Content-Type", artifactContentType(filename))
+	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	if decorate != nil {
+		decorate(c.Writer.Header())
+	}
+	c.Status(resp.StatusCode)
+
+	if _, err := io.Copy(c.Writer, resp.Body); err != nil {
+		a.log.Error(err, "failed to stream download from upstream storage", "file", filename)
+	}
+}
+
+// artifactContentType returns the content type for an artifact based on its file name
+func artifactContentType(filename string) string {
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".lz4"):
+		return "application/x-lz4"
+	case strings.HasSuffix(lower, ".gz"):
+		return "application/gzip"
+	case strings.HasSuffix(lower, ".xz"):
+		return "application/x-xz"
+	case strings.HasSuffix(lower, ".json"):
+		return "application/json"
+	case filename == s3ChecksumsFile:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
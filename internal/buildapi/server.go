@@ -7,18 +7,21 @@ import (
 	_ "embed"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-logr/logr"
-	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +33,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/remotecommand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/buildapi/catalog"
@@ -50,8 +54,14 @@ const (
 	statusUnknown   = "unknown"
 	statusMissing   = "MISSING"
 	buildAPIName    = "ado-build-api"
+
+	// imageBuildsResource is the resource name of ImageBuilds in access reviews
+	imageBuildsResource = "imagebuilds"
 )
 
+// artifactTypeDelta marks the binary delta in artifact listings and downloads
+const artifactTypeDelta = "delta"
+
 // APILimits holds configurable limits for the API server
 type APILimits struct {
 	MaxManifestSize             int64
@@ -77,11 +87,22 @@ type APIServer struct {
 	addr   string
 	log    logr.Logger
 	limits APILimits
+
+	tokenReviews *tokenReviewCache
+	quotaLocks   userLocks
+	oidc         *oidcAuthenticator
+	audit        *auditLog
+	userLimits   operationLimiters
+	ipLimits     operationLimiters
+	watches      *buildWatchHub
 }
 
 //go:embed openapi.yaml
 var embeddedOpenAPI []byte
 
+// sidecarProxies are the addresses of the oauth-proxy sidecar, which forwards requests on OpenShift
+var sidecarProxies = []string{"127.0.0.1", "::1"}
+
 // NewAPIServer creates a new API server
 func NewAPIServer(addr string, logger logr.Logger) *APIServer {
 	return NewAPIServerWithLimits(addr, logger, DefaultAPILimits())
@@ -95,7 +116,14 @@ func NewAPIServerWithLimits(addr string, logger logr.Logger, limits APILimits) *
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	a := &APIServer{addr: addr, log: logger, limits: limits}
+	a := &APIServer{
+		addr:         addr,
+		log:          logger,
+		limits:       limits,
+		tokenReviews: newTokenReviewCache(tokenReviewTTL, maxCachedTokenReviews),
+		audit:        newAuditLog(logger.WithName("audit")),
+		watches:      newBuildWatchHub(),
+	}
 	a.router = a.createRouter()
 	a.server = &http.Server{Addr: addr, Handler: a.router}
 	return a
@@ -193,14 +221,16 @@ func (a *APIServer) Start(ctx context.Context) error {
 
 func (a *APIServer) createRouter() *gin.Engine {
 	router := gin.New()
+	// Client IPs key rate limits and the audit trail, so forwarded addresses are only believed from
+	// trusted proxies
+	if err := router.SetTrustedProxies(sidecarProxies); err != nil {
+		a.log.Error(err, "failed to set trusted proxies")
+	}
 	router.Use(gin.Recovery())
 
-	router.Use(func(c *gin.Context) {
-		reqID := uuid.New().String()
-		c.Set("reqID", reqID)
-		a.log.Info("http request", "method", c.Request.Method, "path", c.Request.URL.Path, "reqID", reqID)
-		c.Next()
-	})
+	router.Use(a.correlationMiddleware(), a.auditMiddleware())
+
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
 
 	v1 := router.Group("/v1")
 	{
@@ -212,28 +242,69 @@ func (a *APIServer) createRouter() *gin.Engine {
 			c.Data(http.StatusOK, "application/yaml", embeddedOpenAPI)
 		})
 
+		v1.GET("/auth/config", a.handleAuthConfig)
+
 		buildsGroup := v1.Group("/builds")
-		buildsGroup.Use(a.authMiddleware())
+		buildsGroup.Use(a.authMiddleware(), a.tenancyMiddleware())
 		{
-			buildsGroup.POST("", a.handleCreateBuild)
-			buildsGroup.GET("", a.handleListBuilds)
-			buildsGroup.GET("/:name", a.handleGetBuild)
-			buildsGroup.GET("/:name/logs", a.handleStreamLogs)
-			buildsGroup.GET("/:name/artifact", a.handleStreamDefaultArtifact)
-			buildsGroup.GET("/:name/artifacts", a.handleListArtifacts)
-			buildsGroup.GET("/:name/artifacts/:file", a.handleStreamArtifactPart)
-			buildsGroup.GET("/:name/artifact/:filename", a.handleStreamArtifactByFilename)
-			buildsGroup.GET("/:name/template", a.handleGetBuildTemplate)
-			buildsGroup.POST("/:name/uploads", a.handleUploadFiles)
-		}
-
-		// Register catalog routes with authentication
+			buildsGroup.POST("", a.rateLimit(rateLimitBuildCreation),
+				a.authorize("create", imageBuildsResource, ""), a.handleCreateBuild)
+			buildsGroup.GET("", a.authorize("list", imageBuildsResource, ""), a.handleListBuilds)
+			buildsGroup.GET("/:name", a.authorize("get", imageBuildsResource, ""), a.handleGetBuild)
+			buildsGroup.GET("/:name/events", a.authorize("watch", imageBuildsResource, ""), a.handleBuildEvents)
+			buildsGroup.GET("/:name/logs", a.rateLimit(rateLimitLogStreams),
+				a.authorize("get", imageBuildsResource, "logs"), a.handleStreamLogs)
+			buildsGroup.GET("/:name/artifact", a.rateLimit(rateLimitArtifactDownloads),
+				a.authorize("get", imageBuildsResource, "artifacts"), a.handleStreamDefaultArtifact)
+			buildsGroup.GET("/:name/artifacts",
+				a.authorize("get", imageBuildsResource, "artifacts"), a.handleListArtifacts)
+			buildsGroup.GET("/:name/artifacts/:file", a.rateLimit(rateLimitArtifactDownloads),
+				a.authorize("get", imageBuildsResource, "artifacts"), a.handleStreamArtifactPart)
+			buildsGroup.GET("/:name/artifact/:filename", a.rateLimit(rateLimitArtifactDownloads),
+				a.authorize("get", imageBuildsResource, "artifacts"), a.handleStreamArtifactByFilename)
+			buildsGroup.GET("/:name/template", a.authorize("get", imageBuildsResource, ""), a.handleGetBuildTemplate)
+			buildsGroup.POST("/:name/uploads",
+				a.authorize("create", imageBuildsResource, "uploads"), a.handleUploadFiles)
+			buildsGroup.POST("/:name/flash", a.authorize("create", flashJobsResource, ""), a.handleCreateFlash)
+		}
+
+		flashGroup := v1.Group("/flash")
+		flashGroup.Use(a.authMiddleware(), a.tenancyMiddleware())
+		{
+			flashGroup.GET("/:name", a.authorize("get", flashJobsResource, ""), a.handleGetFlash)
+			flashGroup.GET("/:name/logs", a.rateLimit(rateLimitLogStreams),
+				a.authorize("get", flashJobsResource, "logs"), a.handleStreamFlashLogs)
+		}
+
+		imagesGroup := v1.Group("/images")
+		imagesGroup.Use(a.authMiddleware(), a.tenancyMiddleware())
+		{
+			imagesGroup.GET("/:name/download", a.rateLimit(rateLimitArtifactDownloads),
+				a.authorize("get", "images", "download"), a.handleDownloadImage)
+		}
+
+		v1.GET("/usage", a.authMiddleware(), a.handleGetUsage)
+		v1.GET("/audit", a.authMiddleware(), a.handleQueryAudit)
+
+		tokensGroup := v1.Group("/tokens")
+		tokensGroup.Use(a.authMiddleware())
+		{
+			tokensGroup.POST("", a.handleCreateToken)
+			tokensGroup.GET("", a.handleListTokens)
+			tokensGroup.DELETE("/:id", a.handleRevokeToken)
+		}
+
+		// Register catalog routes with authentication and authorization
 		catalogClient, err := a.getCatalogClient()
 		if err != nil {
 			a.log.Error(err, "failed to create catalog client, catalog routes will not be available")
 		} else if catalogClient != nil {
 			a.log.Info("registering catalog routes")
-			catalog.RegisterRoutes(v1, catalogClient, a.log)
+			catalog.RegisterRoutes(v1, catalogClient, a.log, resolveNamespace(), catalog.AccessControl{
+				Authenticate: a.authMiddleware(),
+				Authorize:    a.reviewAccess,
+				Requester:    authenticatedUser,
+			})
 		}
 	}
 
@@ -272,6 +343,9 @@ func (a *APIServer) getCatalogClient() (client.Client, error) {
 	if err := corev1.AddToScheme(scheme); err != nil {
 		return nil, fmt.Errorf("failed to add core scheme: %w", err)
 	}
+	if err := authnv1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add authentication scheme: %w", err)
+	}
 
 	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
 	if err != nil {
@@ -298,6 +372,11 @@ func (a *APIServer) handleCreateBuild(c *gin.Context) {
 }
 
 func (a *APIServer) handleListBuilds(c *gin.Context) {
+	if c.Query("watch") == "true" {
+		a.log.Info("watch builds", "reqID", c.GetString("reqID"))
+		a.watchBuilds(c)
+		return
+	}
 	a.log.Info("list builds", "reqID", c.GetString("reqID"))
 	listBuilds(c)
 }
@@ -461,7 +540,7 @@ func processPodLogs(
 }
 
 func (a *APIServer) streamLogs(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 
 	k8sClient, err := getClientFromRequest(c)
 	if err != nil {
@@ -503,7 +582,7 @@ func (a *APIServer) streamLogs(c *gin.Context, name string) {
 
 	setupLogStreamHeaders(c)
 
-	pipelineRunSelector := "tekton.dev/pipelineRun=" + tr + ",tekton.dev/memberOf=tasks"
+	pipelineRunPods := client.MatchingLabels{"tekton.dev/pipelineRun": tr, "tekton.dev/memberOf": "tasks"}
 	var hadStream bool
 	streamedContainers := make(map[string]map[string]bool)
 	completedPods := make(map[string]bool)
@@ -515,8 +594,8 @@ func (a *APIServer) streamLogs(c *gin.Context, name string) {
 		default:
 		}
 
-		pods, err := cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: pipelineRunSelector})
-		if err != nil {
+		pods := &corev1.PodList{}
+		if err := k8sClient.List(ctx, pods, client.InNamespace(namespace), pipelineRunPods); err != nil {
 			if _, writeErr := fmt.Fprintf(c.Writer, "\n[Error listing pods: %v]\n", err); writeErr != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to write error message: %v\n", writeErr)
 			}
@@ -795,9 +874,118 @@ func validateBuildRequest(req *BuildRequest, maxManifestSize int64) error {
 		}
 	}
 
+	if req.S3Upload != nil {
+		if err := validateS3Upload(req.S3Upload); err != nil {
+			return fmt.Errorf("invalid s3Upload: %v", err)
+		}
+	}
+
+	if req.Delta != nil {
+		if err := validateDeltaRequest(req.Delta); err != nil {
+			return fmt.Errorf("invalid delta: %v", err)
+		}
+	}
+
+	if req.PublishToCatalog != nil {
+		if req.PushRepository == "" && req.ContainerPush == "" && req.ExportOCI == "" {
+			return fmt.Errorf("publishToCatalog requires pushRepository, containerPush or exportOci")
+		}
+		if err := validateCatalogPublish(req.PublishToCatalog); err != nil {
+			return fmt.Errorf("invalid publishToCatalog: %v", err)
+		}
+	}
+
 	return nil
 }
 
+// validateCatalogPublish checks the catalog name template, tags and target notes.
+// None of them reach a shell, so the template may use braces.
+func validateCatalogPublish(p *CatalogPublish) error {
+	if len(p.NameTemplate) > 253 {
+		return fmt.Errorf("nameTemplate too long (max 253 characters)")
+	}
+	if _, err := template.New("catalogName").Parse(p.NameTemplate); err != nil {
+		return fmt.Errorf("invalid nameTemplate: %v", err)
+	}
+	for _, tag := range p.Tags {
+		if err := validateInput(tag, "tag", 63, false, "/", " "); err != nil {
+			return err
+		}
+	}
+	if len(p.TargetNotes) > 1024 {
+		return fmt.Errorf("targetNotes too long (max 1024 characters)")
+	}
+	return nil
+}
+
+// validateDeltaRequest checks that a delta request names exactly one base and a known method
+func validateDeltaRequest(d *DeltaRequest) error {
+	if (d.BaseBuild == "") == (d.BaseCatalogImage == "") {
+		return fmt.Errorf("exactly one of baseBuild or baseCatalogImage must be set")
+	}
+	if err := validateInput(d.BaseBuild, "baseBuild", 253, true, "/", " "); err != nil {
+		return err
+	}
+	if err := validateInput(d.BaseCatalogImage, "baseCatalogImage", 253, true, "/", " "); err != nil {
+		return err
+	}
+	switch d.Method {
+	case "", "zstd", "bsdiff", "ostree":
+		return nil
+	default:
+		return fmt.Errorf("unsupported method %q (use zstd, bsdiff or ostree)", d.Method)
+	}
+}
+
+// errInvalidDeltaBase marks delta requests naming a catalog image that cannot be used as delta base
+var errInvalidDeltaBase = errors.New("invalid delta base")
+
+// checkDeltaBase looks up the catalog image a delta request uses as base. It returns a warning if the
+// image is deprecated, and refuses end-of-life images, which are no longer verified.
+func checkDeltaBase(ctx context.Context, k8sClient client.Client, namespace string, d *DeltaRequest) (string, error) {
+	if d == nil || d.BaseCatalogImage == "" {
+		return "", nil
+	}
+	base := &automotivev1alpha1.CatalogImage{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: d.BaseCatalogImage, Namespace: namespace}, base); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", fmt.Errorf("%w: catalog image %s not found", errInvalidDeltaBase, d.BaseCatalogImage)
+		}
+		return "", fmt.Errorf("error reading catalog image %s: %w", d.BaseCatalogImage, err)
+	}
+	return deltaBaseWarning(base)
+}
+
+// deltaBaseWarning returns the lifecycle warning of a delta base catalog image, or an error if the
+// image is end-of-life
+func deltaBaseWarning(base *automotivev1alpha1.CatalogImage) (string, error) {
+	warning := catalog.LifecycleWarning(base)
+	if base.EffectiveLifecycleState() == automotivev1alpha1.LifecycleEOL {
+		return "", fmt.Errorf("%w: %s", errInvalidDeltaBase, warning)
+	}
+	return warning, nil
+}
+
+// validateS3Upload checks that an S3 upload target is complete and safe to pass to the upload task
+func validateS3Upload(s3 *S3Upload) error {
+	if err := validateInput(s3.Endpoint, "endpoint", 500, false, " "); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(s3.Endpoint, "http://") && !strings.HasPrefix(s3.Endpoint, "https://") {
+		return fmt.Errorf("endpoint must be an http or https URL")
+	}
+	if err := validateInput(s3.Bucket, "bucket", 63, false, "/", " "); err != nil {
+		return err
+	}
+	if err := validateInput(s3.Prefix, "prefix", 512, true, " "); err != nil {
+		return err
+	}
+	if err := validateInput(s3.Region, "region", 64, true, "/", " "); err != nil {
+		return err
+	}
+	return validateInput(s3.SecretRef, "secretRef", 253, false, "/", " ")
+}
+
 // applyBuildDefaults sets default values for build request fields
 func applyBuildDefaults(req *BuildRequest) error {
 	if req.Distro == "" {
@@ -906,17 +1094,53 @@ func setupBuildSecrets(
 	return envSecretRef, pushSecretName, nil
 }
 
+// buildDeltaSpec converts a delta request into the ImageBuild delta configuration
+func buildDeltaSpec(d *DeltaRequest) *automotivev1alpha1.DeltaSpec {
+	if d == nil {
+		return nil
+	}
+	return &automotivev1alpha1.DeltaSpec{
+		BaseImageBuild:   d.BaseBuild,
+		BaseCatalogImage: d.BaseCatalogImage,
+		Method:           d.Method,
+	}
+}
+
+// buildCatalogPublishSpec converts a catalog publish request into the ImageBuild configuration
+func buildCatalogPublishSpec(p *CatalogPublish) *automotivev1alpha1.CatalogPublishSpec {
+	if p == nil {
+		return nil
+	}
+	return &automotivev1alpha1.CatalogPublishSpec{
+		NameTemplate: p.NameTemplate,
+		Tags:         p.Tags,
+		TargetNotes:  p.TargetNotes,
+	}
+}
+
 // buildPublishersConfig creates Publishers configuration if needed
-func buildPublishersConfig(pushRepository, pushSecretName string) *automotivev1alpha1.Publishers {
-	if pushRepository == "" {
+func buildPublishersConfig(pushRepository, pushSecretName string, s3 *S3Upload) *automotivev1alpha1.Publishers {
+	if pushRepository == "" && s3 == nil {
 		return nil
 	}
-	return &automotivev1alpha1.Publishers{
-		Registry: &automotivev1alpha1.RegistryPublisher{
+	publishers := &automotivev1alpha1.Publishers{}
+	if pushRepository != "" {
+		publishers.Registry = &automotivev1alpha1.RegistryPublisher{
 			RepositoryURL: pushRepository,
 			Secret:        pushSecretName,
-		},
+		}
 	}
+	if s3 != nil {
+		publishers.S3 = &automotivev1alpha1.S3Publisher{
+			Endpoint:              s3.Endpoint,
+			Bucket:                s3.Bucket,
+			Prefix:                s3.Prefix,
+			Region:                s3.Region,
+			Secret:                s3.SecretRef,
+			InsecureSkipTLSVerify: s3.InsecureSkipTLSVerify,
+		}
+	}
+	return publishers
 }
 
 func (a *APIServer) createBuild(c *gin.Context) {
@@ -925,6 +1149,7 @@ func (a *APIServer) createBuild(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON request"})
 		return
 	}
+	c.Set(auditNameKey, req.Name)
 
 	needsUpload := strings.Contains(req.Manifest, "source_path")
 
@@ -945,7 +1170,7 @@ func (a *APIServer) createBuild(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	requestedBy := resolveRequester(c)
 
 	existing := &automotivev1alpha1.ImageBuild{}
@@ -957,6 +1182,42 @@ func (a *APIServer) createBuild(c *gin.Context) {
 		return
 	}
 
+	var warnings []string
+	deltaWarning, err := checkDeltaBase(ctx, k8sClient, namespace, req.Delta)
+	if err != nil {
+		if errors.Is(err, errInvalidDeltaBase) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if deltaWarning != "" {
+		warnings = append(warnings, deltaWarning)
+	}
+
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	configKey := types.NamespacedName{Name: "config", Namespace: resolveNamespace()}
+	if err := k8sClient.Get(ctx, configKey, operatorConfig); err != nil && !k8serrors.IsNotFound(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error reading OperatorConfig: %v", err)})
+		return
+	}
+
+	if operatorConfig.Spec.BuildAPI != nil {
+		user, _ := c.Get(userInfoKey)
+		userInfo, _ := user.(authnv1.UserInfo)
+		unlock := a.quotaLocks.lock(userInfo.Username)
+		defer unlock()
+		if err := checkBuildQuota(ctx, k8sClient, operatorConfig.Spec.BuildAPI.Quotas, userInfo); err != nil {
+			if errors.Is(err, errQuotaExceeded) {
+				c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	cfgName, err := createManifestConfigMap(ctx, k8sClient, namespace, &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -973,13 +1234,8 @@ func (a *APIServer) createBuild(c *gin.Context) {
 	}
 
 	serveExpiryHours := int32(24)
-	{
-		operatorConfig := &automotivev1alpha1.OperatorConfig{}
-		if err := k8sClient.Get(ctx, types.NamespacedName{Name: "config", Namespace: namespace}, operatorConfig); err == nil {
-			if operatorConfig.Spec.OSBuilds != nil && operatorConfig.Spec.OSBuilds.ServeExpiryHours > 0 {
-				serveExpiryHours = operatorConfig.Spec.OSBuilds.ServeExpiryHours
-			}
-		}
+	if operatorConfig.Spec.OSBuilds != nil && operatorConfig.Spec.OSBuilds.ServeExpiryHours > 0 {
+		serveExpiryHours = operatorConfig.Spec.OSBuilds.ServeExpiryHours
 	}
 
 	envSecretRef, pushSecretName, err := setupBuildSecrets(ctx, k8sClient, namespace, &req)
@@ -988,7 +1244,7 @@ func (a *APIServer) createBuild(c *gin.Context) {
 		return
 	}
 
-	publishers := buildPublishersConfig(req.PushRepository, pushSecretName)
+	publishers := buildPublishersConfig(req.PushRepository, pushSecretName, req.S3Upload)
 
 	imageBuild := &automotivev1alpha1.ImageBuild{
 		ObjectMeta: metav1.ObjectMeta{
@@ -996,7 +1252,8 @@ func (a *APIServer) createBuild(c *gin.Context) {
 			Namespace: namespace,
 			Labels:    labels,
 			Annotations: map[string]string{
-				"automotive.sdv.cloud.redhat.com/requested-by": requestedBy,
+				requestedByAnnotation:                      requestedBy,
+				automotivev1alpha1.CorrelationIDAnnotation: c.GetString("reqID"),
 			},
 		},
 		Spec: automotivev1alpha1.ImageBuildSpec{
@@ -1020,6 +1277,8 @@ func (a *APIServer) createBuild(c *gin.Context) {
 			ExportOCI:              req.ExportOCI,
 			BuilderImage:           req.BuilderImage,
 			ContainerRef:           req.ContainerRef,
+			Delta:                  buildDeltaSpec(req.Delta),
+			PublishToCatalog:       buildCatalogPublishSpec(req.PublishToCatalog),
 		},
 	}
 	if err := k8sClient.Create(ctx, imageBuild); err != nil {
@@ -1060,11 +1319,12 @@ func (a *APIServer) createBuild(c *gin.Context) {
 		Phase:       "Building",
 		Message:     "Build triggered",
 		RequestedBy: requestedBy,
+		Warnings:    warnings,
 	})
 }
 
 func listBuilds(c *gin.Context) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 
 	k8sClient, err := getClientFromRequest(c)
 	if err != nil {
@@ -1092,7 +1352,7 @@ func listBuilds(c *gin.Context) {
 			Name:           b.Name,
 			Phase:          b.Status.Phase,
 			Message:        b.Status.Message,
-			RequestedBy:    b.Annotations["automotive.sdv.cloud.redhat.com/requested-by"],
+			RequestedBy:    b.Annotations[requestedByAnnotation],
 			CreatedAt:      b.CreationTimestamp.Format(time.RFC3339),
 			StartTime:      startStr,
 			CompletionTime: compStr,
@@ -1102,7 +1362,7 @@ func listBuilds(c *gin.Context) {
 }
 
 func getBuild(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	k8sClient, err := getClientFromRequest(c)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("k8s client error: %v", err)})
@@ -1124,55 +1384,53 @@ func getBuild(c *gin.Context, name string) {
 	var jumpstarterInfo *JumpstarterInfo
 	if build.Status.Phase == "Completed" {
 		operatorConfig := &automotivev1alpha1.OperatorConfig{}
-		if err := k8sClient.Get(ctx, types.NamespacedName{Name: "config", Namespace: namespace}, operatorConfig); err == nil {
+		configKey := types.NamespacedName{Name: "config", Namespace: resolveNamespace()}
+		if err := k8sClient.Get(ctx, configKey, operatorConfig); err == nil {
 			if operatorConfig.Status.JumpstarterAvailable {
 				jumpstarterInfo = &JumpstarterInfo{Available: true}
 				if operatorConfig.Spec.Jumpstarter != nil {
 					if mapping, ok := operatorConfig.Spec.Jumpstarter.TargetMappings[build.Spec.Target]; ok {
 						jumpstarterInfo.ExporterSelector = mapping.Selector
-						flashCmd := mapping.FlashCmd
-						// Replace placeholders in flash command
-						if flashCmd != "" {
-							imageURI := build.Spec.ExportOCI
-							if imageURI == "" {
-								imageURI = build.Status.ArtifactURL
-							}
-							flashCmd = strings.ReplaceAll(flashCmd, "{image_uri}", imageURI)
-							flashCmd = strings.ReplaceAll(flashCmd, "{artifact_url}", build.Status.ArtifactURL)
+						if mapping.FlashCmd != "" {
+							jumpstarterInfo.FlashCmd = automotivev1alpha1.RenderFlashCmd(
+								mapping.FlashCmd, build.FlashImageRef(), build.Status.ArtifactURL)
 						}
-						jumpstarterInfo.FlashCmd = flashCmd
 					}
 				}
 			}
 		}
 	}
 
-	writeJSON(c, http.StatusOK, BuildResponse{
+	resp := newBuildResponse(build)
+	resp.Jumpstarter = jumpstarterInfo
+	writeJSON(c, http.StatusOK, resp)
+}
+
+// newBuildResponse describes the state of a build, without Jumpstarter details
+func newBuildResponse(build *automotivev1alpha1.ImageBuild) BuildResponse {
+	resp := BuildResponse{
 		Name:             build.Name,
 		Phase:            build.Status.Phase,
 		Message:          build.Status.Message,
-		RequestedBy:      build.Annotations["automotive.sdv.cloud.redhat.com/requested-by"],
+		RequestedBy:      build.Annotations[requestedByAnnotation],
 		ArtifactURL:      build.Status.ArtifactURL,
 		ArtifactFileName: strings.TrimSpace(build.Status.ArtifactFileName),
-		StartTime: func() string {
-			if build.Status.StartTime != nil {
-				return build.Status.StartTime.Format(time.RFC3339)
-			}
-			return ""
-		}(),
-		CompletionTime: func() string {
-			if build.Status.CompletionTime != nil {
-				return build.Status.CompletionTime.Format(time.RFC3339)
-			}
-			return ""
-		}(),
-		Jumpstarter: jumpstarterInfo,
-	})
+		ArtifactSHA256:   build.Status.ArtifactSHA256,
+		DeltaFileName:    build.Status.DeltaFileName,
+		CatalogImageName: build.Status.CatalogImageName,
+	}
+	if build.Status.StartTime != nil {
+		resp.StartTime = build.Status.StartTime.Format(time.RFC3339)
+	}
+	if build.Status.CompletionTime != nil {
+		resp.CompletionTime = build.Status.CompletionTime.Format(time.RFC3339)
+	}
+	return resp
 }
 
 // getBuildTemplate returns a BuildRequest-like struct representing the inputs that produced a given build
 func getBuildTemplate(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	k8sClient, err := getClientFromRequest(c)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("k8s client error: %v", err)})
@@ -1251,7 +1509,7 @@ func getBuildTemplate(c *gin.Context, name string) {
 }
 
 func (a *APIServer) uploadFiles(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 
 	k8sClient, err := getClientFromRequest(c)
 	if err != nil {
@@ -1403,7 +1661,7 @@ func (a *APIServer) uploadFiles(c *gin.Context, name string) {
 }
 
 func (a *APIServer) listArtifacts(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	ctx := c.Request.Context()
 
 	k8sClient, err := getClientFromRequest(c)
@@ -1464,6 +1722,13 @@ func (a *APIServer) listArtifacts(c *gin.Context, name string) {
 		return
 	}
 
+	// A delta generated against a base image is listed next to the parts
+	deltaFileName := strings.TrimSpace(build.Status.DeltaFileName)
+	deltaPath := ""
+	if deltaFileName != "" && safeFilename(deltaFileName) {
+		deltaPath = "/workspace/shared/" + path.Base(deltaFileName)
+	}
+
 	// Use safe command construction without shell interpolation
 	partsDir := "/workspace/shared/" + artifactFileName + "-parts"
 	listReq := clientset.CoreV1().RESTClient().Post().
@@ -1476,10 +1741,13 @@ func (a *APIServer) listArtifacts(c *gin.Context, name string) {
 			Command: []string{
 				"sh", "-c",
 				"set -e; " +
-					"if [ ! -d \"$1\" ]; then echo MISSING; exit 0; fi; " +
+					"if [ -d \"$1\" ]; then " +
 					"for f in \"$1\"/*; do [ -f \"$f\" ] || continue; " +
-					"n=$(basename \"$f\"); s=$(wc -c < \"$f\"); printf '%s:%s\\n' \"$n\" \"$s\"; done",
-				"--", partsDir,
+					"n=$(basename \"$f\"); s=$(wc -c < \"$f\"); printf '%s:%s\\n' \"$n\" \"$s\"; done; " +
+					"else echo MISSING; fi; " +
+					"if [ -n \"$2\" ] && [ -f \"$2\" ]; then " +
+					"n=$(basename \"$2\"); s=$(wc -c < \"$2\"); printf '%s:%s\\n' \"$n\" \"$s\"; fi",
+				"--", partsDir, deltaPath,
 			},
 			Stdout: true,
 			Stderr: true,
@@ -1504,6 +1772,8 @@ func (a *APIServer) listArtifacts(c *gin.Context, name string) {
 	type item struct {
 		Name      string `json:"name"`
 		SizeBytes string `json:"sizeBytes"`
+		Type      string `json:"type,omitempty"`
+		SHA256    string `json:"sha256,omitempty"`
 	}
 	items := make([]item, 0, len(lines))
 	for _, ln := range lines {
@@ -1511,13 +1781,18 @@ func (a *APIServer) listArtifacts(c *gin.Context, name string) {
 		if len(p) != 2 {
 			continue
 		}
-		items = append(items, item{Name: p[0], SizeBytes: strings.TrimSpace(p[1])})
+		it := item{Name: p[0], SizeBytes: strings.TrimSpace(p[1])}
+		if deltaPath != "" && it.Name == path.Base(deltaPath) {
+			it.Type = artifactTypeDelta
+			it.SHA256 = build.Status.DeltaSHA256
+		}
+		items = append(items, it)
 	}
 	writeJSON(c, http.StatusOK, map[string]any{"items": items})
 }
 
 func (a *APIServer) streamArtifactPart(c *gin.Context, name, file string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	ctx := c.Request.Context()
 
 	// Validate file parameter for security - prevent command injection
@@ -1618,37 +1893,25 @@ func (a *APIServer) streamArtifactPart(c *gin.Context, name, file string) {
 		return
 	}
 
-	streamReq := clientset.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Name(artifactPod.Name).
-		Namespace(namespace).
-		SubResource("exec").
-		VersionedParams(&corev1.PodExecOptions{
-			Container: "fileserver",
-			Command:   []string{"cat", gzPath},
-			Stdout:    true,
-			Stderr:    true,
-		}, kscheme.ParameterCodec)
-	streamExec, err := remotecommand.NewSPDYExecutor(restCfg, http.MethodPost, streamReq.URL())
+	size, err := strconv.ParseInt(sz, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("executor (stream): %v", err)})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid artifact size"})
 		return
 	}
 
 	c.Writer.Header().Set("Content-Type", "application/gzip")
 	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", file))
-	c.Writer.Header().Set("Content-Length", sz)
 	c.Writer.Header().Set("X-AIB-Artifact-Type", "file")
 	c.Writer.Header().Set("X-AIB-Compression", "gzip")
-	if f, ok := c.Writer.(http.Flusher); ok {
-		f.Flush()
-	}
 
-	_ = streamExec.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: c.Writer, Stderr: io.Discard})
+	servePodFile(
+		c, restCfg, clientset.CoreV1().RESTClient(),
+		namespace, artifactPod.Name, gzPath, size, artifactETag(build, file, size),
+	)
 }
 
 func (a *APIServer) streamDefaultArtifact(c *gin.Context, name string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	ctx := c.Request.Context()
 
 	k8sClient, err := getClientFromRequest(c)
@@ -1672,6 +1935,12 @@ func (a *APIServer) streamDefaultArtifact(c *gin.Context, name string) {
 		return
 	}
 
+	// Artifacts uploaded to object storage do not need the artifact pod
+	if key := s3ArtifactKey(build); key != "" {
+		a.serveS3Object(c, k8sClient, build, key)
+		return
+	}
+
 	artifactFileName := strings.TrimSpace(build.Status.ArtifactFileName)
 	if artifactFileName == "" {
 		var ext string
@@ -1794,44 +2063,32 @@ func (a *APIServer) streamDefaultArtifact(c *gin.Context, name string) {
 		contentType = "application/octet-stream"
 	}
 
+	size, err := strconv.ParseInt(sz, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid artifact size"})
+		return
+	}
+
 	// Set response headers
 	c.Writer.Header().Set("Content-Type", contentType)
 	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", artifactFileName))
-	c.Writer.Header().Set("Content-Length", sz)
 	c.Writer.Header().Set("X-AIB-Artifact-Type", artifactType)
 	if build.Spec.Compression != "" {
 		c.Writer.Header().Set("X-AIB-Compression", build.Spec.Compression)
 	}
-
-	if f, ok := c.Writer.(http.Flusher); ok {
-		f.Flush()
-	}
-
-	// Stream the file content
-	streamReq := clientset.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Name(artifactPod.Name).
-		Namespace(namespace).
-		SubResource("exec").
-		VersionedParams(&corev1.PodExecOptions{
-			Container: "fileserver",
-			Command:   []string{"cat", podPath},
-			Stdout:    true,
-			Stderr:    true,
-		}, kscheme.ParameterCodec)
-
-	streamExec, err := remotecommand.NewSPDYExecutor(restCfg, http.MethodPost, streamReq.URL())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("executor (stream): %v", err)})
-		return
+	if build.Status.ArtifactSHA256 != "" && artifactFileName == strings.TrimSpace(build.Status.ArtifactFileName) {
+		c.Writer.Header().Set(headerArtifactSHA256, build.Status.ArtifactSHA256)
 	}
 
-	_ = streamExec.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: c.Writer, Stderr: io.Discard})
+	servePodFile(
+		c, restCfg, clientset.CoreV1().RESTClient(),
+		namespace, artifactPod.Name, podPath, size, artifactETag(build, artifactFileName, size),
+	)
 }
 
 // streamArtifactByFilename streams the specified artifact file from the artifact pod to the client over HTTP
 func (a *APIServer) streamArtifactByFilename(c *gin.Context, name, filename string) {
-	namespace := resolveNamespace()
+	namespace := requestNamespace(c)
 	ctx := c.Request.Context()
 
 	// Validate filename parameter for security - prevent command injection
@@ -1861,6 +2118,11 @@ func (a *APIServer) streamArtifactByFilename(c *gin.Context, name, filename stri
 		return
 	}
 
+	if key, ok := s3ObjectKeyFor(build, path.Base(filename)); ok {
+		a.serveS3Object(c, k8sClient, build, key)
+		return
+	}
+
 	// Only allow the exact final artifact file name or files from the -parts directory
 	expected := strings.TrimSpace(build.Status.ArtifactFileName)
 
@@ -1871,7 +2133,9 @@ func (a *APIServer) streamArtifactByFilename(c *gin.Context, name, filename stri
 	}
 
 	base := path.Base(filename)
-	allowed := base == expected
+	delta := strings.TrimSpace(build.Status.DeltaFileName)
+	isDelta := delta != "" && base == delta
+	allowed := base == expected || isDelta
 
 	if !allowed {
 		// Check if it's a part file (from -parts directory)
@@ -1959,32 +2223,26 @@ func (a *APIServer) streamArtifactByFilename(c *gin.Context, name, filename stri
 	}
 
 	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", base))
-	c.Writer.Header().Set("Content-Length", sz)
-
-	if f, ok := c.Writer.(http.Flusher); ok {
-		f.Flush()
+	if build.Status.ArtifactSHA256 != "" && base == expected {
+		c.Writer.Header().Set(headerArtifactSHA256, build.Status.ArtifactSHA256)
+	}
+	if isDelta {
+		c.Writer.Header().Set("X-AIB-Artifact-Type", artifactTypeDelta)
+		if build.Status.DeltaSHA256 != "" {
+			c.Writer.Header().Set(headerArtifactSHA256, build.Status.DeltaSHA256)
+		}
 	}
 
-	// Stream the file content
-	streamReq := clientset.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Name(artifactPod.Name).
-		Namespace(namespace).
-		SubResource("exec").
-		VersionedParams(&corev1.PodExecOptions{
-			Container: "fileserver",
-			Command:   []string{"cat", podPath},
-			Stdout:    true,
-			Stderr:    true,
-		}, kscheme.ParameterCodec)
-
-	streamExec, err := remotecommand.NewSPDYExecutor(restCfg, http.MethodPost, streamReq.URL())
+	size, err := strconv.ParseInt(sz, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("executor (stream): %v", err)})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid artifact size"})
 		return
 	}
 
-	_ = streamExec.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: c.Writer, Stderr: io.Discard})
+	servePodFile(
+		c, restCfg, clientset.CoreV1().RESTClient(),
+		namespace, artifactPod.Name, podPath, size, artifactETag(build, base, size),
+	)
 }
 
 func copyFileToPod(config *rest.Config, namespace, podName, containerName, localPath, podPath string) error {
@@ -2110,61 +2368,65 @@ func resolveNamespace() string {
 }
 
 func getRESTConfigFromRequest(_ *gin.Context) (*rest.Config, error) {
-	var cfg *rest.Config
-	var err error
-	cfg, err = rest.InClusterConfig()
+	kc, err := loadKubeClients()
 	if err != nil {
-		kubeconfig := os.Getenv("KUBECONFIG")
-		cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
-		if err != nil {
-			return nil, fmt.Errorf("failed to build kube config: %w", err)
-		}
+		return nil, err
 	}
-	cfgCopy := rest.CopyConfig(cfg)
+	cfgCopy := rest.CopyConfig(kc.config)
 	cfgCopy.Timeout = 30 * time.Minute
 	return cfgCopy, nil
 }
 
-func getClientFromRequest(c *gin.Context) (client.Client, error) {
-	cfg, err := getRESTConfigFromRequest(c)
+// getClientFromRequest returns the shared client, which serves ImageBuild and pod reads from the
+// informer cache
+func getClientFromRequest(_ *gin.Context) (client.Client, error) {
+	kc, err := loadKubeClients()
 	if err != nil {
 		return nil, err
 	}
-
-	scheme := runtime.NewScheme()
-	if err := automotivev1alpha1.AddToScheme(scheme); err != nil {
-		return nil, fmt.Errorf("failed to add automotive scheme: %w", err)
-	}
-	if err := corev1.AddToScheme(scheme); err != nil {
-		return nil, fmt.Errorf("failed to add core scheme: %w", err)
-	}
-
-	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create k8s client: %w", err)
-	}
-	return k8sClient, nil
+	return kc.client, nil
 }
 
+// isAuthenticated authenticates the bearer token of the request: an API token, an ID token of the
+// configured OpenID provider, or a cluster token reviewed with a TokenReview. Recent reviews of the
+// same token are reused.
 func (a *APIServer) isAuthenticated(c *gin.Context) bool {
 	token := extractBearerToken(c)
 	if token == "" {
 		return false
 	}
-
-	cfg, err := getRESTConfigFromRequest(c)
-	if err != nil {
-		return false
+	if strings.HasPrefix(token, apiTokenPrefix) {
+		return a.authenticateAPIToken(c, token)
+	}
+	if a.oidc != nil {
+		user, err := a.oidc.authenticate(c.Request.Context(), token)
+		if err == nil {
+			c.Set(userInfoKey, user)
+			return true
+		}
+		if !errors.Is(err, errNotOIDCToken) {
+			a.log.Info("OIDC token rejected", "reason", err.Error(), "reqID", c.GetString("reqID"))
+			return false
+		}
+	}
+	if user, ok := a.tokenReviews.get(token); ok {
+		c.Set(userInfoKey, user)
+		return true
 	}
 
-	clientset, err := kubernetes.NewForConfig(cfg)
+	kc, err := loadKubeClients()
 	if err != nil {
 		return false
 	}
 
 	tr := &authnv1.TokenReview{Spec: authnv1.TokenReviewSpec{Token: token}}
-	res, err := clientset.AuthenticationV1().TokenReviews().Create(c.Request.Context(), tr, metav1.CreateOptions{})
-	return err == nil && res.Status.Authenticated
+	res, err := kc.clientset.AuthenticationV1().TokenReviews().Create(c.Request.Context(), tr, metav1.CreateOptions{})
+	if err != nil || !res.Status.Authenticated {
+		return false
+	}
+	a.tokenReviews.add(token, res.Status.User)
+	c.Set(userInfoKey, res.Status.User)
+	return true
 }
 
 // extractBearerToken extracts the bearer token from the request
@@ -2183,16 +2445,27 @@ func findReadyArtifactPod(
 	k8sClient client.Client,
 	namespace, buildName string,
 	deadline time.Time,
+) (*corev1.Pod, error) {
+	labels := client.MatchingLabels{
+		"app.kubernetes.io/name":                          "artifact-pod",
+		"automotive.sdv.cloud.redhat.com/imagebuild-name": buildName,
+	}
+	return findReadyPod(ctx, k8sClient, namespace, labels, "artifact pod", deadline)
+}
+
+// findReadyPod waits until a pod with the given labels runs with a ready fileserver container
+func findReadyPod(
+	ctx context.Context,
+	k8sClient client.Client,
+	namespace string,
+	labels client.MatchingLabels,
+	description string,
+	deadline time.Time,
 ) (*corev1.Pod, error) {
 	for {
 		podList := &corev1.PodList{}
-		if err := k8sClient.List(ctx, podList,
-			client.InNamespace(namespace),
-			client.MatchingLabels{
-				"app.kubernetes.io/name":                          "artifact-pod",
-				"automotive.sdv.cloud.redhat.com/imagebuild-name": buildName,
-			}); err != nil {
-			return nil, fmt.Errorf("error listing artifact pods: %w", err)
+		if err := k8sClient.List(ctx, podList, client.InNamespace(namespace), labels); err != nil {
+			return nil, fmt.Errorf("error listing %ss: %w", description, err)
 		}
 
 		for i := range podList.Items {
@@ -2207,33 +2480,26 @@ func findReadyArtifactPod(
 		}
 
 		if time.Now().After(deadline) {
-			return nil, fmt.Errorf("artifact pod not ready")
+			return nil, fmt.Errorf("%s not ready", description)
 		}
 		time.Sleep(2 * time.Second)
 	}
 }
 
+// resolveRequester returns the name of the user authenticated by authMiddleware
 func resolveRequester(c *gin.Context) string {
-	token := extractBearerToken(c)
-	if token == "" {
-		return statusUnknown
-	}
-
-	cfg, err := getRESTConfigFromRequest(c)
-	if err != nil {
-		return statusUnknown
-	}
-
-	clientset, err := kubernetes.NewForConfig(cfg)
-	if err != nil {
-		return statusUnknown
+	if username := authenticatedUser(c); username != "" {
+		return username
 	}
+	return statusUnknown
+}
 
-	tr := &authnv1.TokenReview{Spec: authnv1.TokenReviewSpec{Token: token}}
-	res, err := clientset.AuthenticationV1().TokenReviews().Create(c.Request.Context(), tr, metav1.CreateOptions{})
-	if err != nil || !res.Status.Authenticated || res.Status.User.Username == "" {
-		return statusUnknown
+// authenticatedUser returns the name of the user authenticated by authMiddleware, whether by API
+// token, OIDC or TokenReview, or an empty string if the request carries no identity
+func authenticatedUser(c *gin.Context) string {
+	user, _ := c.Get(userInfoKey)
+	if userInfo, ok := user.(authnv1.UserInfo); ok {
+		return userInfo.Username
 	}
-
-	return res.Status.User.Username
+	return ""
 }
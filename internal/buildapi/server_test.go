@@ -12,6 +12,8 @@ import (
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
 	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
 )
 
 var _ = Describe("APIServer", func() {
@@ -79,6 +81,9 @@ var _ = Describe("APIServer", func() {
 			{"GET", "/v1/builds/test-build/artifacts"},
 			{"GET", "/v1/builds/test-build/template"},
 			{"POST", "/v1/builds/test-build/uploads"},
+			{"POST", "/v1/builds/test-build/flash"},
+			{"GET", "/v1/flash/test-build-flash-x7k2p"},
+			{"GET", "/v1/flash/test-build-flash-x7k2p/logs"},
 		}
 
 		It("should require authentication for all builds endpoints", func() {
@@ -162,3 +167,30 @@ var _ = Describe("APIServer Performance", func() {
 		Expect(w.Code).To(Equal(http.StatusOK))
 	})
 })
+
+var _ = Describe("Delta base", func() {
+	catalogImage := func(state automotivev1alpha1.LifecycleState) *automotivev1alpha1.CatalogImage {
+		image := &automotivev1alpha1.CatalogImage{}
+		image.Name = "autosd"
+		image.Spec.Lifecycle = &automotivev1alpha1.CatalogImageLifecycle{State: state, ReplacedBy: "autosd-2"}
+		return image
+	}
+
+	It("should accept active catalog images without a warning", func() {
+		warning, err := deltaBaseWarning(catalogImage(automotivev1alpha1.LifecycleActive))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warning).To(BeEmpty())
+	})
+
+	It("should warn about deprecated catalog images", func() {
+		warning, err := deltaBaseWarning(catalogImage(automotivev1alpha1.LifecycleDeprecated))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warning).To(Equal("catalog image autosd is deprecated; use autosd-2 instead"))
+	})
+
+	It("should refuse end-of-life catalog images", func() {
+		_, err := deltaBaseWarning(catalogImage(automotivev1alpha1.LifecycleEOL))
+		Expect(err).To(MatchError(errInvalidDeltaBase))
+		Expect(err.Error()).To(ContainSubstring("use autosd-2 instead"))
+	})
+})
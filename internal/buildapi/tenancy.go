@@ -0,0 +1,196 @@
+package buildapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	authnv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	// userInfoKey holds the authenticated caller's identity in the gin context
+	userInfoKey = "userInfo"
+	// namespaceKey holds the namespace a request operates in
+	namespaceKey = "namespace"
+	// tenantOwnerBinding binds the tenant role to the owner of a personal namespace
+	tenantOwnerBinding = "ado-tenant-owner"
+)
+
+var (
+	errTenancyDisabled = errors.New("tenancy is not enabled, builds run in the build API namespace")
+	errNotTenant       = errors.New("not a tenant of the requested namespace")
+	errNoTenant        = errors.New("no tenant namespace is available to the caller")
+)
+
+// tenancyMiddleware resolves the namespace an authenticated request operates in and, for requests
+// creating builds, makes sure the caller's tenant namespace exists
+func (a *APIServer) tenancyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, err := a.resolveTenantNamespace(c)
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, errTenancyDisabled):
+				status = http.StatusBadRequest
+			case errors.Is(err, errNotTenant), errors.Is(err, errNoTenant):
+				status = http.StatusForbidden
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		c.Set(namespaceKey, namespace)
+		c.Next()
+	}
+}
+
+// requestNamespace returns the namespace resolved for the request by tenancyMiddleware
+func requestNamespace(c *gin.Context) string {
+	if namespace := c.GetString(namespaceKey); namespace != "" {
+		return namespace
+	}
+	return resolveNamespace()
+}
+
+// resolveTenantNamespace picks the namespace of a request. Without tenancy every request uses the
+// build API namespace; with tenancy the caller may choose any of its tenant namespaces with
+// ?namespace= and otherwise lands in its default one.
+func (a *APIServer) resolveTenantNamespace(c *gin.Context) (string, error) {
+	requested := strings.TrimSpace(c.Query("namespace"))
+
+	k8sClient, err := getClientFromRequest(c)
+	if err != nil {
+		return "", fmt.Errorf("k8s client error: %w", err)
+	}
+
+	ctx := c.Request.Context()
+	operatorConfig := &automotivev1alpha1.OperatorConfig{}
+	configKey := types.NamespacedName{Name: "config", Namespace: resolveNamespace()}
+	if err := k8sClient.Get(ctx, configKey, operatorConfig); err != nil && !k8serrors.IsNotFound(err) {
+		return "", fmt.Errorf("error reading OperatorConfig: %w", err)
+	}
+
+	tenancy := operatorConfig.Spec.Tenancy
+	if tenancy == nil || !tenancy.Enabled {
+		if requested != "" && requested != resolveNamespace() {
+			return "", errTenancyDisabled
+		}
+		return resolveNamespace(), nil
+	}
+
+	user, _ := c.Get(userInfoKey)
+	userInfo, _ := user.(authnv1.UserInfo)
+	namespace, err := selectTenantNamespace(tenancy, userInfo, requested)
+	if err != nil {
+		return "", err
+	}
+
+	if c.Request.Method == http.MethodPost {
+		// Team namespaces and their role bindings are managed by the OperatorConfig controller
+		owner := ""
+		if namespace == tenancy.UserNamespace(userInfo.Username) {
+			owner = userInfo.Username
+		}
+		if err := ensureTenantNamespace(ctx, k8sClient, namespace, owner); err != nil {
+			return "", err
+		}
+	}
+	return namespace, nil
+}
+
+// selectTenantNamespace returns the requested namespace if the user is a tenant of it, or the
+// user's default tenant namespace if none was requested
+func selectTenantNamespace(
+	tenancy *automotivev1alpha1.TenancyConfig,
+	user authnv1.UserInfo,
+	requested string,
+) (string, error) {
+	if user.Username == "" {
+		return "", errNoTenant
+	}
+	namespaces := tenancy.Namespaces(user.Username, user.Groups)
+	if len(namespaces) == 0 {
+		return "", errNoTenant
+	}
+	if requested == "" {
+		return namespaces[0], nil
+	}
+	if !slices.Contains(namespaces, requested) {
+		return "", fmt.Errorf("%w %q", errNotTenant, requested)
+	}
+	return requested, nil
+}
+
+// ensureTenantNamespace creates a tenant namespace on first use and binds the tenant role to the
+// owner of a personal namespace. The OperatorConfig controller provisions the build pipeline and
+// quota into the namespace once it is labeled as a tenant.
+func ensureTenantNamespace(ctx context.Context, k8sClient client.Client, name, owner string) error {
+	ns := &corev1.Namespace{}
+	err := k8sClient.Get(ctx, types.NamespacedName{Name: name}, ns)
+	if k8serrors.IsNotFound(err) {
+		ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{automotivev1alpha1.TenantNamespaceLabel: "true"},
+		}}
+		if owner != "" {
+			ns.Annotations = map[string]string{automotivev1alpha1.TenantOwnerAnnotation: owner}
+		}
+		err = k8sClient.Create(ctx, ns)
+		if k8serrors.IsAlreadyExists(err) {
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: name}, ns)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error ensuring tenant namespace: %w", err)
+	}
+	if owner == "" {
+		return nil
+	}
+	if err := checkNamespaceOwner(ns, owner); err != nil {
+		return err
+	}
+
+	binding := &rbacv1.RoleBinding{}
+	err = k8sClient.Get(ctx, types.NamespacedName{Name: tenantOwnerBinding, Namespace: name}, binding)
+	if !k8serrors.IsNotFound(err) {
+		return err
+	}
+	binding = &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: tenantOwnerBinding, Namespace: name},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     automotivev1alpha1.TenantClusterRole,
+		},
+		Subjects: []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: owner}},
+	}
+	if err := k8sClient.Create(ctx, binding); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("error binding tenant role: %w", err)
+	}
+	return nil
+}
+
+// checkNamespaceOwner makes sure a personal namespace is a tenant namespace created for owner, so
+// that the tenant role is never bound in other namespaces that happen to have the same name
+func checkNamespaceOwner(ns *corev1.Namespace, owner string) error {
+	if _, ok := ns.Labels[automotivev1alpha1.TenantNamespaceLabel]; !ok {
+		return fmt.Errorf("%w %q: not a tenant namespace", errNotTenant, ns.Name)
+	}
+	if ns.Annotations[automotivev1alpha1.TenantOwnerAnnotation] != owner {
+		return fmt.Errorf("%w %q: owned by another user", errNotTenant, ns.Name)
+	}
+	return nil
+}
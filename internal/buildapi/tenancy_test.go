@@ -0,0 +1,81 @@
+package buildapi
+
+import (
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	authnv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+var _ = Describe("Tenant namespaces", func() {
+	tenancy := &automotivev1alpha1.TenancyConfig{
+		Enabled: true,
+		Teams: []automotivev1alpha1.TenantTeam{
+			{Group: "platform", Namespace: "team-platform"},
+			{Group: "bsp", Namespace: "team-bsp"},
+		},
+	}
+
+	It("should derive personal namespaces as DNS labels", func() {
+		Expect(tenancy.UserNamespace("Jane.Doe@example.com")).To(MatchRegexp(`^ado-user-jane-doe-example-com-[0-9a-f]{8}$`))
+		Expect(tenancy.UserNamespace("system:serviceaccount:ci:builder")).
+			To(MatchRegexp(`^ado-user-system-serviceaccount-ci-builder-[0-9a-f]{8}$`))
+		Expect(len(tenancy.UserNamespace("a-very-long-user-name-that-does-not-fit-into-a-label@example.com"))).
+			To(BeNumerically("<=", 63))
+	})
+
+	It("should give user names reduced alike distinct namespaces", func() {
+		Expect(tenancy.UserNamespace("alice.smith")).NotTo(Equal(tenancy.UserNamespace("alice_smith")))
+		Expect(tenancy.UserNamespace("a-very-long-user-name-that-does-not-fit-into-a-label@example.com")).
+			NotTo(Equal(tenancy.UserNamespace("a-very-long-user-name-that-does-not-fit-into-a-label@example.org")))
+		Expect(tenancy.UserNamespace("alice.smith")).To(Equal(tenancy.UserNamespace("alice.smith")))
+	})
+
+	It("should default to the first team namespace, then the personal one", func() {
+		ns, err := selectTenantNamespace(tenancy, authnv1.UserInfo{Username: "jane", Groups: []string{"bsp", "platform"}}, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ns).To(Equal("team-platform"))
+
+		ns, err = selectTenantNamespace(tenancy, authnv1.UserInfo{Username: "joe"}, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ns).To(Equal(tenancy.UserNamespace("joe")))
+	})
+
+	It("should only allow namespaces the caller is a tenant of", func() {
+		user := authnv1.UserInfo{Username: "jane", Groups: []string{"bsp"}}
+
+		ns, err := selectTenantNamespace(tenancy, user, tenancy.UserNamespace("jane"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ns).To(Equal(tenancy.UserNamespace("jane")))
+
+		_, err = selectTenantNamespace(tenancy, user, "ado-user-jane")
+		Expect(err).To(MatchError(errNotTenant))
+
+		_, err = selectTenantNamespace(tenancy, user, "team-platform")
+		Expect(err).To(MatchError(errNotTenant))
+
+		_, err = selectTenantNamespace(tenancy, authnv1.UserInfo{}, "")
+		Expect(err).To(MatchError(errNoTenant))
+	})
+
+	It("should only bind the owner in personal namespaces created for them", func() {
+		personal := func(labels, annotations map[string]string) *corev1.Namespace {
+			return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name: tenancy.UserNamespace("jane"), Labels: labels, Annotations: annotations,
+			}}
+		}
+		tenant := map[string]string{automotivev1alpha1.TenantNamespaceLabel: "true"}
+
+		owned := personal(tenant, map[string]string{automotivev1alpha1.TenantOwnerAnnotation: "jane"})
+		Expect(checkNamespaceOwner(owned, "jane")).To(Succeed())
+		Expect(checkNamespaceOwner(owned, "joe")).To(MatchError(errNotTenant))
+
+		Expect(checkNamespaceOwner(personal(tenant, nil), "jane")).To(MatchError(errNotTenant))
+		Expect(checkNamespaceOwner(personal(nil, map[string]string{
+			automotivev1alpha1.TenantOwnerAnnotation: "jane",
+		}), "jane")).To(MatchError(errNotTenant))
+	})
+})
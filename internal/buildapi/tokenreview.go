@@ -0,0 +1,90 @@
+package buildapi
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+)
+
+const (
+	// tokenReviewTTL is how long an authenticated token is trusted without a new TokenReview
+	tokenReviewTTL = time.Minute
+	// maxCachedTokenReviews bounds the memory held by the token review cache
+	maxCachedTokenReviews = 10000
+)
+
+// tokenReviewCache remembers the users of recently authenticated bearer tokens. Entries are keyed
+// by the SHA-256 of the token so tokens are never held in memory in the clear. A nil cache caches
+// nothing.
+type tokenReviewCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[[sha256.Size]byte]tokenReviewEntry
+	now     func() time.Time
+}
+
+type tokenReviewEntry struct {
+	user    authnv1.UserInfo
+	expires time.Time
+}
+
+func newTokenReviewCache(ttl time.Duration, maxSize int) *tokenReviewCache {
+	return &tokenReviewCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[[sha256.Size]byte]tokenReviewEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the user a token was authenticated as, if the review has not expired yet
+func (t *tokenReviewCache) get(token string) (authnv1.UserInfo, bool) {
+	if t == nil {
+		return authnv1.UserInfo{}, false
+	}
+	key := sha256.Sum256([]byte(token))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry, ok := t.entries[key]
+	if ok && t.now().After(entry.expires) {
+		delete(t.entries, key)
+		ok = false
+	}
+	if !ok {
+		tokenReviewCacheTotal.WithLabelValues("miss").Inc()
+		return authnv1.UserInfo{}, false
+	}
+	tokenReviewCacheTotal.WithLabelValues("hit").Inc()
+	return entry.user, true
+}
+
+// add remembers the user of an authenticated token, evicting expired entries once the cache is full
+func (t *tokenReviewCache) add(token string, user authnv1.UserInfo) {
+	if t == nil {
+		return
+	}
+	key := sha256.Sum256([]byte(token))
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.entries) >= t.maxSize {
+		for k, entry := range t.entries {
+			if now.After(entry.expires) {
+				delete(t.entries, k)
+			}
+		}
+		// Still full of live entries: drop an arbitrary one rather than growing without bound
+		for k := range t.entries {
+			if len(t.entries) < t.maxSize {
+				break
+			}
+			delete(t.entries, k)
+		}
+	}
+	t.entries[key] = tokenReviewEntry{user: user, expires: now.Add(t.ttl)}
+}
@@ -0,0 +1,55 @@
+package buildapi
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	authnv1 "k8s.io/api/authentication/v1"
+)
+
+var _ = Describe("Token review cache", func() {
+	var (
+		cache *tokenReviewCache
+		now   time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		cache = newTokenReviewCache(time.Minute, 2)
+		cache.now = func() time.Time { return now }
+	})
+
+	It("should return cached users until the review expires", func() {
+		cache.add("token-a", authnv1.UserInfo{Username: "jane"})
+
+		user, ok := cache.get("token-a")
+		Expect(ok).To(BeTrue())
+		Expect(user.Username).To(Equal("jane"))
+
+		_, ok = cache.get("token-b")
+		Expect(ok).To(BeFalse())
+
+		now = now.Add(2 * time.Minute)
+		_, ok = cache.get("token-a")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should not grow beyond its maximum size", func() {
+		cache.add("token-a", authnv1.UserInfo{Username: "jane"})
+		cache.add("token-b", authnv1.UserInfo{Username: "joe"})
+		cache.add("token-c", authnv1.UserInfo{Username: "ann"})
+
+		Expect(cache.entries).To(HaveLen(2))
+		user, ok := cache.get("token-c")
+		Expect(ok).To(BeTrue())
+		Expect(user.Username).To(Equal("ann"))
+	})
+
+	It("should cache nothing when disabled", func() {
+		var disabled *tokenReviewCache
+		disabled.add("token-a", authnv1.UserInfo{Username: "jane"})
+		_, ok := disabled.get("token-a")
+		Expect(ok).To(BeFalse())
+	})
+})
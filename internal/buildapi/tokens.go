@@ -0,0 +1,360 @@
+package buildapi
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	// apiTokenPrefix distinguishes build API tokens from cluster bearer tokens
+	apiTokenPrefix = "ado_"
+	// apiTokenSecretPrefix prefixes the names of the Secrets holding API tokens
+	apiTokenSecretPrefix = "ado-api-token-"
+	// apiTokenLabel marks Secrets holding API tokens
+	apiTokenLabel = "automotive.sdv.cloud.redhat.com/api-token"
+	// apiTokenScopesKey holds the scopes of the API token a request authenticated with
+	apiTokenScopesKey = "apiTokenScopes"
+
+	defaultAPITokenDays = 30
+	maxAPITokenDays     = 90
+)
+
+// API token scopes. A token may only perform operations covered by its scopes, and only those its
+// owner's RBAC permits.
+const (
+	scopeBuildCreate    = "build:create"
+	scopeBuildRead      = "build:read"
+	scopeArtifactRead   = "artifact:read"
+	scopeCatalogRead    = "catalog:read"
+	scopeCatalogPublish = "catalog:publish"
+)
+
+var apiTokenScopes = []string{
+	scopeBuildCreate, scopeBuildRead, scopeArtifactRead, scopeCatalogRead, scopeCatalogPublish,
+}
+
+var errInvalidAPIToken = errors.New("invalid API token")
+
+// generateAPIToken returns a new token of the form ado_<id>_<secret>, along with its id and the
+// hash stored in place of the secret
+func generateAPIToken() (token, id, hash string, err error) {
+	idBytes := make([]byte, 8)
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", "", err
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+	id = hex.EncodeToString(idBytes)
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+	return apiTokenPrefix + id + "_" + secret, id, hashAPITokenSecret(secret), nil
+}
+
+// parseAPIToken splits a token into its id and secret
+func parseAPIToken(token string) (id, secret string, err error) {
+	rest, ok := strings.CutPrefix(token, apiTokenPrefix)
+	if !ok {
+		return "", "", errInvalidAPIToken
+	}
+	id, secret, ok = strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", "", errInvalidAPIToken
+	}
+	if _, err := hex.DecodeString(id); err != nil {
+		return "", "", errInvalidAPIToken
+	}
+	return id, secret, nil
+}
+
+func hashAPITokenSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// requiredScope returns the token scope covering an operation, or "" if no scope covers it
+func requiredScope(attrs authzv1.ResourceAttributes) string {
+	switch attrs.Resource {
+	case imageBuildsResource:
+		switch {
+		case attrs.Subresource == "artifacts":
+			return scopeArtifactRead
+		case attrs.Verb == "create":
+			return scopeBuildCreate
+		}
+		return scopeBuildRead
+	case flashJobsResource:
+		if attrs.Verb == "create" {
+			return scopeBuildCreate
+		}
+		return scopeBuildRead
+	case "images":
+		return scopeArtifactRead
+	case "catalogimages":
+		// Requesting and approving a promotion both promote the image, so both need catalog:publish
+		if attrs.Subresource == "promote" {
+			return scopeCatalogPublish
+		}
+		if attrs.Verb == "get" || attrs.Verb == "list" || attrs.Verb == "watch" {
+			return scopeCatalogRead
+		}
+		return scopeCatalogPublish
+	}
+	return ""
+}
+
+// tokenScopesAllow reports whether the API token a request authenticated with, if any, covers an
+// operation
+func tokenScopesAllow(c *gin.Context, attrs authzv1.ResourceAttributes) bool {
+	scopes, ok := c.Get(apiTokenScopesKey)
+	if !ok {
+		return true
+	}
+	granted, _ := scopes.([]string)
+	scope := requiredScope(attrs)
+	return scope != "" && slices.Contains(granted, scope)
+}
+
+// authenticateAPIToken authenticates a request bearing an API token as the token's owner. Only the
+// owner's user name is used: group memberships may change during the token's lifetime, so access
+// granted to groups, including team namespaces, is not available to API tokens.
+func (a *APIServer) authenticateAPIToken(c *gin.Context, token string) bool {
+	id, secret, err := parseAPIToken(token)
+	if err != nil {
+		return false
+	}
+	k8sClient, err := getClientFromRequest(c)
+	if err != nil {
+		return false
+	}
+
+	s := &corev1.Secret{}
+	key := types.NamespacedName{Name: apiTokenSecretPrefix + id, Namespace: resolveNamespace()}
+	if err := k8sClient.Get(c.Request.Context(), key, s); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			a.log.Error(err, "failed to read API token", "id", id)
+		}
+		return false
+	}
+	if s.Labels[apiTokenLabel] != "true" {
+		return false
+	}
+	if subtle.ConstantTimeCompare(s.Data["tokenHash"], []byte(hashAPITokenSecret(secret))) != 1 {
+		return false
+	}
+
+	info := apiTokenFromSecret(s)
+	if expires, err := time.Parse(time.RFC3339, info.ExpiresAt); err != nil || time.Now().After(expires) {
+		return false
+	}
+
+	c.Set(userInfoKey, authnv1.UserInfo{Username: info.Owner})
+	c.Set(apiTokenScopesKey, info.Scopes)
+	c.Set(apiTokenIDKey, info.ID)
+	return true
+}
+
+// apiTokenFromSecret describes the API token stored in a Secret, without its secret
+func apiTokenFromSecret(s *corev1.Secret) APITokenResponse {
+	token := APITokenResponse{
+		ID:          strings.TrimPrefix(s.Name, apiTokenSecretPrefix),
+		Description: string(s.Data["description"]),
+		Owner:       string(s.Data["owner"]),
+		CreatedAt:   s.CreationTimestamp.Format(time.RFC3339),
+		ExpiresAt:   string(s.Data["expiresAt"]),
+	}
+	if scopes := string(s.Data["scopes"]); scopes != "" {
+		token.Scopes = strings.Split(scopes, ",")
+	}
+	return token
+}
+
+// callerIdentity returns the user of a request authenticated with a cluster token. API tokens
+// cannot manage API tokens.
+func callerIdentity(c *gin.Context) (authnv1.UserInfo, bool) {
+	if _, ok := c.Get(apiTokenScopesKey); ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API tokens cannot be used to manage API tokens"})
+		return authnv1.UserInfo{}, false
+	}
+	user, _ := c.Get(userInfoKey)
+	userInfo, _ := user.(authnv1.UserInfo)
+	if userInfo.Username == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return authnv1.UserInfo{}, false
+	}
+	return userInfo, true
+}
+
+func validateAPITokenRequest(req *APITokenRequest) error {
+	if len(req.Scopes) == 0 {
+		return fmt.Errorf("at least one scope is required (%s)", strings.Join(apiTokenScopes, ", "))
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(apiTokenScopes, scope) {
+			return fmt.Errorf("unknown scope %q (valid: %s)", scope, strings.Join(apiTokenScopes, ", "))
+		}
+	}
+	if req.ExpiresInDays == 0 {
+		req.ExpiresInDays = defaultAPITokenDays
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > maxAPITokenDays {
+		return fmt.Errorf("expiresInDays must be between 1 and %d", maxAPITokenDays)
+	}
+	if len(req.Description) > 256 {
+		return fmt.Errorf("description too long (max 256 characters)")
+	}
+	return nil
+}
+
+func (a *APIServer) handleCreateToken(c *gin.Context) {
+	user, ok := callerIdentity(c)
+	if !ok {
+		return
+	}
+
+	var req APITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON request"})
+		return
+	}
+	if err := validateAPITokenRequest(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	slices.Sort(req.Scopes)
+	req.Scopes = slices.Compact(req.Scopes)
+
+	k8sClient, err := getClientFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("k8s client error: %v", err)})
+		return
+	}
+
+	token, id, hash, err := generateAPIToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
+		return
+	}
+	c.Set(auditNameKey, id)
+	expiresAt := time.Now().Add(time.Duration(req.ExpiresInDays) * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      apiTokenSecretPrefix + id,
+			Namespace: resolveNamespace(),
+			Labels: map[string]string{
+				apiTokenLabel:                  "true",
+				"app.kubernetes.io/managed-by": buildAPIName,
+			},
+			Annotations: map[string]string{
+				automotivev1alpha1.CorrelationIDAnnotation: c.GetString("reqID"),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"tokenHash":   []byte(hash),
+			"owner":       []byte(user.Username),
+			"scopes":      []byte(strings.Join(req.Scopes, ",")),
+			"description": []byte(req.Description),
+			"expiresAt":   []byte(expiresAt),
+		},
+	}
+	if err := k8sClient.Create(c.Request.Context(), s); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to store token: %v", err)})
+		return
+	}
+
+	a.log.Info("API token created", "id", id, "owner", user.Username, "scopes", req.Scopes, "reqID", c.GetString("reqID"))
+	resp := apiTokenFromSecret(s)
+	resp.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	resp.Token = token
+	writeJSON(c, http.StatusCreated, resp)
+}
+
+func (a *APIServer) handleListTokens(c *gin.Context) {
+	user, ok := callerIdentity(c)
+	if !ok {
+		return
+	}
+	k8sClient, err := getClientFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("k8s client error: %v", err)})
+		return
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := k8sClient.List(c.Request.Context(), secrets,
+		client.InNamespace(resolveNamespace()), client.MatchingLabels{apiTokenLabel: "true"},
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error listing tokens: %v", err)})
+		return
+	}
+
+	tokens := make([]APITokenResponse, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		if token := apiTokenFromSecret(&secrets.Items[i]); token.Owner == user.Username {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt < tokens[j].CreatedAt })
+	writeJSON(c, http.StatusOK, tokens)
+}
+
+func (a *APIServer) handleRevokeToken(c *gin.Context) {
+	user, ok := callerIdentity(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	if _, err := hex.DecodeString(id); err != nil || id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token id"})
+		return
+	}
+	k8sClient, err := getClientFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("k8s client error: %v", err)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	s := &corev1.Secret{}
+	key := types.NamespacedName{Name: apiTokenSecretPrefix + id, Namespace: resolveNamespace()}
+	err = k8sClient.Get(ctx, key, s)
+	if k8serrors.IsNotFound(err) || (err == nil && (s.Labels[apiTokenLabel] != "true" ||
+		string(s.Data["owner"]) != user.Username)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "token not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error reading token: %v", err)})
+		return
+	}
+	if err := k8sClient.Delete(ctx, s); err != nil && !k8serrors.IsNotFound(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to revoke token: %v", err)})
+		return
+	}
+
+	a.log.Info("API token revoked", "id", id, "owner", user.Username, "reqID", c.GetString("reqID"))
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,81 @@
+package buildapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+)
+
+var _ = Describe("API tokens", func() {
+	It("should generate tokens that parse back into their id and hashed secret", func() {
+		token, id, hash, err := generateAPIToken()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(HavePrefix(apiTokenPrefix + id + "_"))
+
+		parsedID, secret, err := parseAPIToken(token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsedID).To(Equal(id))
+		Expect(hashAPITokenSecret(secret)).To(Equal(hash))
+
+		for _, invalid := range []string{"sha256~abc", "ado_", "ado_abc", "ado_zz_secret", "ado__secret"} {
+			_, _, err := parseAPIToken(invalid)
+			Expect(err).To(MatchError(errInvalidAPIToken), invalid)
+		}
+	})
+
+	DescribeTable("mapping operations to scopes",
+		func(verb, resource, subresource, scope string) {
+			attrs := authzv1.ResourceAttributes{Verb: verb, Resource: resource, Subresource: subresource}
+			Expect(requiredScope(attrs)).To(Equal(scope))
+		},
+		Entry("create build", "create", imageBuildsResource, "", scopeBuildCreate),
+		Entry("upload files", "create", imageBuildsResource, "uploads", scopeBuildCreate),
+		Entry("follow logs", "get", imageBuildsResource, "logs", scopeBuildRead),
+		Entry("download artifact", "get", imageBuildsResource, "artifacts", scopeArtifactRead),
+		Entry("flash build", "create", flashJobsResource, "", scopeBuildCreate),
+		Entry("follow flash logs", "get", flashJobsResource, "logs", scopeBuildRead),
+		Entry("download image", "get", "images", "download", scopeArtifactRead),
+		Entry("list catalog", "list", "catalogimages", "", scopeCatalogRead),
+		Entry("promote", "create", "catalogimages", "promote", scopeCatalogPublish),
+		Entry("unknown resource", "get", "secrets", "", ""),
+	)
+
+	It("should limit token requests to their scopes and leave cluster tokens alone", func() {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		create := authzv1.ResourceAttributes{Verb: "create", Resource: imageBuildsResource}
+		Expect(tokenScopesAllow(c, create)).To(BeTrue())
+
+		c.Set(apiTokenScopesKey, []string{scopeBuildRead})
+		Expect(tokenScopesAllow(c, create)).To(BeFalse())
+		Expect(tokenScopesAllow(c, authzv1.ResourceAttributes{Verb: "get", Resource: imageBuildsResource})).
+			To(BeTrue())
+	})
+
+	It("should let catalog:publish tokens request and approve promotions as their owner", func() {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set(userInfoKey, authnv1.UserInfo{Username: "jane"})
+		promote := authzv1.ResourceAttributes{Verb: "create", Resource: "catalogimages", Subresource: "promote"}
+
+		c.Set(apiTokenScopesKey, []string{scopeCatalogRead})
+		Expect(tokenScopesAllow(c, promote)).To(BeFalse())
+
+		c.Set(apiTokenScopesKey, []string{scopeCatalogPublish})
+		Expect(tokenScopesAllow(c, promote)).To(BeTrue())
+		Expect(authenticatedUser(c)).To(Equal("jane"))
+	})
+
+	It("should validate token requests", func() {
+		req := APITokenRequest{Scopes: []string{scopeBuildCreate}}
+		Expect(validateAPITokenRequest(&req)).To(Succeed())
+		Expect(req.ExpiresInDays).To(Equal(defaultAPITokenDays))
+
+		Expect(validateAPITokenRequest(&APITokenRequest{})).NotTo(Succeed())
+		Expect(validateAPITokenRequest(&APITokenRequest{Scopes: []string{"admin"}})).NotTo(Succeed())
+		Expect(validateAPITokenRequest(&APITokenRequest{Scopes: []string{scopeBuildRead}, ExpiresInDays: 91})).
+			NotTo(Succeed())
+	})
+})
@@ -127,6 +127,9 @@ type BuildRequest struct {
 	Compression            string               `json:"compression,omitempty"`
 	RegistryCredentials    *RegistryCredentials `json:"registryCredentials,omitempty"`
 	PushRepository         string               `json:"pushRepository,omitempty"`
+	S3Upload               *S3Upload            `json:"s3Upload,omitempty"`
+	Delta                  *DeltaRequest        `json:"delta,omitempty"`
+	PublishToCatalog       *CatalogPublish      `json:"publishToCatalog,omitempty"`
 
 	ContainerPush  string `json:"containerPush,omitempty"`  // Registry URL to push bootc container
 	BuildDiskImage bool   `json:"buildDiskImage,omitempty"` // Build disk image from bootc container
@@ -145,6 +148,34 @@ type RegistryCredentials struct {
 	DockerConfig string `json:"dockerConfig"`
 }
 
+// S3Upload describes S3-compatible object storage to upload the finished artifact to.
+// SecretRef names an existing secret in the build namespace with AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+type S3Upload struct {
+	Endpoint              string `json:"endpoint"`
+	Bucket                string `json:"bucket"`
+	Prefix                string `json:"prefix,omitempty"`
+	Region                string `json:"region,omitempty"`
+	SecretRef             string `json:"secretRef"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// DeltaRequest asks for a binary delta from a base image to the build's artifact.
+// Exactly one of BaseBuild and BaseCatalogImage must be set.
+type DeltaRequest struct {
+	BaseBuild        string `json:"baseBuild,omitempty"`
+	BaseCatalogImage string `json:"baseCatalogImage,omitempty"`
+	Method           string `json:"method,omitempty"` // zstd (default), bsdiff or ostree
+}
+
+// CatalogPublish asks for the pushed image to be published to the catalog when the build completes.
+// NameTemplate is a Go template over the build's name, namespace, distro, target, architecture,
+// mode and export format; the build name is used when it is empty.
+type CatalogPublish struct {
+	NameTemplate string   `json:"nameTemplate,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	TargetNotes  string   `json:"targetNotes,omitempty"`
+}
+
 // JumpstarterInfo contains information about Jumpstarter device flashing availability
 type JumpstarterInfo struct {
 	// Available indicates if Jumpstarter is installed in the cluster
@@ -163,9 +194,13 @@ type BuildResponse struct {
 	RequestedBy      string           `json:"requestedBy,omitempty"`
 	ArtifactURL      string           `json:"artifactURL,omitempty"`
 	ArtifactFileName string           `json:"artifactFileName,omitempty"`
+	ArtifactSHA256   string           `json:"artifactSha256,omitempty"`
+	DeltaFileName    string           `json:"deltaFileName,omitempty"`
+	CatalogImageName string           `json:"catalogImageName,omitempty"`
 	StartTime        string           `json:"startTime,omitempty"`
 	CompletionTime   string           `json:"completionTime,omitempty"`
 	Jumpstarter      *JumpstarterInfo `json:"jumpstarter,omitempty"`
+	Warnings         []string         `json:"warnings,omitempty"`
 }
 
 // BuildListItem represents a build in the list API
@@ -179,6 +214,64 @@ type BuildListItem struct {
 	CompletionTime string `json:"completionTime,omitempty"`
 }
 
+// APITokenRequest asks for an API token acting as the caller, limited to the given scopes
+// (build:create, build:read, artifact:read, catalog:read, catalog:publish)
+type APITokenRequest struct {
+	Description   string   `json:"description,omitempty"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expiresInDays,omitempty"` // 90 by default, at most 365
+}
+
+// APITokenResponse describes an API token. Token is only returned when the token is created.
+type APITokenResponse struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description,omitempty"`
+	Owner       string   `json:"owner"`
+	Scopes      []string `json:"scopes"`
+	CreatedAt   string   `json:"createdAt"`
+	ExpiresAt   string   `json:"expiresAt"`
+	Token       string   `json:"token,omitempty"`
+}
+
+// UsageResponse reports the build usage of users over a time range
+type UsageResponse struct {
+	Since string      `json:"since"`
+	Until string      `json:"until"`
+	Users []UserUsage `json:"users"`
+	// Quota is the caller's build quota, set when only the caller's usage is reported
+	Quota *BuildQuotaResponse `json:"quota,omitempty"`
+}
+
+// UserUsage is the build usage of one user. Build counts and minutes cover the builds created in
+// the time range; active builds and artifact storage cover all existing builds.
+type UserUsage struct {
+	User                 string  `json:"user"`
+	Builds               int     `json:"builds"`
+	Succeeded            int     `json:"succeeded"`
+	Failed               int     `json:"failed"`
+	BuildMinutes         float64 `json:"buildMinutes"`
+	ActiveBuilds         int     `json:"activeBuilds"`
+	ArtifactStorageBytes int64   `json:"artifactStorageBytes"`
+}
+
+// BuildQuotaResponse is a user's build quota, zero limits being unlimited
+type BuildQuotaResponse struct {
+	MaxConcurrentBuilds     int32 `json:"maxConcurrentBuilds,omitempty"`
+	MaxBuildsPerDay         int32 `json:"maxBuildsPerDay,omitempty"`
+	MaxArtifactStorageBytes int64 `json:"maxArtifactStorageBytes,omitempty"`
+}
+
+// AuthConfigResponse tells clients how to log in to the build API
+type AuthConfigResponse struct {
+	OIDC *OIDCClientConfig `json:"oidc,omitempty"`
+}
+
+// OIDCClientConfig is the OpenID provider and public client caib login uses
+type OIDCClientConfig struct {
+	IssuerURL string `json:"issuerURL"`
+	ClientID  string `json:"clientID"`
+}
+
 type (
 	// BuildRequestAlias is an alias for BuildRequest used for backward compatibility.
 	BuildRequestAlias = BuildRequest
@@ -191,3 +284,81 @@ type BuildTemplateResponse struct {
 	BuildRequest `json:",inline"`
 	SourceFiles  []string `json:"sourceFiles,omitempty"`
 }
+
+// AuditEvent records who performed a build API operation, from where, and with what outcome
+type AuditEvent struct {
+	Time          string `json:"time"`
+	CorrelationID string `json:"correlationID"`
+	// Action names the operation, such as build.create or artifact.download
+	Action string `json:"action"`
+	// Outcome is success, unauthenticated, denied, rejected or error
+	Outcome    string   `json:"outcome"`
+	Status     int      `json:"status"`
+	User       string   `json:"user,omitempty"`
+	Groups     []string `json:"groups,omitempty"`
+	APITokenID string   `json:"apiTokenID,omitempty"`
+	SourceIP   string   `json:"sourceIP"`
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Name       string   `json:"name,omitempty"`
+}
+
+// Build event types pushed by the watch API
+const (
+	// BuildEventSnapshot carries the state of a build when the watch starts
+	BuildEventSnapshot = "snapshot"
+	// BuildEventCreated reports a new build (list watches only)
+	BuildEventCreated = "created"
+	// BuildEventPhase reports a phase transition
+	BuildEventPhase = "phase"
+	// BuildEventProgress reports a new status message within the same phase
+	BuildEventProgress = "progress"
+	// BuildEventStep reports a pipeline step starting or finishing
+	BuildEventStep = "step"
+	// BuildEventArtifactReady reports that the build artifact can be downloaded
+	BuildEventArtifactReady = "artifact-ready"
+	// BuildEventDeleted reports that the build was deleted
+	BuildEventDeleted = "deleted"
+)
+
+// BuildEvent is a change of a build pushed by the watch API as a server-sent event named after its type
+type BuildEvent struct {
+	Type  string        `json:"type"`
+	Time  string        `json:"time"`
+	Build BuildResponse `json:"build"`
+	// Step is set for step events
+	Step *BuildStep `json:"step,omitempty"`
+}
+
+// BuildStep is the state of a pipeline step (Tekton task) of a build
+type BuildStep struct {
+	Task  string `json:"task"`
+	Phase string `json:"phase"`
+}
+
+// FlashRequest asks for a completed build to be flashed onto a device leased from Jumpstarter. The
+// exporter selector and flash command come from the Jumpstarter target mapping of the build's target;
+// ExporterSelector only adds requirements to the mapping's selector.
+type FlashRequest struct {
+	ExporterSelector     string `json:"exporterSelector,omitempty"`
+	ImageRef             string `json:"imageRef,omitempty"`
+	LeaseDurationMinutes int32  `json:"leaseDurationMinutes,omitempty"`
+}
+
+// FlashJobResponse is returned by POST and GET flash operations
+type FlashJobResponse struct {
+	Name             string `json:"name"`
+	BuildName        string `json:"buildName"`
+	Phase            string `json:"phase"`
+	Message          string `json:"message,omitempty"`
+	RequestedBy      string `json:"requestedBy,omitempty"`
+	ImageRef         string `json:"imageRef,omitempty"`
+	ExporterSelector string `json:"exporterSelector,omitempty"`
+	ExporterName     string `json:"exporterName,omitempty"`
+	FlashCmd         string `json:"flashCmd,omitempty"`
+	LeaseName        string `json:"leaseName,omitempty"`
+	ExitCode         *int32 `json:"exitCode,omitempty"`
+	StartTime        string `json:"startTime,omitempty"`
+	CompletionTime   string `json:"completionTime,omitempty"`
+}
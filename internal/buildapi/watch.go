@@ -0,0 +1,332 @@
+package buildapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	// imageBuildNameLabel is propagated by Tekton from build PipelineRuns to their pods
+	imageBuildNameLabel = "automotive.sdv.cloud.redhat.com/imagebuild-name"
+	pipelineTaskLabel   = "tekton.dev/pipelineTask"
+
+	// watchBufferSize is the number of events a watcher may fall behind before it is disconnected
+	watchBufferSize = 64
+	// watchHeartbeatInterval keeps idle event streams open through proxies
+	watchHeartbeatInterval = 30 * time.Second
+)
+
+// buildSubscription receives the events of one build, or of all builds of a namespace if name is empty
+type buildSubscription struct {
+	namespace string
+	name      string
+	events    chan BuildEvent
+}
+
+// buildWatchHub fans ImageBuild and build pod changes from the informer cache out to watchers.
+// Informer handlers are registered when the first watcher subscribes.
+type buildWatchHub struct {
+	startMu sync.Mutex
+	reader  client.Reader
+
+	mu          sync.Mutex
+	subscribers map[*buildSubscription]struct{}
+}
+
+func newBuildWatchHub() *buildWatchHub {
+	return &buildWatchHub{subscribers: map[*buildSubscription]struct{}{}}
+}
+
+// start registers the informer handlers publishing build events, once
+func (h *buildWatchHub) start(ctx context.Context) error {
+	h.startMu.Lock()
+	defer h.startMu.Unlock()
+	if h.reader != nil {
+		return nil
+	}
+
+	kc, err := loadKubeClients()
+	if err != nil {
+		return err
+	}
+	buildInformer, err := kc.informers.GetInformer(ctx, &automotivev1alpha1.ImageBuild{})
+	if err != nil {
+		return fmt.Errorf("failed to get ImageBuild informer: %w", err)
+	}
+	podInformer, err := kc.informers.GetInformer(ctx, &corev1.Pod{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod informer: %w", err)
+	}
+	return h.register(kc.client, buildInformer, podInformer)
+}
+
+// eventHandlerRegistrar is the part of an informer the hub registers its handlers with
+type eventHandlerRegistrar interface {
+	AddEventHandler(handler toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error)
+	RemoveEventHandler(handle toolscache.ResourceEventHandlerRegistration) error
+}
+
+// register adds the handlers publishing build events to the informers. If a handler cannot be
+// added, the hub is left unstarted, without handlers, so that the next watcher starts it again.
+func (h *buildWatchHub) register(reader client.Reader, buildInformer, podInformer eventHandlerRegistrar) error {
+	h.reader = reader
+	buildHandle, err := buildInformer.AddEventHandler(toolscache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj any, isInInitialList bool) {
+			if build, ok := obj.(*automotivev1alpha1.ImageBuild); ok && !isInInitialList {
+				h.publish(build.Namespace, build.Name, newBuildEvent(BuildEventCreated, build))
+			}
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			oldBuild, _ := oldObj.(*automotivev1alpha1.ImageBuild)
+			build, ok := newObj.(*automotivev1alpha1.ImageBuild)
+			if !ok || oldBuild == nil {
+				return
+			}
+			for _, event := range buildChangeEvents(oldBuild, build) {
+				h.publish(build.Namespace, build.Name, event)
+			}
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if build, ok := obj.(*automotivev1alpha1.ImageBuild); ok {
+				h.publish(build.Namespace, build.Name, newBuildEvent(BuildEventDeleted, build))
+			}
+		},
+	})
+	if err != nil {
+		h.reader = nil
+		return fmt.Errorf("failed to watch ImageBuilds: %w", err)
+	}
+	if _, err := podInformer.AddEventHandler(toolscache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj any, isInInitialList bool) {
+			if pod, ok := obj.(*corev1.Pod); ok && !isInInitialList {
+				h.publishStep(nil, pod)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			oldPod, _ := oldObj.(*corev1.Pod)
+			if pod, ok := newObj.(*corev1.Pod); ok {
+				h.publishStep(oldPod, pod)
+			}
+		},
+	}); err != nil {
+		h.reader = nil
+		if removeErr := buildInformer.RemoveEventHandler(buildHandle); removeErr != nil {
+			return fmt.Errorf("failed to watch build pods: %w (removing the ImageBuild handler failed: %v)",
+				err, removeErr)
+		}
+		return fmt.Errorf("failed to watch build pods: %w", err)
+	}
+	return nil
+}
+
+func (h *buildWatchHub) subscribe(namespace, name string) *buildSubscription {
+	sub := &buildSubscription{namespace: namespace, name: name, events: make(chan BuildEvent, watchBufferSize)}
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+func (h *buildWatchHub) unsubscribe(sub *buildSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// publish sends an event to the watchers of the build. Watchers whose buffer is full are
+// disconnected rather than blocking the informer.
+func (h *buildWatchHub) publish(namespace, name string, event BuildEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		if sub.namespace != namespace || (sub.name != "" && sub.name != name) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			delete(h.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// publishStep reports phase changes of the pods running the pipeline tasks of a build
+func (h *buildWatchHub) publishStep(oldPod, pod *corev1.Pod) {
+	step := podStep(oldPod, pod)
+	if step == nil {
+		return
+	}
+	buildName := pod.Labels[imageBuildNameLabel]
+	build := &automotivev1alpha1.ImageBuild{}
+	key := types.NamespacedName{Name: buildName, Namespace: pod.Namespace}
+	if err := h.reader.Get(context.Background(), key, build); err != nil {
+		build.Name, build.Namespace = buildName, pod.Namespace
+	}
+	event := newBuildEvent(BuildEventStep, build)
+	event.Step = step
+	h.publish(pod.Namespace, buildName, event)
+}
+
+// podStep returns the step of a build pod whose phase changed, or nil for other pods and updates
+func podStep(oldPod, pod *corev1.Pod) *BuildStep {
+	task := pod.Labels[pipelineTaskLabel]
+	if task == "" || pod.Labels[imageBuildNameLabel] == "" {
+		return nil
+	}
+	if oldPod != nil && oldPod.Status.Phase == pod.Status.Phase {
+		return nil
+	}
+	return &BuildStep{Task: task, Phase: string(pod.Status.Phase)}
+}
+
+func newBuildEvent(eventType string, build *automotivev1alpha1.ImageBuild) BuildEvent {
+	return BuildEvent{
+		Type:  eventType,
+		Time:  time.Now().UTC().Format(time.RFC3339),
+		Build: newBuildResponse(build),
+	}
+}
+
+// buildChangeEvents returns the events of a build status update: a phase transition or a new
+// message within the phase, and the artifact becoming available
+func buildChangeEvents(oldBuild, build *automotivev1alpha1.ImageBuild) []BuildEvent {
+	var events []BuildEvent
+	switch {
+	case build.Status.Phase != oldBuild.Status.Phase:
+		events = append(events, newBuildEvent(BuildEventPhase, build))
+	case build.Status.Message != oldBuild.Status.Message:
+		events = append(events, newBuildEvent(BuildEventProgress, build))
+	}
+	if artifactReady(build) && !artifactReady(oldBuild) {
+		events = append(events, newBuildEvent(BuildEventArtifactReady, build))
+	}
+	return events
+}
+
+// artifactReady reports whether the artifact of a build can be downloaded
+func artifactReady(build *automotivev1alpha1.ImageBuild) bool {
+	return build.Status.ArtifactURL != "" ||
+		(build.Status.Phase == phaseCompleted && build.Status.ArtifactFileName != "")
+}
+
+// handleBuildEvents streams the events of a build as server-sent events, starting with its state
+func (a *APIServer) handleBuildEvents(c *gin.Context) {
+	name := c.Param("name")
+	namespace := requestNamespace(c)
+	a.log.Info("build events requested", "build", name, "reqID", c.GetString("reqID"))
+
+	if err := a.watches.start(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to watch builds: %v", err)})
+		return
+	}
+	sub := a.watches.subscribe(namespace, name)
+	defer a.watches.unsubscribe(sub)
+
+	build := &automotivev1alpha1.ImageBuild{}
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+	if err := a.watches.reader.Get(c.Request.Context(), key, build); err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error fetching build: %v", err)})
+		return
+	}
+	streamBuildEvents(c, sub, []BuildEvent{newBuildEvent(BuildEventSnapshot, build)})
+}
+
+// watchBuilds streams the events of all builds of the namespace as server-sent events, starting
+// with their state
+func (a *APIServer) watchBuilds(c *gin.Context) {
+	namespace := requestNamespace(c)
+	allowed, err := a.reviewAccess(c, authzv1.ResourceAttributes{
+		Verb:      "watch",
+		Group:     automotivev1alpha1.GroupVersion.Group,
+		Resource:  imageBuildsResource,
+		Namespace: namespace,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to authorize request"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden: cannot watch " + imageBuildsResource})
+		return
+	}
+
+	if err := a.watches.start(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to watch builds: %v", err)})
+		return
+	}
+	sub := a.watches.subscribe(namespace, "")
+	defer a.watches.unsubscribe(sub)
+
+	list := &automotivev1alpha1.ImageBuildList{}
+	if err := a.watches.reader.List(c.Request.Context(), list, client.InNamespace(namespace)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error listing builds: %v", err)})
+		return
+	}
+	snapshot := make([]BuildEvent, 0, len(list.Items))
+	for i := range list.Items {
+		snapshot = append(snapshot, newBuildEvent(BuildEventSnapshot, &list.Items[i]))
+	}
+	streamBuildEvents(c, sub, snapshot)
+}
+
+// streamBuildEvents writes the snapshot and then the events of the subscription until the client
+// disconnects, the watched build is deleted or the watcher falls behind
+func streamBuildEvents(c *gin.Context, sub *buildSubscription, snapshot []BuildEvent) {
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+	c.Writer.WriteHeader(http.StatusOK)
+	for _, event := range snapshot {
+		c.SSEvent(event.Type, event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(watchHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.events:
+			if !ok {
+				return
+			}
+			c.SSEvent(event.Type, event)
+			c.Writer.Flush()
+			if event.Type == BuildEventDeleted && sub.name != "" {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": keepalive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
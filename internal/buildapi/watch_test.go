@@ -0,0 +1,145 @@
+package buildapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// fakeInformer records the event handlers registered with it, failing registrations if err is set
+type fakeInformer struct {
+	err      error
+	handlers map[toolscache.ResourceEventHandlerRegistration]struct{}
+}
+
+type fakeRegistration struct{ id int }
+
+func (r *fakeRegistration) HasSynced() bool { return true }
+
+func (f *fakeInformer) AddEventHandler(
+	_ toolscache.ResourceEventHandler,
+) (toolscache.ResourceEventHandlerRegistration, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.handlers == nil {
+		f.handlers = map[toolscache.ResourceEventHandlerRegistration]struct{}{}
+	}
+	handle := &fakeRegistration{id: len(f.handlers)}
+	f.handlers[handle] = struct{}{}
+	return handle, nil
+}
+
+func (f *fakeInformer) RemoveEventHandler(handle toolscache.ResourceEventHandlerRegistration) error {
+	delete(f.handlers, handle)
+	return nil
+}
+
+var _ = Describe("Build watch", func() {
+	build := func(phase, message, artifactURL string) *automotivev1alpha1.ImageBuild {
+		return &automotivev1alpha1.ImageBuild{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-build", Namespace: "builds"},
+			Status: automotivev1alpha1.ImageBuildStatus{
+				Phase: phase, Message: message, ArtifactURL: artifactURL, ArtifactFileName: "disk.qcow2",
+			},
+		}
+	}
+	eventTypes := func(events []BuildEvent) []string {
+		var types []string
+		for _, e := range events {
+			types = append(types, e.Type)
+		}
+		return types
+	}
+
+	It("should report phase transitions, progress and artifact readiness", func() {
+		Expect(eventTypes(buildChangeEvents(build("Pending", "", ""), build("Building", "Build started", "")))).
+			To(Equal([]string{BuildEventPhase}))
+		Expect(eventTypes(buildChangeEvents(build("Building", "Build started", ""), build("Building", "Pushing", "")))).
+			To(Equal([]string{BuildEventProgress}))
+		Expect(eventTypes(buildChangeEvents(build("Building", "", ""), build(phaseCompleted, "Done", "")))).
+			To(Equal([]string{BuildEventPhase, BuildEventArtifactReady}))
+		Expect(eventTypes(buildChangeEvents(build(phaseCompleted, "Done", ""), build(phaseCompleted, "Done", "https://x")))).
+			To(BeEmpty())
+		Expect(buildChangeEvents(build("Building", "a", ""), build("Building", "a", ""))).To(BeEmpty())
+	})
+
+	It("should report phase changes of build task pods only", func() {
+		pod := func(phase corev1.PodPhase, labels map[string]string) *corev1.Pod {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: labels}, Status: corev1.PodStatus{Phase: phase}}
+		}
+		labels := map[string]string{imageBuildNameLabel: "my-build", pipelineTaskLabel: "build-image"}
+
+		Expect(podStep(nil, pod(corev1.PodPending, labels))).To(Equal(&BuildStep{Task: "build-image", Phase: "Pending"}))
+		Expect(podStep(pod(corev1.PodPending, labels), pod(corev1.PodRunning, labels)).Phase).To(Equal("Running"))
+		Expect(podStep(pod(corev1.PodRunning, labels), pod(corev1.PodRunning, labels))).To(BeNil())
+		Expect(podStep(nil, pod(corev1.PodRunning, map[string]string{imageBuildNameLabel: "my-build"}))).To(BeNil())
+	})
+
+	It("should deliver events to matching watchers and drop watchers that fall behind", func() {
+		hub := newBuildWatchHub()
+		one := hub.subscribe("builds", "my-build")
+		all := hub.subscribe("builds", "")
+		other := hub.subscribe("other", "")
+
+		hub.publish("builds", "my-build", BuildEvent{Type: BuildEventPhase})
+		hub.publish("builds", "another", BuildEvent{Type: BuildEventCreated})
+		Expect(one.events).To(HaveLen(1))
+		Expect(all.events).To(HaveLen(2))
+		Expect(other.events).To(BeEmpty())
+
+		for range watchBufferSize {
+			hub.publish("builds", "another", BuildEvent{Type: BuildEventProgress})
+		}
+		Expect(hub.subscribers).NotTo(HaveKey(all))
+		Expect(hub.subscribers).To(HaveKey(one))
+		hub.unsubscribe(all)
+		hub.unsubscribe(one)
+		Expect(hub.subscribers).To(HaveLen(1))
+	})
+
+	It("should leave the hub unstarted if a handler cannot be registered", func() {
+		hub := newBuildWatchHub()
+		reader := struct{ client.Reader }{}
+		builds := &fakeInformer{}
+		pods := &fakeInformer{err: errors.New("informer stopped")}
+
+		Expect(hub.register(reader, builds, pods)).To(MatchError(ContainSubstring("informer stopped")))
+		Expect(hub.reader).To(BeNil())
+		Expect(builds.handlers).To(BeEmpty())
+
+		pods.err = nil
+		Expect(hub.register(reader, builds, pods)).To(Succeed())
+		Expect(hub.reader).NotTo(BeNil())
+		Expect(builds.handlers).To(HaveLen(1))
+		Expect(pods.handlers).To(HaveLen(1))
+	})
+
+	It("should stream the snapshot and events as server-sent events", func() {
+		hub := newBuildWatchHub()
+		sub := hub.subscribe("builds", "my-build")
+		hub.publish("builds", "my-build", newBuildEvent(BuildEventPhase, build("Building", "", "")))
+		hub.publish("builds", "my-build", newBuildEvent(BuildEventDeleted, build("Building", "", "")))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/v1/builds/my-build/events", nil)
+		streamBuildEvents(c, sub, []BuildEvent{newBuildEvent(BuildEventSnapshot, build("Pending", "", ""))})
+
+		Expect(w.Header().Get("Content-Type")).To(HavePrefix("text/event-stream"))
+		body := w.Body.String()
+		Expect(body).To(ContainSubstring("event:snapshot\ndata:{"))
+		Expect(body).To(ContainSubstring(`"phase":"Building"`))
+		Expect(body).To(ContainSubstring("event:deleted\n"))
+	})
+})
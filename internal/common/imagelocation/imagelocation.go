@@ -0,0 +1,142 @@
+// Package imagelocation provides helpers shared by the Image controller and the build API
+// for reading images from HTTP servers, S3-compatible storage and PersistentVolumeClaims.
+package imagelocation
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Location types supported by Image
+const (
+	TypeRegistry = "registry"
+	TypeHTTP     = "http"
+	TypeS3       = "s3"
+	TypePVC      = "pvc"
+)
+
+const (
+	// ReaderContainerName is the container of the reader pod that downloads are streamed from
+	ReaderContainerName = "fileserver"
+
+	// ReaderMountPath is where the reader pod mounts the claim of a pvc location
+	ReaderMountPath = "/data"
+
+	// ImageNameLabel links a reader pod to its Image
+	ImageNameLabel = "automotive.sdv.cloud.redhat.com/image-name"
+)
+
+// ReaderPodLabels returns the labels of the reader pod that serves a pvc location
+func ReaderPodLabels(imageName string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/managed-by": "automotive-dev-operator",
+		"app.kubernetes.io/name":       "image-reader-pod",
+		ImageNameLabel:                 imageName,
+	}
+}
+
+// CleanPVCPath validates a path relative to the root of a volume and returns it in canonical form
+func CleanPVCPath(p string) (string, error) {
+	for _, segment := range strings.Split(p, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("invalid path %q: must not contain '..'", p)
+		}
+	}
+	cleaned := path.Clean("/" + strings.TrimSpace(p))
+	if cleaned == "/" {
+		return "", fmt.Errorf("invalid path %q: must name a file inside the volume", p)
+	}
+	return strings.TrimPrefix(cleaned, "/"), nil
+}
+
+// FileName returns the file name clients should save an image as, or an empty string
+// for registry locations
+func FileName(loc automotivev1alpha1.ImageLocation) string {
+	switch {
+	case loc.Type == TypeHTTP && loc.HTTP != nil:
+		u := loc.HTTP.URL
+		if i := strings.IndexAny(u, "?#"); i >= 0 {
+			u = u[:i]
+		}
+		return path.Base(u)
+	case loc.Type == TypeS3 && loc.S3 != nil:
+		return path.Base(loc.S3.Key)
+	case loc.Type == TypePVC && loc.PVC != nil:
+		return path.Base(loc.PVC.Path)
+	default:
+		return ""
+	}
+}
+
+// NewHTTPClient returns an HTTP client for an image server
+func NewHTTPClient(insecureSkipTLSVerify bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if insecureSkipTLSVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // Explicitly requested
+	}
+	return &http.Client{Transport: transport}
+}
+
+// AuthorizationHeader returns the Authorization header value for an HTTP location secret,
+// which holds either a "token" key or "username" and "password" keys
+func AuthorizationHeader(secret *corev1.Secret) (string, error) {
+	if token := strings.TrimSpace(string(secret.Data["token"])); token != "" {
+		return "Bearer " + token, nil
+	}
+	username := string(secret.Data["username"])
+	password := string(secret.Data["password"])
+	if username == "" || password == "" {
+		return "", fmt.Errorf("secret %s must contain either a token key or username and password keys", secret.Name)
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)), nil
+}
+
+// AdvertisedSHA256 returns the hex-encoded SHA-256 digest a server advertises for the full
+// representation in a Repr-Digest (RFC 9530) or legacy Digest (RFC 3230) header, if any
+func AdvertisedSHA256(h http.Header) string {
+	for _, field := range strings.Split(h.Get("Repr-Digest"), ",") {
+		alg, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if ok && strings.EqualFold(alg, "sha-256") {
+			if sum := decodeBase64Digest(strings.Trim(value, ":")); sum != "" {
+				return sum
+			}
+		}
+	}
+	for _, field := range strings.Split(h.Get("Digest"), ",") {
+		alg, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if ok && strings.EqualFold(alg, "sha-256") {
+			if sum := decodeBase64Digest(value); sum != "" {
+				return sum
+			}
+		}
+	}
+	return ""
+}
+
+func decodeBase64Digest(value string) string {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil || len(raw) != 32 {
+		return ""
+	}
+	return hex.EncodeToString(raw)
+}
+
+// FileSize returns the size of an image file, recorded as compressed when the file name
+// carries a compression suffix
+func FileSize(fileName string, n int64) *automotivev1alpha1.ImageSize {
+	lower := strings.ToLower(fileName)
+	for _, ext := range []string{".gz", ".lz4", ".xz", ".zst"} {
+		if strings.HasSuffix(lower, ext) {
+			return &automotivev1alpha1.ImageSize{CompressedBytes: &n}
+		}
+	}
+	return &automotivev1alpha1.ImageSize{UncompressedBytes: &n}
+}
@@ -0,0 +1,87 @@
+package imagelocation
+
+import (
+	"net/http"
+	"testing"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCleanPVCPath(t *testing.T) {
+	valid := map[string]string{
+		"images/autosd.raw":   "images/autosd.raw",
+		"/images/autosd.raw":  "images/autosd.raw",
+		"images//./a.qcow2":   "images/a.qcow2",
+		"dir/file..with.dots": "dir/file..with.dots",
+	}
+	for in, want := range valid {
+		got, err := CleanPVCPath(in)
+		if err != nil || got != want {
+			t.Errorf("CleanPVCPath(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", "/", "../secret", "images/../../etc/passwd"} {
+		if _, err := CleanPVCPath(in); err == nil {
+			t.Errorf("CleanPVCPath(%q) should fail", in)
+		}
+	}
+}
+
+func TestAdvertisedSHA256(t *testing.T) {
+	// sha256 of the empty string
+	const empty = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	const emptyB64 = "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+
+	h := http.Header{}
+	h.Set("Repr-Digest", "sha-512=:abc=:, sha-256=:"+emptyB64+":")
+	if got := AdvertisedSHA256(h); got != empty {
+		t.Errorf("Repr-Digest: got %q, want %q", got, empty)
+	}
+
+	h = http.Header{}
+	h.Set("Digest", "SHA-256="+emptyB64)
+	if got := AdvertisedSHA256(h); got != empty {
+		t.Errorf("Digest: got %q, want %q", got, empty)
+	}
+
+	h = http.Header{}
+	h.Set("Digest", "MD5=1B2M2Y8AsgTpgAmY7PhCfg==")
+	if got := AdvertisedSHA256(h); got != "" {
+		t.Errorf("unrelated algorithm: got %q, want empty", got)
+	}
+}
+
+func TestAuthorizationHeader(t *testing.T) {
+	token := &corev1.Secret{Data: map[string][]byte{"token": []byte("abc")}}
+	if got, _ := AuthorizationHeader(token); got != "Bearer abc" {
+		t.Errorf("token secret: got %q", got)
+	}
+
+	basic := &corev1.Secret{Data: map[string][]byte{"username": []byte("user"), "password": []byte("pass")}}
+	if got, _ := AuthorizationHeader(basic); got != "Basic dXNlcjpwYXNz" {
+		t.Errorf("basic secret: got %q", got)
+	}
+
+	if _, err := AuthorizationHeader(&corev1.Secret{}); err == nil {
+		t.Error("empty secret should fail")
+	}
+}
+
+func TestFileNameAndSize(t *testing.T) {
+	loc := automotivev1alpha1.ImageLocation{
+		Type: TypeHTTP,
+		HTTP: &automotivev1alpha1.HTTPLocation{URL: "https://mirror.example.com/a/autosd.qcow2.gz?sig=1"},
+	}
+	if got := FileName(loc); got != "autosd.qcow2.gz" {
+		t.Errorf("FileName() = %q", got)
+	}
+
+	if size := FileSize("autosd.qcow2.gz", 10); size.CompressedBytes == nil || *size.CompressedBytes != 10 {
+		t.Errorf("compressed file size not recorded as compressed: %+v", size)
+	}
+	if size := FileSize("autosd.raw", 10); size.UncompressedBytes == nil || *size.UncompressedBytes != 10 {
+		t.Errorf("raw file size not recorded as uncompressed: %+v", size)
+	}
+}
@@ -0,0 +1,129 @@
+package notify
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// Kinds of objects notifications are sent for
+const (
+	KindImageBuild   = "ImageBuild"
+	KindCatalogImage = "CatalogImage"
+)
+
+// defaultBuildPhases and defaultCatalogPhases are notified by targets whose filter sets no phases
+var (
+	defaultBuildPhases   = []string{"Completed", "Failed"}
+	defaultCatalogPhases = []automotivev1alpha1.CatalogImagePhase{automotivev1alpha1.CatalogImagePhaseUnavailable}
+)
+
+// Event is the phase change of an ImageBuild or CatalogImage, sent as JSON body to webhook targets
+type Event struct {
+	Kind          string            `json:"kind"`
+	Namespace     string            `json:"namespace"`
+	Name          string            `json:"name"`
+	Phase         string            `json:"phase"`
+	PreviousPhase string            `json:"previousPhase,omitempty"`
+	Message       string            `json:"message,omitempty"`
+	User          string            `json:"user,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Image         string            `json:"image,omitempty"`
+	Duration      string            `json:"duration,omitempty"`
+	Time          time.Time         `json:"time"`
+}
+
+// BuildPhaseChange returns the event of an ImageBuild update, reporting false if its phase did not change
+func BuildPhaseChange(previous, build *automotivev1alpha1.ImageBuild) (Event, bool) {
+	if build.Status.Phase == "" || build.Status.Phase == previous.Status.Phase {
+		return Event{}, false
+	}
+	event := Event{
+		Kind:          KindImageBuild,
+		Namespace:     build.Namespace,
+		Name:          build.Name,
+		Phase:         build.Status.Phase,
+		PreviousPhase: previous.Status.Phase,
+		Message:       build.Status.Message,
+		User:          build.Annotations[automotivev1alpha1.RequestedByAnnotation],
+		Labels:        maps.Clone(build.Labels),
+		Time:          time.Now().UTC(),
+	}
+	if start, end := build.Status.StartTime, build.Status.CompletionTime; start != nil && end != nil {
+		event.Duration = end.Sub(start.Time).Round(time.Second).String()
+	}
+	return event, true
+}
+
+// CatalogPhaseChange returns the event of a CatalogImage update, reporting false if its phase did not change
+func CatalogPhaseChange(previous, image *automotivev1alpha1.CatalogImage) (Event, bool) {
+	if image.Status.Phase == "" || image.Status.Phase == previous.Status.Phase {
+		return Event{}, false
+	}
+	event := Event{
+		Kind:          KindCatalogImage,
+		Namespace:     image.Namespace,
+		Name:          image.Name,
+		Phase:         string(image.Status.Phase),
+		PreviousPhase: string(previous.Status.Phase),
+		Labels:        maps.Clone(image.Labels),
+		Image:         image.Spec.RegistryURL,
+		Time:          time.Now().UTC(),
+	}
+	ready := meta.FindStatusCondition(image.Status.Conditions, automotivev1alpha1.CatalogImageConditionReady)
+	if ready != nil {
+		event.Message = ready.Message
+	}
+	return event, true
+}
+
+// Summary is the one-line description of the event used in Slack messages and email subjects
+func (e Event) Summary() string {
+	summary := fmt.Sprintf("%s %s/%s is %s", e.Kind, e.Namespace, e.Name, e.Phase)
+	if e.Duration != "" {
+		summary += " after " + e.Duration
+	}
+	if e.Message != "" {
+		summary += ": " + e.Message
+	}
+	return summary
+}
+
+// Matches reports whether a target with the given filter is notified of the event
+func Matches(filter *automotivev1alpha1.NotificationFilter, e Event) bool {
+	if filter == nil {
+		filter = &automotivev1alpha1.NotificationFilter{}
+	}
+
+	buildPhases, catalogPhases := filter.BuildPhases, filter.CatalogPhases
+	if len(buildPhases) == 0 && len(catalogPhases) == 0 {
+		buildPhases, catalogPhases = defaultBuildPhases, defaultCatalogPhases
+	}
+	switch e.Kind {
+	case KindImageBuild:
+		if !slices.Contains(buildPhases, e.Phase) {
+			return false
+		}
+		if len(filter.Users) > 0 && !slices.Contains(filter.Users, e.User) {
+			return false
+		}
+	case KindCatalogImage:
+		if !slices.Contains(catalogPhases, automotivev1alpha1.CatalogImagePhase(e.Phase)) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	for key, value := range filter.Labels {
+		if v, ok := e.Labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
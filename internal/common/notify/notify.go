@@ -0,0 +1,190 @@
+// Package notify sends ImageBuild and CatalogImage phase changes to the notification targets of the
+// OperatorConfig: HMAC-signed JSON webhooks, Slack-compatible incoming webhooks and email.
+// Failed deliveries are retried with backoff and, once retries are exhausted, written to the
+// dead-letter log.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	// queueSize bounds the events and deliveries waiting to be sent; events beyond it are dead-lettered
+	queueSize = 1000
+	// workers is the number of deliveries sent concurrently
+	workers = 4
+)
+
+// retryDelays are the waits before each retry of a failed delivery
+var retryDelays = []time.Duration{10 * time.Second, time.Minute, 5 * time.Minute}
+
+// deliveriesTotal counts notification deliveries by target type and result
+var deliveriesTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "notifications",
+		Name:      "deliveries_total",
+		Help:      "Total number of notification deliveries by target type and result (sent, retried or dead_letter)",
+	},
+	[]string{"type", "result"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(deliveriesTotal)
+}
+
+// delivery is an event waiting to be sent, to all matching targets when target is nil
+type delivery struct {
+	event   Event
+	target  *automotivev1alpha1.NotificationTarget
+	attempt int
+}
+
+// Notifier delivers events to the targets configured in the OperatorConfig. It is a leader election
+// runnable, so only the leading operator replica sends notifications; events are dropped until it starts.
+type Notifier struct {
+	client    client.Client
+	namespace string
+	log       logr.Logger
+	senders   map[string]sender
+
+	queue       chan delivery
+	started     atomic.Bool
+	retryDelays []time.Duration
+}
+
+// New creates a notifier reading the OperatorConfig named "config" and target secrets from namespace
+func New(c client.Client, namespace string, log logr.Logger) *Notifier {
+	n := &Notifier{
+		client:      c,
+		namespace:   namespace,
+		log:         log,
+		queue:       make(chan delivery, queueSize),
+		retryDelays: retryDelays,
+	}
+	n.senders = map[string]sender{
+		targetWebhook: n.sendWebhook,
+		targetSlack:   n.sendSlack,
+		targetEmail:   n.sendEmail,
+	}
+	return n
+}
+
+// NeedLeaderElection makes the manager start the notifier on the leader only
+func (n *Notifier) NeedLeaderElection() bool {
+	return true
+}
+
+// Start sends queued notifications until ctx is cancelled
+func (n *Notifier) Start(ctx context.Context) error {
+	n.started.Store(true)
+	for range workers {
+		go n.run(ctx)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// Notify queues an event for the targets whose filter matches it. It never blocks.
+func (n *Notifier) Notify(event Event) {
+	if n == nil || !n.started.Load() {
+		return
+	}
+	n.enqueue(delivery{event: event})
+}
+
+func (n *Notifier) enqueue(d delivery) {
+	select {
+	case n.queue <- d:
+	default:
+		n.deadLetter(d, errQueueFull)
+	}
+}
+
+func (n *Notifier) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-n.queue:
+			if d.target == nil {
+				n.fanOut(ctx, d.event)
+			} else {
+				n.deliver(ctx, d)
+			}
+		}
+	}
+}
+
+// fanOut queues a delivery of the event to every matching target of the current configuration
+func (n *Notifier) fanOut(ctx context.Context, event Event) {
+	config := &automotivev1alpha1.OperatorConfig{}
+	if err := n.client.Get(ctx, k8stypes.NamespacedName{Name: "config", Namespace: n.namespace}, config); err != nil {
+		n.log.Error(err, "Failed to get OperatorConfig for notifications", "event", event.Summary())
+		return
+	}
+	if config.Spec.Notifications == nil {
+		return
+	}
+	for i := range config.Spec.Notifications.Targets {
+		target := &config.Spec.Notifications.Targets[i]
+		if Matches(target.Filter, event) {
+			n.deliver(ctx, delivery{event: event, target: target})
+		}
+	}
+}
+
+// deliver sends one delivery, scheduling a retry or dead-lettering it on failure
+func (n *Notifier) deliver(ctx context.Context, d delivery) {
+	kind := targetType(d.target)
+	send, ok := n.senders[kind]
+	if !ok {
+		n.deadLetter(d, errNoTargetType)
+		return
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	err := send(sendCtx, d.target, d.event)
+	cancel()
+	if err == nil {
+		deliveriesTotal.WithLabelValues(kind, "sent").Inc()
+		return
+	}
+	if d.attempt >= len(n.retryDelays) || ctx.Err() != nil {
+		n.deadLetter(d, err)
+		return
+	}
+
+	deliveriesTotal.WithLabelValues(kind, "retried").Inc()
+	delay := n.retryDelays[d.attempt]
+	n.log.Info("Notification failed, retrying", "target", d.target.Name, "event", d.event.Summary(),
+		"retryIn", delay, "error", err.Error())
+	d.attempt++
+	time.AfterFunc(delay, func() {
+		if ctx.Err() == nil {
+			n.enqueue(d)
+		}
+	})
+}
+
+// deadLetter logs a notification that could not be delivered, with its full payload so it can be resent
+func (n *Notifier) deadLetter(d delivery, err error) {
+	target := ""
+	if d.target != nil {
+		target = d.target.Name
+	}
+	deliveriesTotal.WithLabelValues(targetType(d.target), "dead_letter").Inc()
+	payload, _ := json.Marshal(d.event)
+	n.log.Error(err, "Notification dead-lettered", "deadLetter", true, "target", target,
+		"attempts", d.attempt+1, "event", string(payload))
+}
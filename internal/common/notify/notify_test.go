@@ -0,0 +1,159 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+func TestMatches(t *testing.T) {
+	completed := Event{Kind: KindImageBuild, Phase: "Completed", User: "jane", Labels: map[string]string{"team": "a"}}
+	building := Event{Kind: KindImageBuild, Phase: "Building", User: "jane"}
+	unavailable := Event{Kind: KindCatalogImage, Phase: "Unavailable"}
+
+	tests := []struct {
+		name   string
+		filter *automotivev1alpha1.NotificationFilter
+		event  Event
+		want   bool
+	}{
+		{"default completed build", nil, completed, true},
+		{"default building build", nil, building, false},
+		{"default unavailable image", nil, unavailable, true},
+		{"build phases only", &automotivev1alpha1.NotificationFilter{BuildPhases: []string{"Building"}}, unavailable, false},
+		{"build phase listed", &automotivev1alpha1.NotificationFilter{BuildPhases: []string{"Building"}}, building, true},
+		{"catalog phases only", &automotivev1alpha1.NotificationFilter{
+			CatalogPhases: []automotivev1alpha1.CatalogImagePhase{"Unavailable"}}, completed, false},
+		{"user listed", &automotivev1alpha1.NotificationFilter{Users: []string{"jane"}}, completed, true},
+		{"user not listed", &automotivev1alpha1.NotificationFilter{Users: []string{"joe"}}, completed, false},
+		{"users ignored for catalog", &automotivev1alpha1.NotificationFilter{Users: []string{"joe"}}, unavailable, true},
+		{"labels match", &automotivev1alpha1.NotificationFilter{Labels: map[string]string{"team": "a"}}, completed, true},
+		{"labels differ", &automotivev1alpha1.NotificationFilter{Labels: map[string]string{"team": "b"}}, completed, false},
+		{"label missing", &automotivev1alpha1.NotificationFilter{Labels: map[string]string{"team": ""}}, unavailable, false},
+	}
+	for _, tt := range tests {
+		if got := Matches(tt.filter, tt.event); got != tt.want {
+			t.Errorf("%s: Matches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildPhaseChange(t *testing.T) {
+	start := metav1.NewTime(time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(42*time.Minute + 10*time.Second))
+	previous := &automotivev1alpha1.ImageBuild{}
+	previous.Status.Phase = "Building"
+	build := previous.DeepCopy()
+	build.Name, build.Namespace = "nightly", "team-a"
+	build.Annotations = map[string]string{automotivev1alpha1.RequestedByAnnotation: "jane"}
+	build.Status.Phase = "Completed"
+	build.Status.StartTime, build.Status.CompletionTime = &start, &end
+
+	if _, ok := BuildPhaseChange(previous, previous.DeepCopy()); ok {
+		t.Error("expected no event without a phase change")
+	}
+	event, ok := BuildPhaseChange(previous, build)
+	if !ok {
+		t.Fatal("expected an event for the phase change")
+	}
+	if event.User != "jane" || event.PreviousPhase != "Building" || event.Duration != "42m10s" {
+		t.Errorf("unexpected event %+v", event)
+	}
+	if want := "ImageBuild team-a/nightly is Completed after 42m10s"; event.Summary() != want {
+		t.Errorf("Summary() = %q, want %q", event.Summary(), want)
+	}
+}
+
+func TestSendWebhook(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-ADO-Event") != KindImageBuild {
+			t.Errorf("unexpected event header %q", r.Header.Get("X-ADO-Event"))
+		}
+		_ = json.Unmarshal(body, &received)
+	}))
+	defer server.Close()
+
+	n := New(nil, "ado", logr.Discard())
+	target := &automotivev1alpha1.NotificationTarget{
+		Name:    "ci",
+		Webhook: &automotivev1alpha1.NotificationWebhook{URL: server.URL},
+	}
+	event := Event{Kind: KindImageBuild, Namespace: "team-a", Name: "nightly", Phase: "Failed"}
+	if err := n.sendWebhook(context.Background(), target, event); err != nil {
+		t.Fatal(err)
+	}
+	if received.Name != "nightly" || received.Phase != "Failed" {
+		t.Errorf("unexpected body %+v", received)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// The key and message of HMAC-SHA256 test case 2 of RFC 4231, prefixed with a timestamp
+	got := Sign([]byte("Jefe"), "1700000000", []byte("what do ya want for nothing?"))
+	want := "sha256=1cdd0650c8be1cb0974b1788d458b1e781206cfef59b85faafc582d2e182c57e"
+	if got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+	if Sign([]byte("Jefe"), "1700000300", []byte("what do ya want for nothing?")) == want {
+		t.Error("expected the signature to cover the timestamp")
+	}
+}
+
+func TestDeliverRetriesThenDeadLetters(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n := New(nil, "ado", logr.Discard())
+	n.retryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+	var attempts atomic.Int32
+	n.senders[targetWebhook] = func(context.Context, *automotivev1alpha1.NotificationTarget, Event) error {
+		attempts.Add(1)
+		return errors.New("connection refused")
+	}
+	go func() { _ = n.Start(ctx) }()
+
+	target := &automotivev1alpha1.NotificationTarget{
+		Name:    "ci",
+		Webhook: &automotivev1alpha1.NotificationWebhook{URL: "http://localhost"},
+	}
+	n.deliver(ctx, delivery{event: Event{Kind: KindImageBuild}, target: target})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for attempts.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("expected the first attempt and 2 retries, got %d attempts", got)
+	}
+}
+
+func TestEmailMessage(t *testing.T) {
+	event := Event{Kind: KindCatalogImage, Namespace: "team-a", Name: "autosd", Phase: "Unavailable",
+		Message: "registry unreachable", Time: time.Now()}
+	msg := string(emailMessage("ado@example.com", []string{"a@example.com", "b@example.com"}, event))
+
+	for _, want := range []string{
+		"To: a@example.com, b@example.com\r\n",
+		"Subject: CatalogImage team-a/autosd is Unavailable\r\n",
+		"\r\n\r\nCatalogImage team-a/autosd is Unavailable: registry unreachable\r\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message does not contain %q:\n%s", want, msg)
+		}
+	}
+}
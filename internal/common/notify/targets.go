@@ -0,0 +1,240 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	targetWebhook = "webhook"
+	targetSlack   = "slack"
+	targetEmail   = "email"
+
+	// SignatureHeader carries the HMAC-SHA256 of the timestamp and body of webhook requests when the
+	// target has a signing secret
+	SignatureHeader = "X-ADO-Signature-256"
+	// TimestampHeader carries the Unix time a webhook request was signed at. Receivers should reject
+	// requests older than MaxSignatureAge, so that captured requests cannot be replayed.
+	TimestampHeader = "X-ADO-Timestamp"
+	// MaxSignatureAge is the clock skew and delay receivers are expected to allow for signed requests
+	MaxSignatureAge = 5 * time.Minute
+
+	// sendTimeout bounds a single delivery attempt
+	sendTimeout = 30 * time.Second
+
+	defaultSMTPPort = 587
+)
+
+var (
+	errQueueFull    = errors.New("notification queue is full")
+	errNoTargetType = errors.New("target sets none of webhook, slack and email")
+)
+
+// sender delivers an event to one target
+type sender func(ctx context.Context, target *automotivev1alpha1.NotificationTarget, event Event) error
+
+func targetType(target *automotivev1alpha1.NotificationTarget) string {
+	switch {
+	case target == nil:
+		return ""
+	case target.Webhook != nil:
+		return targetWebhook
+	case target.Slack != nil:
+		return targetSlack
+	case target.Email != nil:
+		return targetEmail
+	}
+	return ""
+}
+
+// Sign returns the SignatureHeader value of a webhook request, the HMAC of "<timestamp>.<body>", so
+// receivers can verify notifications and their age
+func Sign(key []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendWebhook posts the event as JSON, signed when the target names a secret. Every attempt is signed
+// with the time it is sent at.
+func (n *Notifier) sendWebhook(ctx context.Context, target *automotivev1alpha1.NotificationTarget, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"X-ADO-Event": event.Kind}
+	if target.Webhook.SecretName != "" {
+		key, err := n.secretValue(ctx, target.Webhook.SecretName, "token")
+		if err != nil {
+			return err
+		}
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		headers[TimestampHeader] = timestamp
+		headers[SignatureHeader] = Sign(key, timestamp, body)
+	}
+	return postJSON(ctx, target.Webhook.URL, body, headers)
+}
+
+// sendSlack posts the event summary to an incoming webhook, whose URL is itself a credential
+func (n *Notifier) sendSlack(ctx context.Context, target *automotivev1alpha1.NotificationTarget, event Event) error {
+	url, err := n.secretValue(ctx, target.Slack.SecretName, "url")
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{"text": event.Summary()})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, strings.TrimSpace(string(url)), body, nil)
+}
+
+// sendEmail sends the event as a plain text message
+func (n *Notifier) sendEmail(ctx context.Context, target *automotivev1alpha1.NotificationTarget, event Event) error {
+	cfg := target.Email
+	port := int(cfg.Port)
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+
+	var auth smtp.Auth
+	if cfg.SecretName != "" {
+		username, err := n.secretValue(ctx, cfg.SecretName, "username")
+		if err != nil {
+			return err
+		}
+		password, err := n.secretValue(ctx, cfg.SecretName, "password")
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", string(username), string(password), cfg.Host)
+	}
+	return sendMail(ctx, net.JoinHostPort(cfg.Host, strconv.Itoa(port)), cfg.Host, auth, cfg.From, cfg.To,
+		emailMessage(cfg.From, cfg.To, event))
+}
+
+// emailMessage renders the event as an RFC 5322 message
+func emailMessage(from string, to []string, event Event) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s %s/%s is %s\r\n", event.Kind, event.Namespace, event.Name, event.Phase)
+	fmt.Fprintf(&b, "Date: %s\r\n", event.Time.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+
+	b.WriteString(event.Summary() + "\r\n\r\n")
+	for _, field := range [][2]string{
+		{"Kind", event.Kind},
+		{"Namespace", event.Namespace},
+		{"Name", event.Name},
+		{"Phase", event.Phase},
+		{"Previous phase", event.PreviousPhase},
+		{"Requested by", event.User},
+		{"Image", event.Image},
+		{"Duration", event.Duration},
+	} {
+		if field[1] != "" {
+			fmt.Fprintf(&b, "%s: %s\r\n", field[0], field[1])
+		}
+	}
+	return b.Bytes()
+}
+
+// sendMail is smtp.SendMail bounded by the context deadline
+func sendMail(ctx context.Context, addr, host string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func postJSON(ctx context.Context, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("POST returned %s", resp.Status)
+	}
+	return nil
+}
+
+// secretValue reads a key of a Secret in the notifier namespace
+func (n *Notifier) secretValue(ctx context.Context, name, key string) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := n.client.Get(ctx, k8stypes.NamespacedName{Name: name, Namespace: n.namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get notification secret %s: %w", name, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok || len(value) == 0 {
+		return nil, fmt.Errorf("notification secret %s has no %q key", name, key)
+	}
+	return value, nil
+}
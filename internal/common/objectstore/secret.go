@@ -0,0 +1,19 @@
+package objectstore
+
+import corev1 "k8s.io/api/core/v1"
+
+// Secret keys holding S3 credentials, matching the AWS environment variable names
+const (
+	SecretKeyAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	SecretKeySecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+	SecretKeySessionToken    = "AWS_SESSION_TOKEN"
+)
+
+// CredentialsFromSecret reads credentials from a secret holding the AWS environment variable keys
+func CredentialsFromSecret(secret *corev1.Secret) Credentials {
+	return Credentials{
+		AccessKeyID:     string(secret.Data[SecretKeyAccessKeyID]),
+		SecretAccessKey: string(secret.Data[SecretKeySecretAccessKey]),
+		SessionToken:    string(secret.Data[SecretKeySessionToken]),
+	}
+}
@@ -24,3 +24,18 @@ var PushArtifactScript string
 
 // BuildBuilderScript contains the embedded shell script for building the builder image.
 var BuildBuilderScript string
+
+//go:embed scripts/upload_artifact_s3.sh
+
+// UploadArtifactS3Script contains the embedded shell script for uploading artifacts to S3-compatible storage.
+var UploadArtifactS3Script string
+
+//go:embed scripts/fetch_delta_base.sh
+
+// FetchDeltaBaseScript contains the embedded shell script for pulling a delta base image from a registry.
+var FetchDeltaBaseScript string
+
+//go:embed scripts/create_delta.sh
+
+// CreateDeltaScript contains the embedded shell script for creating binary deltas between images.
+var CreateDeltaScript string
@@ -622,6 +622,17 @@ if [ -n "$final_name" ]; then
   echo "$final_name" > /tekton/results/artifact-filename || echo "Failed to write Tekton result"
   echo "Verifying Tekton result file:"
   cat /tekton/results/artifact-filename || echo "Failed to read Tekton result"
+
+  artifact_path="$(workspaces.shared-workspace.path)/${final_name}"
+  if [ -f "$artifact_path" ]; then
+    echo "Computing SHA-256 checksum of ${final_name}..."
+    artifact_sha256=$(sha256sum "$artifact_path" | cut -d' ' -f1)
+    echo "${artifact_sha256}  ${final_name}" > "${artifact_path}.sha256"
+    echo -n "$artifact_sha256" > /tekton/results/artifact-sha256 || echo "Failed to write checksum Tekton result"
+    artifact_size=$(stat -c %s "$artifact_path")
+    echo -n "$artifact_size" > /tekton/results/artifact-size || echo "Failed to write size Tekton result"
+    echo "SHA-256: ${artifact_sha256}, size: ${artifact_size} bytes"
+  fi
 else
   echo "Warning: final_name is empty, no artifact filename will be recorded"
 fi
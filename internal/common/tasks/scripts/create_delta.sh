@@ -0,0 +1,103 @@
+#!/bin/bash
+set -e
+
+# Trim whitespace/newlines from the filenames
+exportFile=$(echo "$(params.artifact-filename)" | tr -d '[:space:]')
+deltaFile=$(echo "$(params.delta-filename)" | tr -d '[:space:]')
+basePath="$(params.base-path)"
+method="$(params.method)"
+
+if [ -z "$exportFile" ] || [ ! -e "$exportFile" ]; then
+  echo "ERROR: Artifact file not found: ${exportFile:-<empty>}"
+  echo "Available files in workspace:"
+  ls -la /workspace/shared/
+  exit 1
+fi
+
+# A directory base holds the single file pulled from a registry
+if [ -d "$basePath" ]; then
+  basePath=$(find "$basePath" -maxdepth 1 -type f | head -n1)
+fi
+if [ -z "$basePath" ] || [ ! -f "$basePath" ]; then
+  echo "ERROR: Delta base not found: $(params.base-path)"
+  exit 1
+fi
+
+case "$method" in
+  zstd) packages="zstd" ;;
+  bsdiff) packages="bsdiff zstd lz4 xz" ;;
+  ostree) packages="ostree zstd lz4 xz tar" ;;
+  *)
+    echo "ERROR: Unsupported delta method: $method"
+    exit 1
+    ;;
+esac
+echo "Installing delta tools: $packages"
+dnf install -y --setopt=install_weak_deps=False $packages >/dev/null
+
+workDir="/workspace/shared/.delta-work"
+mkdir -p "$workDir"
+trap 'rm -rf "$workDir"' EXIT
+
+# decompress writes the uncompressed content of an artifact to stdout
+decompress() {
+  case "$1" in
+    *.gz) gzip -dc "$1" ;;
+    *.lz4) lz4 -dc "$1" ;;
+    *.xz) xz -dc "$1" ;;
+    *) cat "$1" ;;
+  esac
+}
+
+# extract_repo unpacks an ostree-commit export and prints the path of its ostree repository
+extract_repo() {
+  local archive="$1" dest="$2"
+  mkdir -p "$dest"
+  decompress "$archive" | tar -x -C "$dest"
+  find "$dest" -type d -name objects | while read -r objects; do
+    if [ -f "$(dirname "$objects")/config" ]; then
+      dirname "$objects"
+      break
+    fi
+  done
+}
+
+echo "Creating $method delta from $(basename "$basePath") to ${exportFile}"
+case "$method" in
+  zstd|bsdiff)
+    decompress "$basePath" > "$workDir/base.img"
+    decompress "$exportFile" > "$workDir/target.img"
+    if [ "$method" = "zstd" ]; then
+      # --long must cover the whole base image for --patch-from to find matches
+      zstd -q -f -19 --long=31 --patch-from="$workDir/base.img" "$workDir/target.img" -o "$deltaFile"
+      echo "Apply with: zstd -d --long=31 --patch-from=<base image> $deltaFile -o <image>"
+    else
+      bsdiff "$workDir/base.img" "$workDir/target.img" "$deltaFile"
+      echo "Apply with: bspatch <base image> <image> $deltaFile"
+    fi
+    ;;
+  ostree)
+    baseRepo=$(extract_repo "$basePath" "$workDir/base")
+    targetRepo=$(extract_repo "$exportFile" "$workDir/target")
+    if [ -z "$baseRepo" ] || [ -z "$targetRepo" ]; then
+      echo "ERROR: ostree deltas require ostree-commit exports for both builds"
+      exit 1
+    fi
+    baseRev=$(ostree --repo="$baseRepo" rev-parse "$(ostree --repo="$baseRepo" refs | head -n1)")
+    targetRev=$(ostree --repo="$targetRepo" rev-parse "$(ostree --repo="$targetRepo" refs | head -n1)")
+    echo "Base commit: $baseRev"
+    echo "Target commit: $targetRev"
+    ostree --repo="$targetRepo" pull-local "$baseRepo" "$baseRev"
+    ostree --repo="$targetRepo" static-delta generate \
+      --from="$baseRev" --to="$targetRev" --inline --min-fallback-size=0 \
+      --filename="$deltaFile"
+    echo "Apply with: ostree static-delta apply-offline $deltaFile"
+    ;;
+esac
+
+deltaSHA256=$(sha256sum "$deltaFile" | cut -d' ' -f1)
+echo "${deltaSHA256}  ${deltaFile}" > "${deltaFile}.sha256"
+echo -n "$deltaSHA256" > "$(results.delta-sha256.path)"
+
+echo "Delta size:" && ls -lah "$deltaFile"
+echo "SHA-256: ${deltaSHA256}"
@@ -0,0 +1,12 @@
+#!/bin/sh
+set -e
+
+baseDir="/workspace/shared/.delta-work/base"
+rm -rf "$baseDir"
+mkdir -p "$baseDir"
+
+echo "Pulling delta base from $(params.base-image)"
+oras pull --output "$baseDir" "$(params.base-image)"
+
+echo "Pulled files:"
+ls -la "$baseDir"
@@ -0,0 +1,70 @@
+#!/bin/sh
+set -e
+
+# Trim whitespace/newlines from the filename
+exportFile=$(echo "$(params.artifact-filename)" | tr -d '[:space:]')
+
+if [ -z "$exportFile" ]; then
+  echo "ERROR: artifact-filename param is empty"
+  echo "Available files in workspace:"
+  ls -la /workspace/shared/
+  exit 1
+fi
+
+if [ ! -f "$exportFile" ]; then
+  echo "ERROR: Artifact file not found: $exportFile"
+  echo "Available files in workspace:"
+  ls -la /workspace/shared/
+  exit 1
+fi
+
+keyPrefix="$(params.key-prefix)"
+destination="s3://$(params.bucket)/${keyPrefix}"
+
+# Path-style addressing keeps MinIO and other S3-compatible stores working
+aws configure set default.s3.addressing_style path
+
+awsArgs="--endpoint-url $(params.endpoint)"
+if [ "$(params.insecure)" = "true" ]; then
+  awsArgs="$awsArgs --no-verify-ssl"
+fi
+
+metaDir=$(mktemp -d)
+trap 'rm -rf "$metaDir"' EXIT
+
+echo "Computing checksum for ${exportFile}"
+sha256sum "$exportFile" > "${metaDir}/SHA256SUMS"
+checksum=$(cut -d' ' -f1 "${metaDir}/SHA256SUMS")
+size=$(wc -c < "$exportFile" | tr -d '[:space:]')
+
+cat > "${metaDir}/provenance.json" <<EOF
+{
+  "buildName": "$(params.build-name)",
+  "namespace": "$(context.taskRun.namespace)",
+  "distro": "$(params.distro)",
+  "target": "$(params.target)",
+  "architecture": "$(params.architecture)",
+  "exportFormat": "$(params.export-format)",
+  "artifact": {
+    "name": "${exportFile}",
+    "sha256": "${checksum}",
+    "sizeBytes": ${size}
+  },
+  "uploadedAt": "$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+}
+EOF
+
+echo "Uploading artifact to ${destination}/"
+echo "File: ${exportFile}"
+echo "SHA-256: ${checksum}"
+
+# shellcheck disable=SC2086
+aws $awsArgs s3 cp "$exportFile" "${destination}/${exportFile}" \
+  --metadata "sha256=${checksum}"
+# shellcheck disable=SC2086
+aws $awsArgs s3 cp "${metaDir}/SHA256SUMS" "${destination}/SHA256SUMS"
+# shellcheck disable=SC2086
+aws $awsArgs s3 cp "${metaDir}/provenance.json" "${destination}/provenance.json" \
+  --content-type application/json
+
+echo "Artifact uploaded successfully to object storage"
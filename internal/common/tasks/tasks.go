@@ -114,6 +114,245 @@ func GeneratePushArtifactRegistryTask(namespace string) *tektonv1.Task {
 	}
 }
 
+// GenerateUploadArtifactS3Task creates a Tekton Task for uploading artifacts, checksums and
+// provenance to S3-compatible object storage
+func GenerateUploadArtifactS3Task(namespace string) *tektonv1.Task {
+	return &tektonv1.Task{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "tekton.dev/v1",
+			Kind:       "Task",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "upload-artifact-s3",
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "automotive-dev-operator",
+				"app.kubernetes.io/part-of":    "automotive-dev",
+			},
+		},
+		Spec: tektonv1.TaskSpec{
+			Params: []tektonv1.ParamSpec{
+				{
+					Name:        "build-name",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Name of the ImageBuild that produced the artifact",
+				},
+				{
+					Name:        "distro",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Distribution that was built",
+				},
+				{
+					Name:        "target",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Build target",
+				},
+				{
+					Name:        "architecture",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Target architecture",
+				},
+				{
+					Name:        "export-format",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Export format for the build",
+				},
+				{
+					Name:        "endpoint",
+					Type:        tektonv1.ParamTypeString,
+					Description: "URL of the S3 endpoint",
+				},
+				{
+					Name:        "region",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Region of the bucket",
+					Default: &tektonv1.ParamValue{
+						Type:      tektonv1.ParamTypeString,
+						StringVal: "us-east-1",
+					},
+				},
+				{
+					Name:        "bucket",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Name of the bucket to upload to",
+				},
+				{
+					Name:        "key-prefix",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Object key prefix under which the artifact is stored",
+				},
+				{
+					Name:        "secret-ref",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Name of the secret containing AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY",
+				},
+				{
+					Name:        "insecure",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Skip TLS verification of the endpoint",
+					Default: &tektonv1.ParamValue{
+						Type:      tektonv1.ParamTypeString,
+						StringVal: "false",
+					},
+				},
+				{
+					Name:        "artifact-filename",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Filename of the artifact to upload",
+				},
+			},
+			Workspaces: []tektonv1.WorkspaceDeclaration{
+				{
+					Name:        "shared-workspace",
+					Description: "Workspace containing the build artifacts",
+					MountPath:   "/workspace/shared",
+				},
+			},
+			Steps: []tektonv1.Step{
+				{
+					Name:  "upload-artifact",
+					Image: "docker.io/amazon/aws-cli:2.17.0",
+					Env: []corev1.EnvVar{
+						{
+							Name:  "AWS_DEFAULT_REGION",
+							Value: "$(params.region)",
+						},
+					},
+					EnvFrom: []corev1.EnvFromSource{
+						{
+							SecretRef: &corev1.SecretEnvSource{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: "$(params.secret-ref)",
+								},
+							},
+						},
+					},
+					Script:     UploadArtifactS3Script,
+					WorkingDir: "/workspace/shared",
+				},
+			},
+		},
+	}
+}
+
+// GenerateCreateDeltaTask creates a Tekton Task that produces a binary delta between a base image
+// and the build's artifact. When baseImage is set, the base is pulled from that OCI reference
+// (with credentials from the dockerconfigjson secret baseAuthSecret, if any); otherwise it is read
+// from the base-workspace, which holds the workspace of the base ImageBuild.
+func GenerateCreateDeltaTask(namespace, baseImage, baseAuthSecret string) *tektonv1.Task {
+	task := &tektonv1.Task{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "tekton.dev/v1",
+			Kind:       "Task",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "create-delta",
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "automotive-dev-operator",
+				"app.kubernetes.io/part-of":    "automotive-dev",
+			},
+		},
+		Spec: tektonv1.TaskSpec{
+			Params: []tektonv1.ParamSpec{
+				{
+					Name:        "artifact-filename",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Filename of the artifact the delta leads to",
+				},
+				{
+					Name:        "base-path",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Path of the base artifact, or a directory holding the pulled base",
+				},
+				{
+					Name:        "delta-filename",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Filename of the delta placed in the shared workspace",
+				},
+				{
+					Name:        "method",
+					Type:        tektonv1.ParamTypeString,
+					Description: "Delta algorithm (zstd, bsdiff, ostree)",
+					Default: &tektonv1.ParamValue{
+						Type:      tektonv1.ParamTypeString,
+						StringVal: "zstd",
+					},
+				},
+			},
+			Results: []tektonv1.TaskResult{
+				{
+					Name:        "delta-sha256",
+					Description: "SHA-256 checksum of the delta file",
+				},
+			},
+			Workspaces: []tektonv1.WorkspaceDeclaration{
+				{
+					Name:        "shared-workspace",
+					Description: "Workspace containing the build artifacts",
+					MountPath:   "/workspace/shared",
+				},
+			},
+		},
+	}
+
+	if baseImage == "" {
+		task.Spec.Workspaces = append(task.Spec.Workspaces, tektonv1.WorkspaceDeclaration{
+			Name:        "base-workspace",
+			Description: "Workspace of the base ImageBuild",
+			MountPath:   "/workspace/base",
+			ReadOnly:    true,
+		})
+	} else {
+		task.Spec.Params = append(task.Spec.Params, tektonv1.ParamSpec{
+			Name:        "base-image",
+			Type:        tektonv1.ParamTypeString,
+			Description: "OCI reference of the base disk image artifact",
+		})
+		fetch := tektonv1.Step{
+			Name:       "fetch-base",
+			Image:      "ghcr.io/oras-project/oras:v1.2.0",
+			Script:     FetchDeltaBaseScript,
+			WorkingDir: "/workspace/shared",
+		}
+		if baseAuthSecret != "" {
+			fetch.Env = []corev1.EnvVar{
+				{
+					Name:  "DOCKER_CONFIG",
+					Value: "/docker-config",
+				},
+			}
+			fetch.VolumeMounts = []corev1.VolumeMount{
+				{
+					Name:      "docker-config",
+					MountPath: "/docker-config/config.json",
+					SubPath:   ".dockerconfigjson",
+				},
+			}
+			task.Spec.Volumes = []corev1.Volume{
+				{
+					Name: "docker-config",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: baseAuthSecret,
+						},
+					},
+				},
+			}
+		}
+		task.Spec.Steps = append(task.Spec.Steps, fetch)
+	}
+
+	task.Spec.Steps = append(task.Spec.Steps, tektonv1.Step{
+		Name:       "create-delta",
+		Image:      "quay.io/fedora/fedora:41",
+		Script:     CreateDeltaScript,
+		WorkingDir: "/workspace/shared",
+	})
+
+	return task
+}
+
 // GenerateBuildAutomotiveImageTask creates a Tekton Task for building automotive images
 func GenerateBuildAutomotiveImageTask(namespace string, buildConfig *BuildConfig, envSecretRef string) *tektonv1.Task {
 	task := &tektonv1.Task{
@@ -238,6 +477,14 @@ func GenerateBuildAutomotiveImageTask(namespace string, buildConfig *BuildConfig
 					Name:        "artifact-filename",
 					Description: "artifact filename placed in the shared workspace",
 				},
+				{
+					Name:        "artifact-sha256",
+					Description: "SHA-256 checksum of the artifact file",
+				},
+				{
+					Name:        "artifact-size",
+					Description: "size of the artifact file in bytes",
+				},
 			},
 			Workspaces: []tektonv1.WorkspaceDeclaration{
 				{
@@ -42,6 +42,14 @@ const (
 	AuditEventRemoved AuditEventType = "Removed"
 	// AuditEventAccessError indicates an access error occurred
 	AuditEventAccessError AuditEventType = "AccessError"
+	// AuditEventDeprecated indicates an image was marked deprecated
+	AuditEventDeprecated AuditEventType = "Deprecated"
+	// AuditEventEndOfLife indicates an image reached end-of-life
+	AuditEventEndOfLife AuditEventType = "EndOfLife"
+	// AuditEventReinstated indicates a deprecated or end-of-life image was made active again
+	AuditEventReinstated AuditEventType = "Reinstated"
+	// AuditEventDigestChanged indicates the tag of an image was moved to a different digest
+	AuditEventDigestChanged AuditEventType = "DigestChanged"
 )
 
 // AuditRecorder records audit events for CatalogImages
@@ -96,6 +104,30 @@ func (a *AuditRecorder) RecordAccessError(_ context.Context, catalogImage *autom
 		"Registry access error: %v", err)
 }
 
+// RecordLifecycleChanged records that an image's lifecycle state changed
+func (a *AuditRecorder) RecordLifecycleChanged(_ context.Context, catalogImage *automotivev1alpha1.CatalogImage) {
+	message := "Image is active again"
+	eventType, reason := corev1.EventTypeNormal, AuditEventReinstated
+	switch catalogImage.EffectiveLifecycleState() {
+	case automotivev1alpha1.LifecycleDeprecated:
+		message = "Image deprecated"
+		eventType, reason = corev1.EventTypeWarning, AuditEventDeprecated
+	case automotivev1alpha1.LifecycleEOL:
+		message = "Image reached end-of-life, periodic verification stopped"
+		eventType, reason = corev1.EventTypeWarning, AuditEventEndOfLife
+	}
+
+	if lifecycle := catalogImage.Spec.Lifecycle; lifecycle != nil && reason != AuditEventReinstated {
+		if lifecycle.Reason != "" {
+			message += ": " + lifecycle.Reason
+		}
+		if lifecycle.ReplacedBy != "" {
+			message += " (replaced by " + lifecycle.ReplacedBy + ")"
+		}
+	}
+	a.recorder.Event(catalogImage, eventType, string(reason), message)
+}
+
 // CatalogImageLister provides methods to list CatalogImages efficiently
 //
 //nolint:revive // Name intentionally includes resource type for clarity
@@ -161,3 +193,13 @@ func (l *CatalogImageLister) ExistsByRegistryURL(ctx context.Context, namespace,
 	}
 	return len(list.Items) > 0, nil
 }
+
+// RecordDigestChanged records that the image's tag moved to a different digest
+func (a *AuditRecorder) RecordDigestChanged(
+	_ context.Context,
+	catalogImage *automotivev1alpha1.CatalogImage,
+	previous, current string,
+) {
+	a.recorder.Eventf(catalogImage, corev1.EventTypeWarning, string(AuditEventDigestChanged),
+		"Tag moved from %s to %s", previous, current)
+}
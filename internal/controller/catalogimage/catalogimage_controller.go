@@ -25,11 +25,13 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	toolscache "k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/notify"
 )
 
 const (
@@ -47,11 +49,20 @@ type CatalogImageReconciler struct {
 	Scheme         *runtime.Scheme
 	Log            logr.Logger
 	RegistryClient RegistryClient
+	ImageMirrorer  ImageMirrorer
+	AuditRecorder  *AuditRecorder
+
+	// ConfigNamespace is the namespace of the OperatorConfig holding the catalog mirror policy
+	ConfigNamespace string
+
+	// Notifier sends catalog phase changes to the configured notification targets; nil disables notifications
+	Notifier *notify.Notifier
 }
 
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=catalogimages,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=catalogimages/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=catalogimages/finalizers,verbs=update
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=operatorconfigs,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
@@ -81,6 +92,10 @@ func (r *CatalogImageReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	log.Info("Reconciling CatalogImage", "phase", catalogImage.Status.Phase)
 
+	if changed, err := r.reconcileLifecycle(ctx, catalogImage); err != nil || changed {
+		return ctrl.Result{Requeue: changed}, err
+	}
+
 	// Handle reconciliation based on current phase
 	switch catalogImage.Status.Phase {
 	case "", automotivev1alpha1.CatalogImagePhasePending:
@@ -159,9 +174,10 @@ func (r *CatalogImageReconciler) handleVerifyingPhase(
 
 	// Get registry client
 	registryClient := r.getRegistryClient()
+	registryURL, verifyAuth, viaMirror := r.verificationSource(ctx, catalogImage, registryClient, auth)
 
 	// Verify image is accessible
-	accessible, err := registryClient.VerifyImageAccessible(ctx, catalogImage.Spec.RegistryURL, auth)
+	accessible, err := registryClient.VerifyImageAccessible(ctx, registryURL, verifyAuth)
 	if err != nil {
 		log.Error(err, "Failed to access registry")
 		return r.transitionToUnavailable(ctx, catalogImage, "RegistryAccessFailed", err.Error())
@@ -172,13 +188,27 @@ func (r *CatalogImageReconciler) handleVerifyingPhase(
 	}
 
 	// Extract metadata from registry
-	metadata, err := registryClient.GetImageMetadata(ctx, catalogImage.Spec.RegistryURL, auth)
+	metadata, err := registryClient.GetImageMetadata(ctx, registryURL, verifyAuth)
 	if err != nil {
 		log.Error(err, "Failed to get image metadata")
 		// Still transition to Available if image is accessible but metadata extraction fails
 		log.Info("Image accessible but metadata extraction failed, continuing")
 	}
 
+	// Track tag drift and copy the image into the mirror registry, unless the upstream registry is
+	// unreachable
+	var previousDigest string
+	if !viaMirror {
+		digest := catalogImage.Spec.Digest
+		if metadata != nil && metadata.ResolvedDigest != "" {
+			previousDigest = r.trackDigest(catalogImage, metadata.ResolvedDigest)
+			if digest == "" {
+				digest = metadata.ResolvedDigest
+			}
+		}
+		r.reconcileMirror(ctx, catalogImage, digest, auth)
+	}
+
 	// Update status with metadata and transition to Available
 	catalogImage.Status.RegistryMetadata = metadata
 	catalogImage.Status.LastVerificationTime = GetCurrentTime()
@@ -200,12 +230,16 @@ func (r *CatalogImageReconciler) handleVerifyingPhase(
 		"RegistryAccessible",
 		"Image is accessible in registry",
 	)
+	verifiedReason, verifiedMessage := "VerificationSucceeded", "Image verification completed successfully"
+	if viaMirror {
+		verifiedReason, verifiedMessage = "VerifiedFromMirror", "Upstream registry is unreachable, image verified in mirror"
+	}
 	r.setCondition(
 		catalogImage,
 		automotivev1alpha1.CatalogImageConditionVerified,
 		metav1.ConditionTrue,
-		"VerificationSucceeded",
-		"Image verification completed successfully",
+		verifiedReason,
+		verifiedMessage,
 	)
 	r.setCondition(
 		catalogImage,
@@ -218,6 +252,17 @@ func (r *CatalogImageReconciler) handleVerifyingPhase(
 	if err := r.Status().Update(ctx, catalogImage); err != nil {
 		return ctrl.Result{}, err
 	}
+	if previousDigest != "" {
+		log.Info("Tag moved to a different digest", "previous", previousDigest,
+			"current", catalogImage.Status.RegistryMetadata.ResolvedDigest)
+		if r.AuditRecorder != nil {
+			r.AuditRecorder.RecordDigestChanged(ctx, catalogImage, previousDigest,
+				catalogImage.Status.RegistryMetadata.ResolvedDigest)
+		}
+	}
+	if err := r.pinFirstSeenDigest(ctx, catalogImage); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// Requeue for periodic verification
 	return ctrl.Result{RequeueAfter: r.getVerificationInterval(catalogImage)}, nil
@@ -230,6 +275,11 @@ func (r *CatalogImageReconciler) handleAvailablePhase(
 ) (ctrl.Result, error) {
 	log := r.Log.WithValues("catalogimage", catalogImage.Name, "namespace", catalogImage.Namespace)
 
+	// End-of-life images are no longer verified
+	if catalogImage.EffectiveLifecycleState() == automotivev1alpha1.LifecycleEOL {
+		return r.observeEOLGeneration(ctx, catalogImage)
+	}
+
 	// Check if spec changed (generation mismatch)
 	if catalogImage.Status.ObservedGeneration != catalogImage.Generation {
 		log.Info("Spec changed, re-verifying")
@@ -270,6 +320,12 @@ func (r *CatalogImageReconciler) handleUnavailablePhase(
 	catalogImage *automotivev1alpha1.CatalogImage,
 ) (ctrl.Result, error) {
 	log := r.Log.WithValues("catalogimage", catalogImage.Name, "namespace", catalogImage.Namespace)
+
+	// End-of-life images are not retried
+	if catalogImage.EffectiveLifecycleState() == automotivev1alpha1.LifecycleEOL {
+		return r.observeEOLGeneration(ctx, catalogImage)
+	}
+
 	log.Info("Retrying unavailable image")
 
 	// Transition back to Verifying to retry
@@ -357,6 +413,45 @@ func (r *CatalogImageReconciler) setCondition(
 	})
 }
 
+// reconcileLifecycle records changes of the image's lifecycle state and emits an audit event for them.
+// It reports whether the status was updated.
+func (r *CatalogImageReconciler) reconcileLifecycle(
+	ctx context.Context,
+	catalogImage *automotivev1alpha1.CatalogImage,
+) (bool, error) {
+	state := catalogImage.EffectiveLifecycleState()
+	observed := catalogImage.Status.LifecycleState
+	if observed == "" {
+		observed = automotivev1alpha1.LifecycleActive
+	}
+	if state == observed {
+		return false, nil
+	}
+
+	r.Log.Info("Lifecycle state changed", "catalogimage", catalogImage.Name, "namespace", catalogImage.Namespace,
+		"from", observed, "to", state)
+	catalogImage.Status.LifecycleState = state
+	if err := r.Status().Update(ctx, catalogImage); err != nil {
+		return false, err
+	}
+	if r.AuditRecorder != nil {
+		r.AuditRecorder.RecordLifecycleChanged(ctx, catalogImage)
+	}
+	return true, nil
+}
+
+// observeEOLGeneration acknowledges spec changes of an end-of-life image without verifying it
+func (r *CatalogImageReconciler) observeEOLGeneration(
+	ctx context.Context,
+	catalogImage *automotivev1alpha1.CatalogImage,
+) (ctrl.Result, error) {
+	if catalogImage.Status.ObservedGeneration == catalogImage.Generation {
+		return ctrl.Result{}, nil
+	}
+	catalogImage.Status.ObservedGeneration = catalogImage.Generation
+	return ctrl.Result{}, r.Status().Update(ctx, catalogImage)
+}
+
 // ensureLabels ensures that labels are set based on metadata
 func (r *CatalogImageReconciler) ensureLabels(catalogImage *automotivev1alpha1.CatalogImage) {
 	if catalogImage.Labels == nil {
@@ -429,7 +524,34 @@ func (r *CatalogImageReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return fmt.Errorf("failed to create field index for status.phase: %w", err)
 	}
 
+	if r.Notifier != nil {
+		informer, err := mgr.GetCache().GetInformer(context.Background(), &automotivev1alpha1.CatalogImage{})
+		if err != nil {
+			return fmt.Errorf("failed to get CatalogImage informer for notifications: %w", err)
+		}
+		if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			UpdateFunc: r.notifyPhaseChange,
+		}); err != nil {
+			return fmt.Errorf("failed to watch CatalogImage phase changes: %w", err)
+		}
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&automotivev1alpha1.CatalogImage{}).
 		Complete(r)
 }
+
+// notifyPhaseChange sends a notification when a CatalogImage moves to another phase
+func (r *CatalogImageReconciler) notifyPhaseChange(oldObj, newObj any) {
+	previous, ok := oldObj.(*automotivev1alpha1.CatalogImage)
+	if !ok {
+		return
+	}
+	catalogImage, ok := newObj.(*automotivev1alpha1.CatalogImage)
+	if !ok {
+		return
+	}
+	if event, changed := notify.CatalogPhaseChange(previous, catalogImage); changed {
+		r.Notifier.Notify(event)
+	}
+}
@@ -327,3 +327,15 @@ func (c *CircuitBreakerRegistryClient) VerifyDigest(
 	c.breakers.RecordSuccess(registryURL)
 	return match, digest, nil
 }
+
+// Route returns the mirror reference while the circuit of the upstream registry is open and a mirror
+// is available, and the upstream reference otherwise
+func (c *CircuitBreakerRegistryClient) Route(upstreamURL, mirrorURL string) string {
+	if mirrorURL == "" {
+		return upstreamURL
+	}
+	if canAttempt, _ := c.breakers.CanAttempt(upstreamURL); canAttempt {
+		return upstreamURL
+	}
+	return mirrorURL
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalogimage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// maxDigestHistory bounds the number of digests kept in the status of a catalog image
+const maxDigestHistory = 10
+
+// trackDigest records the digest the image's tag resolved to in its digest history and DigestChanged
+// condition. It returns the previously resolved digest if the tag has moved, or an empty string.
+func (r *CatalogImageReconciler) trackDigest(catalogImage *automotivev1alpha1.CatalogImage, digest string) string {
+	// Images referenced by digest cannot drift
+	if digest == "" || strings.Contains(catalogImage.Spec.RegistryURL, "@") {
+		return ""
+	}
+
+	history := catalogImage.Status.DigestHistory
+	if len(history) > 0 && history[len(history)-1].Digest == digest {
+		return ""
+	}
+
+	history = append(history, automotivev1alpha1.DigestRecord{Digest: digest, FirstSeen: *GetCurrentTime()})
+	if len(history) > maxDigestHistory {
+		history = history[len(history)-maxDigestHistory:]
+	}
+	catalogImage.Status.DigestHistory = history
+
+	if len(history) == 1 {
+		r.setCondition(catalogImage, automotivev1alpha1.CatalogImageConditionDigestChanged, metav1.ConditionFalse,
+			"DigestUnchanged", "Tag resolves to the first digest seen")
+		return ""
+	}
+
+	previous := history[len(history)-2].Digest
+	message := fmt.Sprintf("Tag moved from %s to %s", previous, digest)
+	if pinned := catalogImage.Spec.Digest; pinned != "" {
+		message += fmt.Sprintf("; the image remains pinned to %s", pinned)
+	}
+	r.setCondition(catalogImage, automotivev1alpha1.CatalogImageConditionDigestChanged, metav1.ConditionTrue,
+		"TagMoved", message)
+	return previous
+}
+
+// pinFirstSeenDigest sets the image's digest to the first digest its tag resolved to when it asks for
+// its digest to be pinned
+func (r *CatalogImageReconciler) pinFirstSeenDigest(
+	ctx context.Context,
+	catalogImage *automotivev1alpha1.CatalogImage,
+) error {
+	history := catalogImage.Status.DigestHistory
+	if !catalogImage.Spec.PinDigest || catalogImage.Spec.Digest != "" || len(history) == 0 {
+		return nil
+	}
+
+	r.Log.Info("Pinning first-seen digest", "catalogimage", catalogImage.Name, "namespace", catalogImage.Namespace,
+		"digest", history[0].Digest)
+	catalogImage.Spec.Digest = history[0].Digest
+	if err := r.Update(ctx, catalogImage); err != nil {
+		return fmt.Errorf("failed to pin digest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalogimage
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+// ImageMirrorer copies images together with the referrers attached to them
+type ImageMirrorer interface {
+	ImageCopier
+
+	// ListTags lists the tags of a registry repository
+	ListTags(ctx context.Context, repository string, auth *types.DockerAuthConfig) ([]string, error)
+}
+
+// ListTags lists the tags of a registry repository using containers/image
+func (c *DefaultRegistryClient) ListTags(
+	ctx context.Context,
+	repository string,
+	auth *types.DockerAuthConfig,
+) ([]string, error) {
+	ref, err := docker.ParseReference("//" + repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse repository: %w", err)
+	}
+	tags, err := docker.GetRepositoryTags(ctx, &types.SystemContext{DockerAuthConfig: auth}, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return tags, nil
+}
+
+// MirrorTarget is the repository a catalog image is mirrored into
+type MirrorTarget struct {
+	// Repository is the mirror repository, without tag
+	Repository string
+	// AuthSecretRef references the credentials used to push to the mirror repository
+	AuthSecretRef *automotivev1alpha1.AuthSecretReference
+	// AuthNamespace is the namespace of AuthSecretRef when it does not set one
+	AuthNamespace string
+}
+
+// ResolveMirror returns where a catalog image is mirrored to under its own mirror settings and the
+// OperatorConfig mirror policy, or nil if the image is not mirrored
+func ResolveMirror(
+	catalogImage *automotivev1alpha1.CatalogImage,
+	config *automotivev1alpha1.OperatorConfig,
+) (*MirrorTarget, error) {
+	var policy *automotivev1alpha1.CatalogMirrorPolicy
+	if config != nil && config.Spec.Catalog != nil {
+		policy = config.Spec.Catalog.Mirror
+	}
+
+	mirror := catalogImage.Spec.Mirror
+	switch {
+	case mirror != nil && mirror.Disabled:
+		return nil, nil
+	case mirror != nil && mirror.RepositoryURL != "":
+		repo, err := mirrorRepository(mirror.RepositoryURL)
+		if err != nil {
+			return nil, err
+		}
+		return &MirrorTarget{
+			Repository:    repo,
+			AuthSecretRef: mirror.AuthSecretRef,
+			AuthNamespace: catalogImage.Namespace,
+		}, nil
+	case policy == nil:
+		if mirror != nil {
+			return nil, fmt.Errorf("spec.mirror needs a repositoryUrl when the OperatorConfig has no mirror policy")
+		}
+		return nil, nil
+	case mirror == nil && !policy.MirrorAll:
+		return nil, nil
+	}
+
+	base, err := mirrorRepository(policy.RepositoryURL)
+	if err != nil {
+		return nil, err
+	}
+	source, err := reference.ParseNormalizedNamed(catalogImage.Spec.RegistryURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry URL of %s: %w", catalogImage.Name, err)
+	}
+	target := &MirrorTarget{
+		Repository:    base + "/" + path.Base(reference.Path(source)),
+		AuthSecretRef: policy.AuthSecretRef,
+		AuthNamespace: config.Namespace,
+	}
+	if mirror != nil && mirror.AuthSecretRef != nil {
+		target.AuthSecretRef = mirror.AuthSecretRef
+		target.AuthNamespace = catalogImage.Namespace
+	}
+	return target, nil
+}
+
+// mirrorRepository validates and normalizes a mirror repository URL
+func mirrorRepository(repositoryURL string) (string, error) {
+	repo, err := reference.ParseNormalizedNamed(strings.TrimSuffix(strings.TrimSpace(repositoryURL), "/"))
+	if err != nil {
+		return "", fmt.Errorf("invalid mirror repository %q: %w", repositoryURL, err)
+	}
+	if !reference.IsNameOnly(repo) {
+		return "", fmt.Errorf("mirror repository %q must not contain a tag or digest", repositoryURL)
+	}
+	return repo.Name(), nil
+}
+
+// referrerTagPrefix returns the tag prefix under which signatures, attestations and SBOMs of the
+// manifest with the given digest are stored: the cosign tag scheme and the OCI referrers tag schema
+func referrerTagPrefix(digest string) string {
+	return strings.Replace(digest, ":", "-", 1)
+}
+
+// PullReference returns the reference clients should pull a catalog image from, preferring its mirror
+func PullReference(catalogImage *automotivev1alpha1.CatalogImage) string {
+	if mirror := catalogImage.MirrorArtifact(); mirror != nil {
+		return mirror.URL
+	}
+	return PinnedReference(catalogImage)
+}
+
+// mirrorTarget resolves the mirror target of the image against the OperatorConfig
+func (r *CatalogImageReconciler) mirrorTarget(
+	ctx context.Context,
+	catalogImage *automotivev1alpha1.CatalogImage,
+) (*MirrorTarget, error) {
+	var config *automotivev1alpha1.OperatorConfig
+	if r.ConfigNamespace != "" {
+		config = &automotivev1alpha1.OperatorConfig{}
+		key := k8stypes.NamespacedName{Name: "config", Namespace: r.ConfigNamespace}
+		if err := r.Get(ctx, key, config); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get OperatorConfig: %w", err)
+			}
+			config = nil
+		}
+	}
+	return ResolveMirror(catalogImage, config)
+}
+
+// reconcileMirror copies the verified image and its referrers into the mirror registry when mirroring
+// is configured, and records the copy in the image's artifact references. Failures are reported in the
+// Mirrored condition and do not affect the availability of the image.
+func (r *CatalogImageReconciler) reconcileMirror(
+	ctx context.Context,
+	catalogImage *automotivev1alpha1.CatalogImage,
+	digest string,
+	sourceAuth *types.DockerAuthConfig,
+) {
+	log := r.Log.WithValues("catalogimage", catalogImage.Name, "namespace", catalogImage.Namespace)
+
+	target, err := r.mirrorTarget(ctx, catalogImage)
+	if err != nil {
+		log.Error(err, "Failed to resolve mirror")
+		r.setCondition(catalogImage, automotivev1alpha1.CatalogImageConditionMirrored, metav1.ConditionFalse,
+			"InvalidMirror", err.Error())
+		return
+	}
+	if target == nil {
+		removeMirrorArtifact(catalogImage)
+		meta.RemoveStatusCondition(&catalogImage.Status.Conditions, automotivev1alpha1.CatalogImageConditionMirrored)
+		return
+	}
+	if digest == "" {
+		r.setCondition(catalogImage, automotivev1alpha1.CatalogImageConditionMirrored, metav1.ConditionFalse,
+			"DigestUnknown", "Image digest could not be resolved")
+		return
+	}
+	if existing := catalogImage.MirrorArtifact(); existing != nil && existing.Digest == digest &&
+		strings.HasPrefix(existing.URL, target.Repository+"@") {
+		return
+	}
+
+	destAuth, err := GetAuthFromSecret(ctx, r.Client, target.AuthSecretRef, target.AuthNamespace)
+	if err != nil {
+		log.Error(err, "Failed to get mirror credentials")
+		r.setCondition(catalogImage, automotivev1alpha1.CatalogImageConditionMirrored, metav1.ConditionFalse,
+			"AuthenticationError", err.Error())
+		return
+	}
+
+	log.Info("Mirroring image", "repository", target.Repository, "digest", digest)
+	artifact, referrers, err := r.mirrorImage(ctx, catalogImage, target, digest, sourceAuth, destAuth)
+	if err != nil {
+		log.Error(err, "Failed to mirror image", "repository", target.Repository)
+		r.setCondition(catalogImage, automotivev1alpha1.CatalogImageConditionMirrored, metav1.ConditionFalse,
+			"MirrorFailed", err.Error())
+		return
+	}
+
+	removeMirrorArtifact(catalogImage)
+	catalogImage.Status.ArtifactRefs = append(catalogImage.Status.ArtifactRefs, *artifact)
+	r.setCondition(catalogImage, automotivev1alpha1.CatalogImageConditionMirrored, metav1.ConditionTrue,
+		"MirrorSynced", fmt.Sprintf("Mirrored to %s with %d referrers", artifact.URL, referrers))
+}
+
+// mirrorImage copies the image with the given digest and the referrers stored under its digest tags
+// into the mirror repository. It returns the artifact reference of the copy and the number of referrers.
+func (r *CatalogImageReconciler) mirrorImage(
+	ctx context.Context,
+	catalogImage *automotivev1alpha1.CatalogImage,
+	target *MirrorTarget,
+	digest string,
+	sourceAuth, destAuth *types.DockerAuthConfig,
+) (*automotivev1alpha1.ArtifactReference, int, error) {
+	source, err := reference.ParseNormalizedNamed(catalogImage.Spec.RegistryURL)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid registry URL: %w", err)
+	}
+	sourceRepo := source.Name()
+
+	dest, err := referenceInRepository(catalogImage, target.Repository)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	mirrorer := r.getImageMirrorer()
+	mirrorDigest, err := mirrorer.CopyImage(ctx, sourceRepo+"@"+digest, sourceAuth, dest, destAuth)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tags, err := mirrorer.ListTags(ctx, sourceRepo, sourceAuth)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list referrers: %w", err)
+	}
+	prefix := referrerTagPrefix(digest)
+	referrers := 0
+	for _, tag := range tags {
+		if tag != prefix && !strings.HasPrefix(tag, prefix+".") {
+			continue
+		}
+		if _, err := mirrorer.CopyImage(ctx, sourceRepo+":"+tag, sourceAuth, target.Repository+":"+tag,
+			destAuth); err != nil {
+			return nil, 0, fmt.Errorf("failed to copy referrer %s: %w", tag, err)
+		}
+		referrers++
+	}
+
+	return &automotivev1alpha1.ArtifactReference{
+		Type:   "container",
+		URL:    target.Repository + "@" + mirrorDigest,
+		Digest: mirrorDigest,
+		Format: automotivev1alpha1.ArtifactFormatMirror,
+	}, referrers, nil
+}
+
+// removeMirrorArtifact removes the mirror entry from the image's artifact references
+func removeMirrorArtifact(catalogImage *automotivev1alpha1.CatalogImage) {
+	refs := catalogImage.Status.ArtifactRefs[:0]
+	for _, ref := range catalogImage.Status.ArtifactRefs {
+		if ref.Format != automotivev1alpha1.ArtifactFormatMirror {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) == 0 {
+		refs = nil
+	}
+	catalogImage.Status.ArtifactRefs = refs
+}
+
+// getImageMirrorer returns the image mirrorer (allows for testing)
+func (r *CatalogImageReconciler) getImageMirrorer() ImageMirrorer {
+	if r.ImageMirrorer != nil {
+		return r.ImageMirrorer
+	}
+	return NewRegistryClient()
+}
+
+// verificationSource returns the reference and credentials the image is verified with, and whether they
+// are those of its mirror. Verification is routed to the mirror while the upstream circuit is open.
+func (r *CatalogImageReconciler) verificationSource(
+	ctx context.Context,
+	catalogImage *automotivev1alpha1.CatalogImage,
+	registryClient RegistryClient,
+	auth *types.DockerAuthConfig,
+) (string, *types.DockerAuthConfig, bool) {
+	upstream := catalogImage.Spec.RegistryURL
+	breaker, ok := registryClient.(*CircuitBreakerRegistryClient)
+	mirror := catalogImage.MirrorArtifact()
+	if !ok || mirror == nil || breaker.Route(upstream, mirror.URL) == upstream {
+		return upstream, auth, false
+	}
+
+	log := r.Log.WithValues("catalogimage", catalogImage.Name, "namespace", catalogImage.Namespace)
+	target, err := r.mirrorTarget(ctx, catalogImage)
+	if err != nil || target == nil {
+		log.Info("Upstream circuit is open but the mirror is no longer configured", "registryUrl", upstream)
+		return upstream, auth, false
+	}
+	mirrorAuth, err := GetAuthFromSecret(ctx, r.Client, target.AuthSecretRef, target.AuthNamespace)
+	if err != nil {
+		log.Error(err, "Failed to get mirror credentials")
+		return upstream, auth, false
+	}
+	log.Info("Upstream circuit is open, verifying through mirror", "registryUrl", upstream, "mirror", mirror.URL)
+	return mirror.URL, mirrorAuth, true
+}
@@ -0,0 +1,455 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalogimage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+var (
+	// ErrAlreadyInChannel is returned when an image is promoted into the channel it is already in
+	ErrAlreadyInChannel = errors.New("image is already in the requested channel")
+	// ErrApprovalPending is returned when the requester of a protected promotion tries to approve it
+	ErrApprovalPending = errors.New("promotion into a protected channel must be approved by a second user")
+	// ErrPromotionTargetExists is returned when the promoted CatalogImage already exists
+	ErrPromotionTargetExists = errors.New("promoted catalog image already exists")
+	// ErrPromotionSourceChanged is returned when a protected promotion is approved after the image
+	// moved to a different digest than the one requested
+	ErrPromotionSourceChanged = errors.New("image changed since the promotion was requested, request it again")
+)
+
+// ImageCopier copies images between registries
+type ImageCopier interface {
+	// CopyImage copies an image with all its platform variants and returns the destination manifest digest
+	CopyImage(
+		ctx context.Context,
+		srcURL string,
+		srcAuth *types.DockerAuthConfig,
+		destURL string,
+		destAuth *types.DockerAuthConfig,
+	) (string, error)
+}
+
+// CopyImage copies an image from one registry reference to another using containers/image
+func (c *DefaultRegistryClient) CopyImage(
+	ctx context.Context,
+	srcURL string,
+	srcAuth *types.DockerAuthConfig,
+	destURL string,
+	destAuth *types.DockerAuthConfig,
+) (string, error) {
+	srcRef, err := docker.ParseReference("//" + srcURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse source reference: %w", err)
+	}
+	destRef, err := docker.ParseReference("//" + destURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse destination reference: %w", err)
+	}
+
+	// Signatures are not verified here: the source is already a trusted catalog entry
+	policyContext, err := signature.NewPolicyContext(&signature.Policy{
+		Default: signature.PolicyRequirements{signature.NewPRInsecureAcceptAnything()},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create signature policy: %w", err)
+	}
+	defer func() {
+		_ = policyContext.Destroy()
+	}()
+
+	manifestBytes, err := copy.Image(ctx, policyContext, destRef, srcRef, &copy.Options{
+		SourceCtx:          &types.SystemContext{DockerAuthConfig: srcAuth},
+		DestinationCtx:     &types.SystemContext{DockerAuthConfig: destAuth},
+		ImageListSelection: copy.CopyAllImages,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to copy image: %w", err)
+	}
+
+	digest, err := manifest.Digest(manifestBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute digest: %w", err)
+	}
+	return digest.String(), nil
+}
+
+// FindChannel returns the promotion channel with the given name from the operator configuration
+func FindChannel(config *automotivev1alpha1.OperatorConfig, name string) *automotivev1alpha1.PromotionChannel {
+	if config == nil || config.Spec.Catalog == nil {
+		return nil
+	}
+	for i := range config.Spec.Catalog.Channels {
+		if config.Spec.Catalog.Channels[i].Name == name {
+			return &config.Spec.Catalog.Channels[i]
+		}
+	}
+	return nil
+}
+
+// PinnedReference returns the registry reference of a catalog image, pinned to its digest when known
+func PinnedReference(catalogImage *automotivev1alpha1.CatalogImage) string {
+	ref := catalogImage.Spec.RegistryURL
+	if catalogImage.Spec.Digest == "" || strings.Contains(ref, "@") {
+		return ref
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+	return ref + "@" + catalogImage.Spec.Digest
+}
+
+// ResolvedDigest returns the manifest digest a catalog image was last verified at, falling back to
+// the digest in its spec, or an empty string if neither is known
+func ResolvedDigest(catalogImage *automotivev1alpha1.CatalogImage) string {
+	if metadata := catalogImage.Status.RegistryMetadata; metadata != nil && metadata.ResolvedDigest != "" {
+		return metadata.ResolvedDigest
+	}
+	return catalogImage.Spec.Digest
+}
+
+// digestReference returns the reference of a digest in a catalog image's repository
+func digestReference(catalogImage *automotivev1alpha1.CatalogImage, digest string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(catalogImage.Spec.RegistryURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid registry URL of %s: %w", catalogImage.Name, err)
+	}
+	return named.Name() + "@" + digest, nil
+}
+
+// PromotedName returns the default name of the CatalogImage created by promoting source into channel
+func PromotedName(source *automotivev1alpha1.CatalogImage, channel string) string {
+	base := source.Name
+	if source.Spec.Channel != "" {
+		base = strings.TrimSuffix(base, "-"+source.Spec.Channel)
+	}
+	return base + "-" + channel
+}
+
+// promotedReference returns the reference the source image is copied to in the channel repository.
+// The source tag is kept; images referenced by digest only are tagged after their digest.
+func promotedReference(
+	source *automotivev1alpha1.CatalogImage,
+	channel *automotivev1alpha1.PromotionChannel,
+) (string, error) {
+	repo, err := reference.ParseNormalizedNamed(strings.TrimSpace(channel.RepositoryURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid repository URL of channel %s: %w", channel.Name, err)
+	}
+	if !reference.IsNameOnly(repo) {
+		return "", fmt.Errorf("repository URL of channel %s must not contain a tag or digest", channel.Name)
+	}
+
+	return referenceInRepository(source, repo.Name())
+}
+
+// referenceInRepository returns the reference of the source image's tag in another repository.
+// Images referenced by digest only are tagged after their digest.
+func referenceInRepository(source *automotivev1alpha1.CatalogImage, repository string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(source.Spec.RegistryURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid registry URL of %s: %w", source.Name, err)
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		return repository + ":" + tagged.Tag(), nil
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		d := digested.Digest()
+		return fmt.Sprintf("%s:%s-%s", repository, d.Algorithm(), d.Encoded()), nil
+	}
+	return repository + ":latest", nil
+}
+
+// PromoteOptions contains options for promoting a catalog image into a channel
+type PromoteOptions struct {
+	// Source is the CatalogImage to promote
+	Source *automotivev1alpha1.CatalogImage
+	// Channel is the channel to promote into
+	Channel *automotivev1alpha1.PromotionChannel
+	// TargetName is the name of the promoted CatalogImage, defaults to PromotedName
+	TargetName string
+	// Requester is the user requesting or approving the promotion
+	Requester string
+	// ConfigNamespace is the namespace of the OperatorConfig defining the channel, where the
+	// channel's auth secret is looked up unless its reference names a namespace
+	ConfigNamespace string
+}
+
+// PromoteResult contains the result of a promotion
+type PromoteResult struct {
+	// CatalogImage is the promoted CatalogImage, nil while approval is pending
+	CatalogImage *automotivev1alpha1.CatalogImage
+	// Pending indicates the promotion was recorded and awaits a second approver
+	Pending bool
+	// Record is the promotion history entry of a completed promotion
+	Record *automotivev1alpha1.PromotionRecord
+}
+
+// Promote copies a catalog image into a channel's repository and publishes it as a new CatalogImage.
+// Promotions into protected channels are first recorded as pending on the source image and carried
+// out when a different user promotes it again.
+func (p *Publisher) Promote(ctx context.Context, opts PromoteOptions) (*PromoteResult, error) {
+	source, channel := opts.Source, opts.Channel
+	log := p.log.WithValues("name", source.Name, "namespace", source.Namespace, "channel", channel.Name)
+
+	if source.Spec.Channel == channel.Name {
+		return nil, ErrAlreadyInChannel
+	}
+	// The image is copied by the digest it was verified at, never by its current tag
+	sourceDigest := ResolvedDigest(source)
+	if sourceDigest == "" {
+		return nil, fmt.Errorf("catalog image %s has no resolved digest to promote", source.Name)
+	}
+
+	targetName := opts.TargetName
+	record := automotivev1alpha1.PromotionRecord{
+		SourceImage: source.Name,
+		FromChannel: source.Spec.Channel,
+		Channel:     channel.Name,
+		PromotedBy:  opts.Requester,
+	}
+
+	if channel.Protected {
+		pending := PendingPromotionFor(source, channel.Name)
+		if pending == nil {
+			if targetName == "" {
+				targetName = PromotedName(source, channel.Name)
+			}
+			err := p.recordPendingPromotion(ctx, source, channel.Name, targetName, sourceDigest, opts.Requester)
+			if err != nil {
+				return nil, err
+			}
+			log.Info("Promotion into protected channel awaits approval",
+				"requestedBy", opts.Requester, "digest", sourceDigest)
+			return &PromoteResult{Pending: true}, nil
+		}
+		if pending.RequestedBy == opts.Requester {
+			return nil, ErrApprovalPending
+		}
+		if pending.Digest != sourceDigest {
+			// The requester vouched for a different image: drop the stale request so it is repeated
+			log.Info("Refusing approval of promotion whose image changed",
+				"requested", pending.Digest, "current", sourceDigest)
+			if err := p.clearPendingPromotion(ctx, source, channel.Name); err != nil {
+				log.Error(err, "Failed to clear stale pending promotion")
+			}
+			return nil, ErrPromotionSourceChanged
+		}
+		record.PromotedBy = pending.RequestedBy
+		record.ApprovedBy = opts.Requester
+		if pending.TargetName != "" {
+			targetName = pending.TargetName
+		}
+	}
+
+	if targetName == "" {
+		targetName = PromotedName(source, channel.Name)
+	}
+	if errs := validation.IsDNS1123Subdomain(targetName); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid promoted image name %q: %s", targetName, strings.Join(errs, ", "))
+	}
+
+	existing := &automotivev1alpha1.CatalogImage{}
+	err := p.client.Get(ctx, k8stypes.NamespacedName{Name: targetName, Namespace: source.Namespace}, existing)
+	if err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPromotionTargetExists, targetName)
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to check for promoted image: %w", err)
+	}
+
+	destURL, err := promotedReference(source, channel)
+	if err != nil {
+		return nil, err
+	}
+	srcAuth, err := GetAuthFromSecret(ctx, p.client, source.Spec.AuthSecretRef, source.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source credentials: %w", err)
+	}
+	// The promoted image lives in the source namespace, so it references the channel's secret with
+	// its namespace made explicit
+	var authRef *automotivev1alpha1.AuthSecretReference
+	if channel.AuthSecretRef != nil {
+		authRef = channel.AuthSecretRef.DeepCopy()
+		if authRef.Namespace == "" {
+			authRef.Namespace = opts.ConfigNamespace
+		}
+	}
+	destAuth, err := GetAuthFromSecret(ctx, p.client, authRef, opts.ConfigNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials of channel %s: %w", channel.Name, err)
+	}
+
+	copier, ok := p.registryClient.(ImageCopier)
+	if !ok {
+		copier = NewRegistryClient()
+	}
+	srcURL, err := digestReference(source, sourceDigest)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Copying image into channel repository", "source", srcURL, "destination", destURL)
+	digest, err := copier.CopyImage(ctx, srcURL, srcAuth, destURL, destAuth)
+	if err != nil {
+		return nil, err
+	}
+	record.SourceDigest = digest
+
+	var metadata *automotivev1alpha1.CatalogImageMetadata
+	if source.Spec.Metadata != nil {
+		metadata = source.Spec.Metadata.DeepCopy()
+	}
+	// The promoted image is created directly rather than through Publish: it was just pushed by
+	// digest, and the controller verifies it as for any new catalog entry
+	promoted := p.buildCatalogImage(PublishOptions{
+		Name:                 targetName,
+		Namespace:            source.Namespace,
+		RegistryURL:          destURL,
+		Digest:               digest,
+		Tags:                 append([]string(nil), source.Spec.Tags...),
+		Metadata:             metadata,
+		AuthSecretRef:        authRef,
+		Source:               PublishSourcePromotion,
+		SourceImageBuildName: source.Status.SourceImageBuild,
+		Channel:              channel.Name,
+		PromotedFrom:         source.Name,
+	})
+	promoted.Spec.VerificationInterval = source.Spec.VerificationInterval
+	sourceImageBuild := promoted.Status.SourceImageBuild
+	if err := p.client.Create(ctx, promoted); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("%w: %s", ErrPromotionTargetExists, targetName)
+		}
+		return nil, fmt.Errorf("failed to create CatalogImage: %w", err)
+	}
+	if p.auditRecorder != nil {
+		p.auditRecorder.RecordPublished(ctx, promoted, string(PublishSourcePromotion))
+	}
+
+	record.PromotedAt = metav1.Now()
+	history := append(append([]automotivev1alpha1.PromotionRecord(nil), source.Status.PromotionHistory...), record)
+	if err := p.recordPromotion(ctx, promoted, history, sourceImageBuild); err != nil {
+		return nil, err
+	}
+
+	if channel.Protected {
+		if err := p.clearPendingPromotion(ctx, source, channel.Name); err != nil {
+			log.Error(err, "Failed to clear pending promotion")
+		}
+	}
+
+	log.Info("Promoted image", "promoted", targetName, "digest", digest,
+		"promotedBy", record.PromotedBy, "approvedBy", record.ApprovedBy)
+	return &PromoteResult{CatalogImage: promoted, Record: &record}, nil
+}
+
+// PendingPromotionFor returns the pending promotion of the image into the channel, if any
+func PendingPromotionFor(
+	catalogImage *automotivev1alpha1.CatalogImage,
+	channel string,
+) *automotivev1alpha1.PendingPromotion {
+	for i := range catalogImage.Status.PendingPromotions {
+		if catalogImage.Status.PendingPromotions[i].Channel == channel {
+			return &catalogImage.Status.PendingPromotions[i]
+		}
+	}
+	return nil
+}
+
+// recordPendingPromotion stores a promotion awaiting approval on the source image
+func (p *Publisher) recordPendingPromotion(
+	ctx context.Context,
+	source *automotivev1alpha1.CatalogImage,
+	channel, targetName, digest, requester string,
+) error {
+	patch := client.MergeFromWithOptions(source.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	source.Status.PendingPromotions = append(source.Status.PendingPromotions, automotivev1alpha1.PendingPromotion{
+		Channel:     channel,
+		TargetName:  targetName,
+		Digest:      digest,
+		RequestedBy: requester,
+		RequestedAt: metav1.Now(),
+	})
+	if err := p.client.Status().Patch(ctx, source, patch); err != nil {
+		return fmt.Errorf("failed to record pending promotion: %w", err)
+	}
+	return nil
+}
+
+// clearPendingPromotion removes the pending promotion into the channel from the source image
+func (p *Publisher) clearPendingPromotion(
+	ctx context.Context,
+	source *automotivev1alpha1.CatalogImage,
+	channel string,
+) error {
+	fresh := &automotivev1alpha1.CatalogImage{}
+	if err := p.client.Get(ctx, client.ObjectKeyFromObject(source), fresh); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(fresh.DeepCopy())
+	remaining := fresh.Status.PendingPromotions[:0]
+	for _, pending := range fresh.Status.PendingPromotions {
+		if pending.Channel != channel {
+			remaining = append(remaining, pending)
+		}
+	}
+	fresh.Status.PendingPromotions = remaining
+	return p.client.Status().Patch(ctx, fresh, patch)
+}
+
+// recordPromotion stores the promotion history and provenance on the promoted image
+func (p *Publisher) recordPromotion(
+	ctx context.Context,
+	promoted *automotivev1alpha1.CatalogImage,
+	history []automotivev1alpha1.PromotionRecord,
+	sourceImageBuild string,
+) error {
+	fresh := &automotivev1alpha1.CatalogImage{}
+	if err := p.client.Get(ctx, client.ObjectKeyFromObject(promoted), fresh); err != nil {
+		return fmt.Errorf("failed to get promoted image: %w", err)
+	}
+	patch := client.MergeFrom(fresh.DeepCopy())
+	fresh.Status.PromotionHistory = history
+	if fresh.Status.SourceImageBuild == "" {
+		fresh.Status.SourceImageBuild = sourceImageBuild
+	}
+	if fresh.Status.PublishedAt == nil {
+		fresh.Status.PublishedAt = GetCurrentTime()
+	}
+	if err := p.client.Status().Patch(ctx, fresh, patch); err != nil {
+		return fmt.Errorf("failed to record promotion history: %w", err)
+	}
+	fresh.DeepCopyInto(promoted)
+	return nil
+}
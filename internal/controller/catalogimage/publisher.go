@@ -23,6 +23,7 @@ import (
 	"github.com/containers/image/v5/types"
 	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
@@ -38,6 +39,8 @@ const (
 	PublishSourceExternal PublishSource = "External"
 	// PublishSourceManual indicates the image was manually added via API
 	PublishSourceManual PublishSource = "Manual"
+	// PublishSourcePromotion indicates the image was promoted from another catalog image
+	PublishSourcePromotion PublishSource = "Promotion"
 )
 
 // PublishOptions contains options for publishing an image to the catalog
@@ -60,6 +63,10 @@ type PublishOptions struct {
 	Source PublishSource
 	// SourceImageBuildName is the name of the source ImageBuild (if applicable)
 	SourceImageBuildName string
+	// Channel is the promotion channel of the image (if applicable)
+	Channel string
+	// PromotedFrom is the name of the CatalogImage this image was promoted from (if applicable)
+	PromotedFrom string
 	// VerifyAccessibility determines if registry accessibility should be verified
 	VerifyAccessibility bool
 }
@@ -153,12 +160,14 @@ func (p *Publisher) Publish(ctx context.Context, opts PublishOptions) (*PublishR
 	}, nil
 }
 
-// PublishFromImageBuild creates a CatalogImage from a completed ImageBuild
+// PublishFromImageBuild creates a CatalogImage from a completed ImageBuild.
+// targetNotes are recorded on the build's hardware target.
 func (p *Publisher) PublishFromImageBuild(
 	ctx context.Context,
 	imageBuild *automotivev1alpha1.ImageBuild,
 	catalogName string,
 	tags []string,
+	targetNotes string,
 ) (*PublishResult, error) {
 	log := p.log.WithValues("imageBuild", imageBuild.Name, "namespace", imageBuild.Namespace)
 
@@ -188,7 +197,7 @@ func (p *Publisher) PublishFromImageBuild(
 	// Add hardware target if specified
 	if imageBuild.Spec.Target != "" {
 		metadata.Targets = []automotivev1alpha1.HardwareTarget{
-			{Name: imageBuild.Spec.Target, Verified: true},
+			{Name: imageBuild.Spec.Target, Verified: true, Notes: targetNotes},
 		}
 	}
 
@@ -233,6 +242,7 @@ func (p *Publisher) buildCatalogImage(opts PublishOptions) *automotivev1alpha1.C
 			Tags:          opts.Tags,
 			AuthSecretRef: opts.AuthSecretRef,
 			Metadata:      opts.Metadata,
+			Channel:       opts.Channel,
 		},
 		Status: automotivev1alpha1.CatalogImageStatus{
 			Phase:       automotivev1alpha1.CatalogImagePhasePending,
@@ -243,6 +253,16 @@ func (p *Publisher) buildCatalogImage(opts PublishOptions) *automotivev1alpha1.C
 	// Set source ImageBuild if applicable
 	if opts.SourceImageBuildName != "" {
 		catalogImage.Status.SourceImageBuild = opts.SourceImageBuildName
+		if len(validation.IsValidLabelValue(opts.SourceImageBuildName)) == 0 {
+			catalogImage.Labels[automotivev1alpha1.LabelSourceImageBuild] = opts.SourceImageBuildName
+		}
+	}
+
+	if opts.Channel != "" {
+		catalogImage.Labels[automotivev1alpha1.LabelChannel] = opts.Channel
+	}
+	if opts.PromotedFrom != "" && len(validation.IsValidLabelValue(opts.PromotedFrom)) == 0 {
+		catalogImage.Labels[automotivev1alpha1.LabelPromotedFrom] = opts.PromotedFrom
 	}
 
 	// Set labels for indexing
@@ -314,6 +334,11 @@ func (p *Publisher) extractRegistryURL(imageBuild *automotivev1alpha1.ImageBuild
 		return imageBuild.Spec.Publishers.Registry.RepositoryURL
 	}
 
+	// Check the disk image OCI artifact
+	if imageBuild.Spec.ExportOCI != "" {
+		return imageBuild.Spec.ExportOCI
+	}
+
 	return ""
 }
 
@@ -0,0 +1,389 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package flashjob provides the controller flashing built images onto devices leased from Jumpstarter.
+package flashjob
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	flashJobFinalizer = "automotive.sdv.cloud.redhat.com/flashjob-lease"
+
+	// flashJobNamespaceAnnotation and flashJobNameAnnotation tie flash pods, which run in the operator
+	// namespace, to their FlashJob
+	flashJobNamespaceAnnotation = "automotive.sdv.cloud.redhat.com/flashjob-namespace"
+	flashJobNameAnnotation      = "automotive.sdv.cloud.redhat.com/flashjob-name"
+
+	// defaultLeaseDuration is the lease duration of FlashJobs that set none
+	defaultLeaseDuration = 30 * time.Minute
+	// leasePollInterval is how often pending leases and builds are checked
+	leasePollInterval = 10 * time.Second
+)
+
+// FlashJobReconciler reconciles a FlashJob object
+//
+//nolint:revive // Name follows Kubebuilder convention for reconcilers
+type FlashJobReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Log    logr.Logger
+
+	// Leaser leases exporters and describes flash pods: a JumpstarterLeaser, or a FakeExporterLeaser
+	// in tests and clusters without test hardware
+	Leaser ExporterLeaser
+
+	// ConfigNamespace is the namespace of the OperatorConfig holding the Jumpstarter target mappings,
+	// where flash pods run next to the Jumpstarter client credentials
+	ConfigNamespace string
+}
+
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=flashjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=flashjobs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=flashjobs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=jumpstarter.dev,resources=leases,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Reconcile leases an exporter for the FlashJob, runs the flash pod against it and releases the lease
+func (r *FlashJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	job := &automotivev1alpha1.FlashJob{}
+	if err := r.Get(ctx, req.NamespacedName, job); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !job.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.handleDeletion(ctx, job)
+	}
+	if controllerutil.AddFinalizer(job, flashJobFinalizer) {
+		if err := r.Update(ctx, job); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	switch job.Status.Phase {
+	case "", automotivev1alpha1.FlashJobPhasePending:
+		return r.requestLease(ctx, job)
+	case automotivev1alpha1.FlashJobPhaseLeasing:
+		return r.waitForLease(ctx, job)
+	case automotivev1alpha1.FlashJobPhaseFlashing:
+		return r.watchFlash(ctx, job)
+	}
+	return ctrl.Result{}, r.releaseLease(ctx, job)
+}
+
+// handleDeletion releases the lease and deletes the flash pod of a deleted FlashJob before letting it go
+func (r *FlashJobReconciler) handleDeletion(ctx context.Context, job *automotivev1alpha1.FlashJob) error {
+	if !controllerutil.ContainsFinalizer(job, flashJobFinalizer) {
+		return nil
+	}
+	if job.Status.PodName != "" {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: job.Status.PodName, Namespace: job.Status.PodNamespace}}
+		if err := r.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete flash pod %s: %w", job.Status.PodName, err)
+		}
+	}
+	if job.Status.LeaseName != "" && !job.Status.LeaseReleased {
+		if err := r.Leaser.Release(ctx, job.Status.LeaseName); err != nil {
+			return fmt.Errorf("failed to release lease %s: %w", job.Status.LeaseName, err)
+		}
+	}
+	controllerutil.RemoveFinalizer(job, flashJobFinalizer)
+	return r.Update(ctx, job)
+}
+
+// requestLease resolves what to flash from the ImageBuild and the target mapping, then requests a lease.
+// The exporter selector and flash command come from the target mapping the administrator configured;
+// the FlashJob can only narrow the selector.
+func (r *FlashJobReconciler) requestLease(ctx context.Context, job *automotivev1alpha1.FlashJob) (ctrl.Result, error) {
+	build := &automotivev1alpha1.ImageBuild{}
+	buildKey := k8stypes.NamespacedName{Name: job.Spec.ImageBuildName, Namespace: job.Namespace}
+	if err := r.Get(ctx, buildKey, build); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseFailed,
+				fmt.Sprintf("ImageBuild %s not found", job.Spec.ImageBuildName))
+		}
+		return ctrl.Result{}, err
+	}
+	switch build.Status.Phase {
+	case "Completed":
+	case "Failed":
+		return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseFailed,
+			fmt.Sprintf("ImageBuild %s failed", build.Name))
+	default:
+		message := fmt.Sprintf("Waiting for ImageBuild %s to complete", build.Name)
+		if job.Status.Phase != automotivev1alpha1.FlashJobPhasePending || job.Status.Message != message {
+			job.Status.Phase = automotivev1alpha1.FlashJobPhasePending
+			job.Status.Message = message
+			if err := r.Status().Update(ctx, job); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: leasePollInterval}, nil
+	}
+
+	mapping, err := r.targetMapping(ctx, build.Spec.Target)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if mapping.Selector == "" {
+		return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseFailed,
+			fmt.Sprintf("No Jumpstarter target mapping for target %q", build.Spec.Target))
+	}
+	selector, err := narrowSelector(mapping.Selector, job.Spec.ExporterSelector)
+	if err != nil {
+		return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseFailed, err.Error())
+	}
+	imageRef := firstNonEmpty(job.Spec.ImageRef, build.FlashImageRef())
+	if imageRef == "" {
+		return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseFailed,
+			fmt.Sprintf("ImageBuild %s has no OCI reference or artifact URL to flash", build.Name))
+	}
+	template := firstNonEmpty(mapping.FlashCmd, automotivev1alpha1.DefaultFlashCmd)
+
+	lease, err := r.Leaser.Acquire(ctx, job, selector, leaseDuration(job))
+	if err != nil {
+		return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseFailed, fmt.Sprintf("Failed to lease exporter: %v", err))
+	}
+
+	now := metav1.Now()
+	job.Status.Phase = automotivev1alpha1.FlashJobPhaseLeasing
+	job.Status.Message = "Waiting for an exporter matching " + selector
+	job.Status.ImageRef = imageRef
+	job.Status.ExporterSelector = selector
+	job.Status.FlashCmd = automotivev1alpha1.RenderFlashCmd(template, imageRef, build.Status.ArtifactURL)
+	job.Status.LeaseName = lease
+	job.Status.StartTime = &now
+	r.Log.Info("Requested exporter lease", "flashjob", job.Name, "namespace", job.Namespace,
+		"lease", lease, "selector", selector)
+	return ctrl.Result{RequeueAfter: leasePollInterval}, r.Status().Update(ctx, job)
+}
+
+// waitForLease starts the flash pod once an exporter is leased
+func (r *FlashJobReconciler) waitForLease(ctx context.Context, job *automotivev1alpha1.FlashJob) (ctrl.Result, error) {
+	state, err := r.Leaser.State(ctx, job.Status.LeaseName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if state.Failed != "" {
+		return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseFailed, "Lease failed: "+state.Failed)
+	}
+	if state.Exporter == "" {
+		if job.Status.StartTime != nil && time.Since(job.Status.StartTime.Time) > leaseDuration(job) {
+			return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseFailed,
+				fmt.Sprintf("No exporter matching %s became available within %s",
+					job.Status.ExporterSelector, leaseDuration(job)))
+		}
+		return ctrl.Result{RequeueAfter: leasePollInterval}, nil
+	}
+
+	spec, err := r.Leaser.FlashPodSpec(ctx, job, job.Status.FlashCmd)
+	if err != nil {
+		return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseFailed,
+			fmt.Sprintf("Failed to prepare flash pod: %v", err))
+	}
+	spec.ActiveDeadlineSeconds = ptr.To(int64(leaseDuration(job).Seconds()))
+	spec.AutomountServiceAccountToken = ptr.To(false)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      generatedName("flash", job),
+			Namespace: r.ConfigNamespace,
+			Labels: map[string]string{
+				automotivev1alpha1.FlashJobLabel: string(job.UID),
+				"app.kubernetes.io/managed-by":   "automotive-dev-operator",
+			},
+			Annotations: map[string]string{
+				flashJobNamespaceAnnotation: job.Namespace,
+				flashJobNameAnnotation:      job.Name,
+			},
+		},
+		Spec: spec,
+	}
+	if err := r.Create(ctx, pod); err != nil && !apierrors.IsAlreadyExists(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to create flash pod: %w", err)
+	}
+
+	job.Status.Phase = automotivev1alpha1.FlashJobPhaseFlashing
+	job.Status.Message = fmt.Sprintf("Flashing %s on exporter %s", job.Status.ImageRef, state.Exporter)
+	job.Status.ExporterName = state.Exporter
+	job.Status.PodName = pod.Name
+	job.Status.PodNamespace = pod.Namespace
+	r.Log.Info("Flashing", "flashjob", job.Name, "namespace", job.Namespace, "exporter", state.Exporter)
+	return ctrl.Result{}, r.Status().Update(ctx, job)
+}
+
+// watchFlash records the outcome of the flash pod
+func (r *FlashJobReconciler) watchFlash(ctx context.Context, job *automotivev1alpha1.FlashJob) (ctrl.Result, error) {
+	pod := &corev1.Pod{}
+	podKey := k8stypes.NamespacedName{Name: job.Status.PodName, Namespace: job.Status.PodNamespace}
+	if err := r.Get(ctx, podKey, pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseFailed, "Flash pod was deleted")
+		}
+		return ctrl.Result{}, err
+	}
+
+	terminated := flashContainerTermination(pod)
+	if terminated != nil {
+		job.Status.ExitCode = ptr.To(terminated.ExitCode)
+	}
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseSucceeded,
+			fmt.Sprintf("Flashed %s on exporter %s", job.Status.ImageRef, job.Status.ExporterName))
+	case corev1.PodFailed:
+		message := "Flash failed"
+		switch {
+		case terminated != nil:
+			message = fmt.Sprintf("Flash failed with exit code %d", terminated.ExitCode)
+			if terminated.Message != "" {
+				message += ": " + terminated.Message
+			}
+		case pod.Status.Reason != "":
+			message += ": " + pod.Status.Reason
+		}
+		return r.finish(ctx, job, automotivev1alpha1.FlashJobPhaseFailed, message)
+	}
+	return ctrl.Result{}, nil
+}
+
+// finish records the outcome of the FlashJob and releases its lease
+func (r *FlashJobReconciler) finish(
+	ctx context.Context,
+	job *automotivev1alpha1.FlashJob,
+	phase automotivev1alpha1.FlashJobPhase,
+	message string,
+) (ctrl.Result, error) {
+	now := metav1.Now()
+	job.Status.Phase = phase
+	job.Status.Message = message
+	job.Status.CompletionTime = &now
+	if err := r.Status().Update(ctx, job); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Log.Info("FlashJob finished", "flashjob", job.Name, "namespace", job.Namespace, "phase", phase,
+		"message", message)
+	return ctrl.Result{}, r.releaseLease(ctx, job)
+}
+
+// releaseLease releases the lease of a finished FlashJob, once
+func (r *FlashJobReconciler) releaseLease(ctx context.Context, job *automotivev1alpha1.FlashJob) error {
+	if job.Status.LeaseName == "" || job.Status.LeaseReleased {
+		return nil
+	}
+	if err := r.Leaser.Release(ctx, job.Status.LeaseName); err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", job.Status.LeaseName, err)
+	}
+	job.Status.LeaseReleased = true
+	return r.Status().Update(ctx, job)
+}
+
+// targetMapping returns the Jumpstarter target mapping of a build target, empty if there is none
+func (r *FlashJobReconciler) targetMapping(
+	ctx context.Context,
+	target string,
+) (automotivev1alpha1.JumpstarterTargetMapping, error) {
+	config := &automotivev1alpha1.OperatorConfig{}
+	key := k8stypes.NamespacedName{Name: "config", Namespace: r.ConfigNamespace}
+	if err := r.Get(ctx, key, config); err != nil {
+		if apierrors.IsNotFound(err) {
+			return automotivev1alpha1.JumpstarterTargetMapping{}, nil
+		}
+		return automotivev1alpha1.JumpstarterTargetMapping{}, fmt.Errorf("failed to get OperatorConfig: %w", err)
+	}
+	if config.Spec.Jumpstarter == nil {
+		return automotivev1alpha1.JumpstarterTargetMapping{}, nil
+	}
+	return config.Spec.Jumpstarter.TargetMappings[target], nil
+}
+
+// narrowSelector adds the requirements of a FlashJob's selector to the target mapping's, so that
+// FlashJobs can pick among the exporters of their target but never lease others
+func narrowSelector(mappingSelector, jobSelector string) (string, error) {
+	if jobSelector == "" {
+		return mappingSelector, nil
+	}
+	if _, err := metav1.ParseToLabelSelector(jobSelector); err != nil {
+		return "", fmt.Errorf("invalid exporter selector %q: %v", jobSelector, err)
+	}
+	return mappingSelector + "," + jobSelector, nil
+}
+
+// mapFlashPod requeues the FlashJob of a flash pod
+func mapFlashPod(_ context.Context, obj client.Object) []reconcile.Request {
+	if _, ok := obj.GetLabels()[automotivev1alpha1.FlashJobLabel]; !ok {
+		return nil
+	}
+	annotations := obj.GetAnnotations()
+	if annotations[flashJobNameAnnotation] == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{
+		Name:      annotations[flashJobNameAnnotation],
+		Namespace: annotations[flashJobNamespaceAnnotation],
+	}}}
+}
+
+func flashContainerTermination(pod *corev1.Pod) *corev1.ContainerStateTerminated {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == flashContainerName {
+			return status.State.Terminated
+		}
+	}
+	return nil
+}
+
+func leaseDuration(job *automotivev1alpha1.FlashJob) time.Duration {
+	if job.Spec.LeaseDuration != nil && job.Spec.LeaseDuration.Duration > 0 {
+		return job.Spec.LeaseDuration.Duration
+	}
+	return defaultLeaseDuration
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *FlashJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&automotivev1alpha1.FlashJob{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(mapFlashPod)).
+		Complete(r)
+}
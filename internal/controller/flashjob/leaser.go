@@ -0,0 +1,349 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flashjob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	// defaultJumpstarterImage runs jmp in flash pods when the client configuration sets no image
+	defaultJumpstarterImage = "quay.io/jumpstarter-dev/jumpstarter:latest"
+	// clientConfigKey is the key of the jmp client configuration in the client config secret
+	clientConfigKey = "client.yaml"
+	// clientConfigDir is where the client configuration is mounted into flash pods
+	clientConfigDir = "/etc/jumpstarter"
+	// flashContainerName is the container of flash pods running the flash command
+	flashContainerName = "flash"
+)
+
+// leaseGVK is the Jumpstarter Lease kind, used unstructured so the operator does not depend on Jumpstarter
+var leaseGVK = schema.GroupVersionKind{Group: "jumpstarter.dev", Version: "v1alpha1", Kind: "Lease"}
+
+// errNoJumpstarterClient is returned when the OperatorConfig names no Jumpstarter client to lease as
+var errNoJumpstarterClient = errors.New(
+	"no Jumpstarter client configured in the OperatorConfig (spec.jumpstarter.client)")
+
+// LeaseState is the state of an exporter lease
+type LeaseState struct {
+	// Exporter is the leased exporter, empty while the lease waits for one
+	Exporter string
+	// Failed explains why the lease cannot be fulfilled or has ended, empty otherwise
+	Failed string
+}
+
+// ExporterLeaser leases Jumpstarter exporters and describes the pods flashing their devices
+type ExporterLeaser interface {
+	// Acquire requests a lease of an exporter matching selector and returns the lease name. Acquiring
+	// the lease of a job again returns the existing lease.
+	Acquire(ctx context.Context, job *automotivev1alpha1.FlashJob, selector string, duration time.Duration) (string, error)
+	// State returns the state of a lease
+	State(ctx context.Context, leaseName string) (LeaseState, error)
+	// Release ends a lease; releasing a missing lease is not an error
+	Release(ctx context.Context, leaseName string) error
+	// FlashPodSpec returns the spec of the pod running flashCmd against the job's leased exporter
+	FlashPodSpec(ctx context.Context, job *automotivev1alpha1.FlashJob, flashCmd string) (corev1.PodSpec, error)
+}
+
+// maxGeneratedNameLength keeps names generated for FlashJobs valid as DNS labels
+const maxGeneratedNameLength = 63
+
+// generatedName returns a deterministic name for an object created for a job, unique across
+// namespaces and job incarnations: the prefix, namespace and name, truncated to leave room for a
+// hash of the namespace, name and UID
+func generatedName(prefix string, job *automotivev1alpha1.FlashJob) string {
+	sum := sha256.Sum256([]byte(job.Namespace + "/" + job.Name + "/" + string(job.UID)))
+	suffix := hex.EncodeToString(sum[:])[:10]
+	base := fmt.Sprintf("%s-%s-%s", prefix, job.Namespace, job.Name)
+	if limit := maxGeneratedNameLength - len(suffix) - 1; len(base) > limit {
+		base = strings.TrimRight(base[:limit], "-.")
+	}
+	return base + "-" + suffix
+}
+
+// leaseName is the deterministic name of a job's lease
+func leaseName(job *automotivev1alpha1.FlashJob) string {
+	return generatedName("ado", job)
+}
+
+// JumpstarterLeaser leases exporters through Jumpstarter Lease objects as the client configured in the
+// OperatorConfig, and flashes with `jmp shell --lease`
+type JumpstarterLeaser struct {
+	Client client.Client
+	// ConfigNamespace is the namespace of the OperatorConfig and of the client configuration secret
+	ConfigNamespace string
+}
+
+// NewJumpstarterLeaser creates a leaser backed by the Jumpstarter controller
+func NewJumpstarterLeaser(c client.Client, configNamespace string) *JumpstarterLeaser {
+	return &JumpstarterLeaser{Client: c, ConfigNamespace: configNamespace}
+}
+
+func (l *JumpstarterLeaser) clientConfig(ctx context.Context) (*automotivev1alpha1.JumpstarterClientConfig, error) {
+	config := &automotivev1alpha1.OperatorConfig{}
+	key := k8stypes.NamespacedName{Name: "config", Namespace: l.ConfigNamespace}
+	if err := l.Client.Get(ctx, key, config); err != nil {
+		return nil, fmt.Errorf("failed to get OperatorConfig: %w", err)
+	}
+	if config.Spec.Jumpstarter == nil || config.Spec.Jumpstarter.Client == nil {
+		return nil, errNoJumpstarterClient
+	}
+	return config.Spec.Jumpstarter.Client, nil
+}
+
+// Acquire creates a Lease for the configured client
+func (l *JumpstarterLeaser) Acquire(
+	ctx context.Context,
+	job *automotivev1alpha1.FlashJob,
+	selector string,
+	duration time.Duration,
+) (string, error) {
+	cfg, err := l.clientConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	labelSelector, err := metav1.ParseToLabelSelector(selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid exporter selector %q: %w", selector, err)
+	}
+	selectorObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(labelSelector)
+	if err != nil {
+		return "", err
+	}
+
+	lease := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"clientRef": map[string]any{"name": cfg.Name},
+			"duration":  duration.String(),
+			"selector":  selectorObj,
+		},
+	}}
+	lease.SetGroupVersionKind(leaseGVK)
+	lease.SetName(leaseName(job))
+	lease.SetNamespace(cfg.Namespace)
+	lease.SetLabels(map[string]string{
+		automotivev1alpha1.FlashJobLabel: string(job.UID),
+		"app.kubernetes.io/managed-by":   "automotive-dev-operator",
+	})
+	if err := l.Client.Create(ctx, lease); err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("failed to create lease: %w", err)
+	}
+	return lease.GetName(), nil
+}
+
+// State reads the exporter and conditions of a Lease
+func (l *JumpstarterLeaser) State(ctx context.Context, name string) (LeaseState, error) {
+	cfg, err := l.clientConfig(ctx)
+	if err != nil {
+		return LeaseState{}, err
+	}
+	lease := &unstructured.Unstructured{}
+	lease.SetGroupVersionKind(leaseGVK)
+	if err := l.Client.Get(ctx, k8stypes.NamespacedName{Name: name, Namespace: cfg.Namespace}, lease); err != nil {
+		if apierrors.IsNotFound(err) {
+			return LeaseState{Failed: "lease no longer exists"}, nil
+		}
+		return LeaseState{}, err
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(lease.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok || condition["status"] != string(metav1.ConditionTrue) {
+			continue
+		}
+		if condition["type"] == "Unsatisfiable" || condition["type"] == "Invalid" {
+			message, _ := condition["message"].(string)
+			return LeaseState{Failed: fmt.Sprintf("%s: %s", condition["type"], message)}, nil
+		}
+	}
+	if ended, _, _ := unstructured.NestedBool(lease.Object, "status", "ended"); ended {
+		return LeaseState{Failed: "lease ended"}, nil
+	}
+	exporter, _, _ := unstructured.NestedString(lease.Object, "status", "exporterRef", "name")
+	return LeaseState{Exporter: exporter}, nil
+}
+
+// Release asks the Jumpstarter controller to end a Lease
+func (l *JumpstarterLeaser) Release(ctx context.Context, name string) error {
+	cfg, err := l.clientConfig(ctx)
+	if err != nil {
+		return err
+	}
+	lease := &unstructured.Unstructured{}
+	lease.SetGroupVersionKind(leaseGVK)
+	if err := l.Client.Get(ctx, k8stypes.NamespacedName{Name: name, Namespace: cfg.Namespace}, lease); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	patch := client.MergeFrom(lease.DeepCopy())
+	if err := unstructured.SetNestedField(lease.Object, true, "spec", "release"); err != nil {
+		return err
+	}
+	return l.Client.Patch(ctx, lease, patch)
+}
+
+// FlashPodSpec runs the flash command in a jmp shell connected to the lease. The pod runs in the
+// operator namespace and mounts the client configuration from there, so the credentials never reach
+// tenant namespaces.
+func (l *JumpstarterLeaser) FlashPodSpec(
+	ctx context.Context,
+	job *automotivev1alpha1.FlashJob,
+	flashCmd string,
+) (corev1.PodSpec, error) {
+	cfg, err := l.clientConfig(ctx)
+	if err != nil {
+		return corev1.PodSpec{}, err
+	}
+	secret := &corev1.Secret{}
+	key := k8stypes.NamespacedName{Name: cfg.ConfigSecretName, Namespace: l.ConfigNamespace}
+	if err := l.Client.Get(ctx, key, secret); err != nil {
+		return corev1.PodSpec{}, fmt.Errorf("failed to get Jumpstarter client config secret: %w", err)
+	}
+	if len(secret.Data[clientConfigKey]) == 0 {
+		return corev1.PodSpec{}, fmt.Errorf("secret %s has no %q key", cfg.ConfigSecretName, clientConfigKey)
+	}
+
+	image := cfg.Image
+	if image == "" {
+		image = defaultJumpstarterImage
+	}
+	return corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers: []corev1.Container{{
+			Name:  flashContainerName,
+			Image: image,
+			Command: []string{
+				"jmp", "shell",
+				"--client-config", clientConfigDir + "/" + clientConfigKey,
+				"--lease", job.Status.LeaseName,
+				"--", "sh", "-c", flashCmd,
+			},
+			VolumeMounts: []corev1.VolumeMount{{Name: "jumpstarter-client", MountPath: clientConfigDir, ReadOnly: true}},
+		}},
+		Volumes: []corev1.Volume{{
+			Name:         "jumpstarter-client",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: cfg.ConfigSecretName}},
+		}},
+	}, nil
+}
+
+// FakeExporterLeaser stands in for Jumpstarter in tests and in clusters without test hardware. Every
+// lease is granted at once on a single fake exporter, and flash pods only print the flash command.
+type FakeExporterLeaser struct {
+	// Exporter is the name of the fake exporter
+	Exporter string
+
+	mu       sync.Mutex
+	leases   map[string]string
+	released map[string]bool
+}
+
+// NewFakeExporterLeaser creates a fake leaser granting leases on the exporter "fake-exporter"
+func NewFakeExporterLeaser() *FakeExporterLeaser {
+	return &FakeExporterLeaser{
+		Exporter: "fake-exporter",
+		leases:   map[string]string{},
+		released: map[string]bool{},
+	}
+}
+
+// Acquire grants the lease immediately
+func (f *FakeExporterLeaser) Acquire(
+	_ context.Context,
+	job *automotivev1alpha1.FlashJob,
+	selector string,
+	_ time.Duration,
+) (string, error) {
+	if _, err := metav1.ParseToLabelSelector(selector); err != nil {
+		return "", fmt.Errorf("invalid exporter selector %q: %w", selector, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := leaseName(job)
+	if _, ok := f.leases[name]; !ok {
+		f.leases[name] = selector
+	}
+	return name, nil
+}
+
+// State reports the fake exporter for granted leases
+func (f *FakeExporterLeaser) State(_ context.Context, name string) (LeaseState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case f.released[name]:
+		return LeaseState{Failed: "lease ended"}, nil
+	case f.leases[name] == "":
+		return LeaseState{Failed: "lease no longer exists"}, nil
+	}
+	return LeaseState{Exporter: f.Exporter}, nil
+}
+
+// Release ends a lease
+func (f *FakeExporterLeaser) Release(_ context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.leases[name]; ok {
+		f.released[name] = true
+	}
+	return nil
+}
+
+// Released reports whether a lease was granted and released
+func (f *FakeExporterLeaser) Released(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.released[name]
+}
+
+// FlashPodSpec runs a pod echoing the flash command
+func (f *FakeExporterLeaser) FlashPodSpec(
+	_ context.Context,
+	_ *automotivev1alpha1.FlashJob,
+	flashCmd string,
+) (corev1.PodSpec, error) {
+	return corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers: []corev1.Container{{
+			Name:    flashContainerName,
+			Image:   "quay.io/fedora/fedora:41",
+			Command: []string{"sh", "-c", `echo "[$EXPORTER] $FLASH_CMD"; echo "Flash complete"`},
+			Env: []corev1.EnvVar{
+				{Name: "EXPORTER", Value: f.Exporter},
+				{Name: "FLASH_CMD", Value: flashCmd},
+			},
+		}},
+	}, nil
+}
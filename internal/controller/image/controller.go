@@ -4,11 +4,15 @@ package image
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/imagelocation"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/catalogimage"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -17,8 +21,19 @@ import (
 )
 
 const (
+	phaseVerifying   = "Verifying"
 	phaseAvailable   = "Available"
 	phaseUnavailable = "Unavailable"
+
+	// verificationInterval is how often an available image is verified again
+	verificationInterval = time.Hour
+
+	// initialRetryDelay is the first retry delay after a failed verification; it doubles
+	// with each consecutive failure up to verificationInterval
+	initialRetryDelay = 30 * time.Second
+
+	// registryTimeout bounds a single registry verification
+	registryTimeout = 30 * time.Second
 )
 
 // ImageReconciler reconciles an Image object
@@ -28,12 +43,17 @@ type ImageReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Log    logr.Logger
+
+	// RegistryClient is used to query registries; defaults to the containers/image client
+	RegistryClient catalogimage.RegistryClient
 }
 
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=images,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=images/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=images/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;delete
 
 // Reconcile Image
 func (r *ImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -48,7 +68,7 @@ func (r *ImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	switch image.Status.Phase {
 	case "":
 		return r.handleInitialState(ctx, image)
-	case "Verifying":
+	case phaseVerifying:
 		return r.handleVerifyingState(ctx, image)
 	case phaseAvailable:
 		return r.handleAvailableState(ctx, image)
@@ -64,7 +84,7 @@ func (r *ImageReconciler) handleInitialState(
 	ctx context.Context,
 	image *automotivev1alpha1.Image,
 ) (ctrl.Result, error) {
-	if err := r.updateStatus(ctx, image, "Verifying", "Starting image location verification"); err != nil {
+	if err := r.updateStatus(ctx, image, phaseVerifying, "Starting image location verification"); err != nil {
 		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
 	}
 	return ctrl.Result{Requeue: true}, nil
@@ -74,89 +94,185 @@ func (r *ImageReconciler) handleVerifyingState(
 	ctx context.Context,
 	image *automotivev1alpha1.Image,
 ) (ctrl.Result, error) {
-	log := r.Log.WithValues("image", types.NamespacedName{Name: image.Name, Namespace: image.Namespace})
+	return r.verifyAndRecord(ctx, image)
+}
 
-	// Verify the image location is accessible
-	accessible, err := r.verifyImageLocation(ctx, image)
-	if err != nil {
-		log.Error(err, "Failed to verify image location")
-		if err := r.updateStatus(ctx, image, phaseUnavailable, fmt.Sprintf("Verification failed: %v", err)); err != nil {
-			return ctrl.Result{RequeueAfter: time.Second * 10}, nil
-		}
-		return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+func (r *ImageReconciler) handleAvailableState(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+) (ctrl.Result, error) {
+	// Periodically re-verify the image is still accessible
+	if wait := verificationDueIn(image); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
 	}
+	return r.verifyAndRecord(ctx, image)
+}
 
-	if accessible {
-		if err := r.updateStatus(ctx, image, phaseAvailable, "Image location verified and accessible"); err != nil {
-			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
-		}
-		// Set LastVerified timestamp
-		if err := r.updateLastVerified(ctx, image); err != nil {
-			log.Error(err, "Failed to update LastVerified timestamp")
-		}
-		return ctrl.Result{RequeueAfter: time.Hour * 1}, nil // Recheck every hour
+func (r *ImageReconciler) handleUnavailableState(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+) (ctrl.Result, error) {
+	// Try to verify again once the backoff has elapsed
+	if wait := verificationDueIn(image); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+	return r.verifyAndRecord(ctx, image)
+}
+
+// verificationDueIn returns how long until the image should be verified again.
+// Spec changes are verified right away.
+func verificationDueIn(image *automotivev1alpha1.Image) time.Duration {
+	if image.Status.ObservedGeneration != image.Generation || image.Status.NextVerification == nil {
+		return 0
 	}
+	return time.Until(image.Status.NextVerification.Time)
+}
 
-	if err := r.updateStatus(ctx, image, phaseUnavailable, "Image location is not accessible"); err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+// retryBackoff returns the delay before retrying after the given number of consecutive failures
+func retryBackoff(failures int32) time.Duration {
+	delay := initialRetryDelay
+	for i := int32(1); i < failures && delay < verificationInterval; i++ {
+		delay *= 2
 	}
-	return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+	return min(delay, verificationInterval)
 }
 
-func (r *ImageReconciler) handleAvailableState(
+// verifyAndRecord verifies the image location and records the outcome, the observed digest
+// and size, and the time of the next verification in the status
+func (r *ImageReconciler) verifyAndRecord(
 	ctx context.Context,
 	image *automotivev1alpha1.Image,
 ) (ctrl.Result, error) {
-	// Periodically re-verify the image is still accessible
-	accessible, err := r.verifyImageLocation(ctx, image)
-	if err != nil || !accessible {
-		if err := r.updateStatus(ctx, image, "Verifying", "Re-verifying image location"); err != nil {
-			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+	log := r.Log.WithValues("image", types.NamespacedName{Name: image.Name, Namespace: image.Namespace})
+
+	result, verifyErr := r.verifyImageLocation(ctx, image)
+	if errors.Is(verifyErr, errVerificationPending) {
+		if image.Status.Phase != phaseVerifying {
+			if err := r.updateStatus(ctx, image, phaseVerifying, "Waiting for the image location to be checked"); err != nil {
+				return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+			}
 		}
-		return ctrl.Result{Requeue: true}, nil
+		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+	}
+	if verifyErr != nil {
+		log.Info("Image location verification failed", "error", verifyErr.Error())
 	}
 
-	// Update LastVerified timestamp
-	if err := r.updateLastVerified(ctx, image); err != nil {
-		r.Log.Error(err, "Failed to update LastVerified timestamp")
+	fresh := &automotivev1alpha1.Image{}
+	if err := r.Get(ctx, types.NamespacedName{Name: image.Name, Namespace: image.Namespace}, fresh); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	patch := client.MergeFrom(fresh.DeepCopy())
+	now := metav1.Now()
+
+	phase := phaseAvailable
+	message := "Image location verified and accessible"
+	next := verificationInterval
+	if verifyErr != nil {
+		phase = phaseUnavailable
+		message = fmt.Sprintf("Verification failed: %v", verifyErr)
+		fresh.Status.ConsecutiveFailures++
+		next = retryBackoff(fresh.Status.ConsecutiveFailures)
+	} else {
+		fresh.Status.ConsecutiveFailures = 0
+		fresh.Status.LastVerified = &now
+		fresh.Status.ResolvedDigest = result.digest
+		fresh.Status.Size = result.size
 	}
 
-	return ctrl.Result{RequeueAfter: time.Hour * 1}, nil // Recheck every hour
+	nextVerification := metav1.NewTime(now.Add(next))
+	fresh.Status.NextVerification = &nextVerification
+	fresh.Status.ObservedGeneration = fresh.Generation
+	setStatusPhase(fresh, phase, message)
+
+	if err := r.Status().Patch(ctx, fresh, patch); err != nil {
+		log.Error(err, "Failed to record verification result")
+		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+	}
+	return ctrl.Result{RequeueAfter: next}, nil
 }
 
-func (r *ImageReconciler) handleUnavailableState(
+// verificationResult holds what a successful verification observed about the image
+type verificationResult struct {
+	digest string
+	size   *automotivev1alpha1.ImageSize
+}
+
+func (r *ImageReconciler) verifyImageLocation(
 	ctx context.Context,
 	image *automotivev1alpha1.Image,
-) (ctrl.Result, error) {
-	// Try to verify again after some time
-	if err := r.updateStatus(ctx, image, "Verifying", "Retrying image location verification"); err != nil {
-		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+) (*verificationResult, error) {
+	if image.Spec.Location.Type != imagelocation.TypePVC {
+		if err := r.deleteReaderPod(ctx, image); err != nil {
+			return nil, fmt.Errorf("failed to clean up reader pod: %w", err)
+		}
 	}
-	return ctrl.Result{Requeue: true}, nil
-}
 
-func (r *ImageReconciler) verifyImageLocation(ctx context.Context, image *automotivev1alpha1.Image) (bool, error) {
 	switch image.Spec.Location.Type {
-	case "registry":
+	case imagelocation.TypeRegistry:
 		return r.verifyRegistryLocation(ctx, image)
+	case imagelocation.TypeHTTP:
+		return r.verifyHTTPLocation(ctx, image)
+	case imagelocation.TypeS3:
+		return r.verifyS3Location(ctx, image)
+	case imagelocation.TypePVC:
+		return r.verifyPVCLocation(ctx, image)
 	default:
-		return false, fmt.Errorf(
-			"unsupported location type: %s (only 'registry' is currently supported)",
-			image.Spec.Location.Type,
-		)
+		return nil, fmt.Errorf("unsupported location type: %s", image.Spec.Location.Type)
 	}
 }
 
-func (r *ImageReconciler) verifyRegistryLocation(_ context.Context, image *automotivev1alpha1.Image) (bool, error) {
-	if image.Spec.Location.Registry == nil {
-		return false, fmt.Errorf("registry location configuration is nil")
+// verifyRegistryLocation fetches the image manifest with the configured credentials, checks the
+// digest when one is pinned and derives the image size from the manifest layers
+func (r *ImageReconciler) verifyRegistryLocation(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+) (*verificationResult, error) {
+	loc := image.Spec.Location.Registry
+	if loc == nil {
+		return nil, fmt.Errorf("registry location configuration is nil")
 	}
 
-	if image.Spec.Location.Registry.URL == "" {
-		return false, fmt.Errorf("registry URL is required")
+	if loc.URL == "" {
+		return nil, fmt.Errorf("registry URL is required")
 	}
 
-	return true, nil // Placeholder - assume accessible for now
+	var secretRef *automotivev1alpha1.AuthSecretReference
+	if loc.SecretRef != "" {
+		secretRef = &automotivev1alpha1.AuthSecretReference{Name: loc.SecretRef}
+	}
+	auth, err := catalogimage.GetAuthFromSecret(ctx, r.Client, secretRef, image.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, registryTimeout)
+	defer cancel()
+
+	metadata, err := r.getRegistryClient().GetImageMetadata(ctx, loc.URL, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	if loc.Digest != "" && metadata.ResolvedDigest != loc.Digest {
+		return nil, fmt.Errorf("digest mismatch: expected %s, registry has %s", loc.Digest, metadata.ResolvedDigest)
+	}
+
+	result := &verificationResult{digest: metadata.ResolvedDigest}
+	if metadata.SizeBytes > 0 {
+		size := metadata.SizeBytes
+		result.size = &automotivev1alpha1.ImageSize{CompressedBytes: &size}
+	}
+	return result, nil
+}
+
+// getRegistryClient returns the registry client (allows for testing)
+func (r *ImageReconciler) getRegistryClient() catalogimage.RegistryClient {
+	if r.RegistryClient != nil {
+		return r.RegistryClient
+	}
+	return catalogimage.NewRegistryClient()
 }
 
 func (r *ImageReconciler) updateStatus(
@@ -173,9 +289,14 @@ func (r *ImageReconciler) updateStatus(
 	}
 
 	patch := client.MergeFrom(fresh.DeepCopy())
+	setStatusPhase(fresh, phase, message)
+	return r.Status().Patch(ctx, fresh, patch)
+}
 
-	fresh.Status.Phase = phase
-	fresh.Status.Message = message
+// setStatusPhase sets the phase and message and updates the Available condition to match
+func setStatusPhase(image *automotivev1alpha1.Image, phase, message string) {
+	image.Status.Phase = phase
+	image.Status.Message = message
 
 	// Update conditions
 	now := metav1.Now()
@@ -197,39 +318,25 @@ func (r *ImageReconciler) updateStatus(
 
 	// Update or add the condition
 	updated := false
-	for i, existingCondition := range fresh.Status.Conditions {
+	for i, existingCondition := range image.Status.Conditions {
 		if existingCondition.Type == phaseAvailable {
-			fresh.Status.Conditions[i] = condition
+			if existingCondition.Status == condition.Status {
+				condition.LastTransitionTime = existingCondition.LastTransitionTime
+			}
+			image.Status.Conditions[i] = condition
 			updated = true
 			break
 		}
 	}
 	if !updated {
-		fresh.Status.Conditions = append(fresh.Status.Conditions, condition)
-	}
-
-	return r.Status().Patch(ctx, fresh, patch)
-}
-
-func (r *ImageReconciler) updateLastVerified(ctx context.Context, image *automotivev1alpha1.Image) error {
-	fresh := &automotivev1alpha1.Image{}
-	if err := r.Get(ctx, types.NamespacedName{
-		Name:      image.Name,
-		Namespace: image.Namespace,
-	}, fresh); err != nil {
-		return err
+		image.Status.Conditions = append(image.Status.Conditions, condition)
 	}
-
-	patch := client.MergeFrom(fresh.DeepCopy())
-	now := metav1.Now()
-	fresh.Status.LastVerified = &now
-
-	return r.Status().Patch(ctx, fresh, patch)
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ImageReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&automotivev1alpha1.Image{}).
+		Owns(&corev1.Pod{}).
 		Complete(r)
 }
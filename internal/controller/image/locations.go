@@ -0,0 +1,381 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/imagelocation"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/objectstore"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// readerPodStartupGrace is how long a started reader pod may take to become ready
+	readerPodStartupGrace = 2 * time.Minute
+
+	// readerLocationAnnotation records the claim and path a reader pod was created for
+	readerLocationAnnotation = "automotive.sdv.cloud.redhat.com/pvc-location"
+
+	// readerImage provides the shell and coreutils used by reader pods
+	readerImage = "quay.io/nginx/nginx-unprivileged:latest"
+)
+
+// errVerificationPending is returned while a verification cannot complete yet,
+// e.g. because the reader pod of a pvc location is still starting
+var errVerificationPending = errors.New("verification pending")
+
+// secretFor reads a secret in the image's namespace
+func (r *ImageReconciler) secretFor(ctx context.Context, image *automotivev1alpha1.Image, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: image.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", name, err)
+	}
+	return secret, nil
+}
+
+// verifyHTTPLocation checks that the image file is reachable, reads its size and compares the
+// configured checksum with the digest the server advertises, if any
+func (r *ImageReconciler) verifyHTTPLocation(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+) (*verificationResult, error) {
+	loc := image.Spec.Location.HTTP
+	if loc == nil {
+		return nil, fmt.Errorf("http location configuration is nil")
+	}
+	if loc.URL == "" {
+		return nil, fmt.Errorf("http URL is required")
+	}
+
+	var authorization string
+	if loc.SecretRef != "" {
+		secret, err := r.secretFor(ctx, image, loc.SecretRef)
+		if err != nil {
+			return nil, err
+		}
+		if authorization, err = imagelocation.AuthorizationHeader(secret); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, registryTimeout)
+	defer cancel()
+
+	size, header, err := probeHTTPFile(ctx, imagelocation.NewHTTPClient(loc.InsecureSkipTLSVerify), loc.URL, authorization)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &verificationResult{}
+	advertised := imagelocation.AdvertisedSHA256(header)
+	switch {
+	case loc.SHA256 != "" && advertised != "" && advertised != loc.SHA256:
+		return nil, fmt.Errorf("checksum mismatch: expected sha256:%s, server advertises sha256:%s", loc.SHA256, advertised)
+	case loc.SHA256 != "":
+		result.digest = "sha256:" + loc.SHA256
+	case advertised != "":
+		result.digest = "sha256:" + advertised
+	}
+	if size >= 0 {
+		result.size = imagelocation.FileSize(imagelocation.FileName(image.Spec.Location), size)
+	}
+	return result, nil
+}
+
+// probeHTTPFile returns the size and response headers of a file without downloading it.
+// Servers that reject HEAD are asked for the first byte instead. The size is -1 when unknown.
+func probeHTTPFile(ctx context.Context, httpClient *http.Client, url, authorization string) (int64, http.Header, error) {
+	do := func(method string, header http.Header) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL: %w", err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reach %s: %w", url, err)
+		}
+		_ = resp.Body.Close()
+		return resp, nil
+	}
+
+	resp, err := do(http.MethodHead, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		if resp, err = do(http.MethodGet, http.Header{"Range": {"bytes=0-0"}}); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		// Content-Range: bytes 0-0/<size>
+		_, total, _ := strings.Cut(resp.Header.Get("Content-Range"), "/")
+		size, err := strconv.ParseInt(total, 10, 64)
+		if err != nil {
+			size = -1
+		}
+		return size, resp.Header, nil
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return resp.ContentLength, resp.Header, nil
+	default:
+		return 0, nil, fmt.Errorf("server returned %s for %s", resp.Status, url)
+	}
+}
+
+// verifyS3Location checks that the object exists and reads its size
+func (r *ImageReconciler) verifyS3Location(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+) (*verificationResult, error) {
+	loc := image.Spec.Location.S3
+	if loc == nil {
+		return nil, fmt.Errorf("s3 location configuration is nil")
+	}
+	if loc.Bucket == "" || loc.Key == "" {
+		return nil, fmt.Errorf("s3 bucket and key are required")
+	}
+
+	secret, err := r.secretFor(ctx, image, loc.Secret)
+	if err != nil {
+		return nil, err
+	}
+	store, err := objectstore.New(loc.Endpoint, loc.Region, objectstore.CredentialsFromSecret(secret), loc.InsecureSkipTLSVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, registryTimeout)
+	defer cancel()
+
+	resp, err := store.HeadObject(ctx, loc.Bucket, loc.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach object storage: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("object %s not found in bucket %s", loc.Key, loc.Bucket)
+	case resp.StatusCode >= http.StatusBadRequest:
+		return nil, fmt.Errorf("object storage returned %s", resp.Status)
+	}
+
+	result := &verificationResult{}
+	if resp.ContentLength >= 0 {
+		result.size = imagelocation.FileSize(path.Base(loc.Key), resp.ContentLength)
+	}
+	return result, nil
+}
+
+// verifyPVCLocation checks the image file through a reader pod that mounts the claim read-only.
+// An init container records the file size in its termination message and fails when the file
+// is missing; the long-running container re-checks the file with its readiness probe and is
+// used by the build API to stream downloads.
+func (r *ImageReconciler) verifyPVCLocation(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+) (*verificationResult, error) {
+	loc := image.Spec.Location.PVC
+	if loc == nil {
+		return nil, fmt.Errorf("pvc location configuration is nil")
+	}
+	filePath, err := imagelocation.CleanPVCPath(loc.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: loc.ClaimName, Namespace: image.Namespace}, pvc); err != nil {
+		return nil, fmt.Errorf("failed to get PersistentVolumeClaim %s: %w", loc.ClaimName, err)
+	}
+	if pvc.Status.Phase == corev1.ClaimLost {
+		return nil, fmt.Errorf("PersistentVolumeClaim %s has lost its volume", loc.ClaimName)
+	}
+
+	pod, err := r.ensureReaderPod(ctx, image, loc.ClaimName, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var stat *corev1.ContainerStateTerminated
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if cs.Name == "stat" {
+			stat = cs.State.Terminated
+		}
+	}
+	if stat == nil {
+		return nil, errVerificationPending
+	}
+	if stat.ExitCode != 0 {
+		// Start over on the next verification, the file may be written later
+		if err := r.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to delete reader pod: %w", err)
+		}
+		return nil, fmt.Errorf("cannot read %s on claim %s: %s", filePath, loc.ClaimName, strings.TrimSpace(stat.Message))
+	}
+
+	size, err := strconv.ParseInt(strings.TrimSpace(stat.Message), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("reader pod reported an invalid size %q", stat.Message)
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != imagelocation.ReaderContainerName || cs.Ready {
+			continue
+		}
+		if cs.State.Running != nil && time.Since(cs.State.Running.StartedAt.Time) > readerPodStartupGrace {
+			return nil, fmt.Errorf("%s is no longer readable on claim %s", filePath, loc.ClaimName)
+		}
+		return nil, errVerificationPending
+	}
+
+	return &verificationResult{size: imagelocation.FileSize(path.Base(filePath), size)}, nil
+}
+
+// ensureReaderPod returns the reader pod for the image, creating it or replacing it when the
+// location changed
+func (r *ImageReconciler) ensureReaderPod(
+	ctx context.Context,
+	image *automotivev1alpha1.Image,
+	claimName, filePath string,
+) (*corev1.Pod, error) {
+	location := claimName + ":" + filePath
+
+	pod := &corev1.Pod{}
+	err := r.Get(ctx, types.NamespacedName{Name: readerPodName(image), Namespace: image.Namespace}, pod)
+	switch {
+	case err == nil:
+		if pod.Annotations[readerLocationAnnotation] == location && pod.DeletionTimestamp == nil {
+			return pod, nil
+		}
+		if pod.DeletionTimestamp == nil {
+			if err := r.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to replace reader pod: %w", err)
+			}
+		}
+		return nil, errVerificationPending
+	case !k8serrors.IsNotFound(err):
+		return nil, fmt.Errorf("failed to get reader pod: %w", err)
+	}
+
+	filePathInPod := path.Join(imagelocation.ReaderMountPath, filePath)
+	pod = &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        readerPodName(image),
+			Namespace:   image.Namespace,
+			Labels:      imagelocation.ReaderPodLabels(image.Name),
+			Annotations: map[string]string{readerLocationAnnotation: location},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsUser:    ptr.To[int64](1000),
+				RunAsGroup:   ptr.To[int64](1000),
+				FSGroup:      ptr.To[int64](1000),
+				RunAsNonRoot: ptr.To(true),
+			},
+			InitContainers: []corev1.Container{
+				{
+					Name:                     "stat",
+					Image:                    readerImage,
+					Command:                  []string{"sh", "-c", "wc -c < \"$1\" > /dev/termination-log", "--", filePathInPod},
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Resources:                readerResources(),
+					VolumeMounts:             readerVolumeMounts(),
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:    imagelocation.ReaderContainerName,
+					Image:   readerImage,
+					Command: []string{"sleep", "infinity"},
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{
+							Exec: &corev1.ExecAction{Command: []string{"test", "-r", filePathInPod}},
+						},
+						PeriodSeconds: 60,
+					},
+					Resources:    readerResources(),
+					VolumeMounts: readerVolumeMounts(),
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "image",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: claimName,
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(image, pod, r.Scheme); err != nil {
+		return nil, fmt.Errorf("failed to set owner reference on reader pod: %w", err)
+	}
+	if err := r.Create(ctx, pod); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to create reader pod: %w", err)
+	}
+	return nil, errVerificationPending
+}
+
+// deleteReaderPod removes the reader pod left over from a previous pvc location
+func (r *ImageReconciler) deleteReaderPod(ctx context.Context, image *automotivev1alpha1.Image) error {
+	pod := &corev1.Pod{}
+	err := r.Get(ctx, types.NamespacedName{Name: readerPodName(image), Namespace: image.Namespace}, pod)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, pod))
+}
+
+func readerPodName(image *automotivev1alpha1.Image) string {
+	return image.Name + "-reader"
+}
+
+func readerResources() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("10m"),
+			corev1.ResourceMemory: resource.MustParse("16Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("200m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+	}
+}
+
+func readerVolumeMounts() []corev1.VolumeMount {
+	return []corev1.VolumeMount{
+		{
+			Name:      "image",
+			MountPath: imagelocation.ReaderMountPath,
+			ReadOnly:  true,
+		},
+	}
+}
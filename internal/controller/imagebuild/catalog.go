@@ -0,0 +1,149 @@
+package imagebuild
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/catalogimage"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// conditionCatalogPublished reports the outcome of PublishToCatalog
+	conditionCatalogPublished = "CatalogPublished"
+
+	// catalogPublishRetryInterval is how long to wait before retrying a failed catalog publish
+	catalogPublishRetryInterval = time.Minute
+)
+
+// catalogImageName renders the CatalogImage name for a build from its PublishToCatalog template
+func catalogImageName(imageBuild *automotivev1alpha1.ImageBuild) (string, error) {
+	nameTemplate := strings.TrimSpace(imageBuild.Spec.PublishToCatalog.NameTemplate)
+	if nameTemplate == "" {
+		return imageBuild.Name, nil
+	}
+
+	tmpl, err := template.New("catalogName").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid catalog name template: %w", err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, map[string]string{
+		"Name":         imageBuild.Name,
+		"Namespace":    imageBuild.Namespace,
+		"Distro":       imageBuild.Spec.Distro,
+		"Target":       imageBuild.Spec.Target,
+		"Architecture": imageBuild.Spec.Architecture,
+		"Mode":         imageBuild.Spec.Mode,
+		"ExportFormat": imageBuild.Spec.ExportFormat,
+	}); err != nil {
+		return "", fmt.Errorf("invalid catalog name template: %w", err)
+	}
+
+	name := strings.ToLower(strings.TrimSpace(out.String()))
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", fmt.Errorf("catalog name %q is not a valid resource name: %s", name, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
+
+// reconcileCatalogPublish publishes a completed build to the catalog once and records the
+// CatalogImage name. Failures do not fail the build; they are reported in the CatalogPublished
+// condition and publishing is retried periodically.
+func (r *ImageBuildReconciler) reconcileCatalogPublish(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+) (ctrl.Result, error) {
+	if imageBuild.Spec.PublishToCatalog == nil || imageBuild.Status.CatalogImageName != "" {
+		return ctrl.Result{}, nil
+	}
+
+	nsName := types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace}
+	log := r.Log.WithValues("imagebuild", nsName)
+
+	name, publishErr := r.publishToCatalog(ctx, imageBuild)
+
+	fresh := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(ctx, nsName, fresh); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	patch := client.MergeFrom(fresh.DeepCopy())
+
+	condition := metav1.Condition{
+		Type:               conditionCatalogPublished,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Published",
+		Message:            fmt.Sprintf("Published as CatalogImage %s", name),
+		ObservedGeneration: fresh.Generation,
+	}
+	if publishErr != nil {
+		log.Error(publishErr, "Failed to publish build to catalog")
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "PublishFailed"
+		condition.Message = publishErr.Error()
+	} else {
+		fresh.Status.CatalogImageName = name
+		log.Info("Published build to catalog", "catalogImage", name)
+	}
+	meta.SetStatusCondition(&fresh.Status.Conditions, condition)
+
+	if err := r.Status().Patch(ctx, fresh, patch); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to record catalog publish result: %w", err)
+	}
+	imageBuild.Status.CatalogImageName = fresh.Status.CatalogImageName
+
+	if publishErr != nil {
+		return ctrl.Result{RequeueAfter: catalogPublishRetryInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// publishToCatalog creates the build's CatalogImage, or adopts one left over from an earlier
+// attempt whose status update was lost, and returns its name
+func (r *ImageBuildReconciler) publishToCatalog(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+) (string, error) {
+	name, err := catalogImageName(imageBuild)
+	if err != nil {
+		return "", err
+	}
+
+	existing := &automotivev1alpha1.CatalogImage{}
+	err = r.Get(ctx, types.NamespacedName{Name: name, Namespace: imageBuild.Namespace}, existing)
+	switch {
+	case err == nil:
+		if existing.Labels[automotivev1alpha1.LabelSourceImageBuild] == imageBuild.Name {
+			return name, nil
+		}
+		return "", fmt.Errorf("CatalogImage %s already exists and was not published from this build", name)
+	case !errors.IsNotFound(err):
+		return "", fmt.Errorf("failed to get CatalogImage %s: %w", name, err)
+	}
+
+	spec := imageBuild.Spec.PublishToCatalog
+	publisher := catalogimage.NewPublisher(r.Client, r.getRegistryClient(), nil, r.Log)
+	if _, err := publisher.PublishFromImageBuild(ctx, imageBuild, name, spec.Tags, spec.TargetNotes); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// getRegistryClient returns the registry client (allows for testing)
+func (r *ImageBuildReconciler) getRegistryClient() catalogimage.RegistryClient {
+	if r.RegistryClient != nil {
+		return r.RegistryClient
+	}
+	return catalogimage.NewRegistryClient()
+}
@@ -4,10 +4,15 @@ package imagebuild
 import (
 	"context"
 	"fmt"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/notify"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/catalogimage"
 	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
 	pod "github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
@@ -19,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	toolscache "k8s.io/client-go/tools/cache"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -74,6 +80,37 @@ func buildArtifactFilename(distro, target, exportFormat, compression string) str
 	return fmt.Sprintf("%s-%s%s%s", distro, target, baseFormat, compressionExt)
 }
 
+// resolveArtifactFilename returns the artifact filename recorded in status, falling back to
+// the name derived from the build spec
+func resolveArtifactFilename(imageBuild *automotivev1alpha1.ImageBuild) string {
+	if name := strings.TrimSpace(imageBuild.Status.ArtifactFileName); name != "" {
+		return name
+	}
+	return buildArtifactFilename(
+		imageBuild.Spec.Distro,
+		imageBuild.Spec.Target,
+		imageBuild.Spec.ExportFormat,
+		imageBuild.Spec.Compression,
+	)
+}
+
+// hasPublishers returns true if at least one artifact publisher is configured
+func hasPublishers(imageBuild *automotivev1alpha1.ImageBuild) bool {
+	p := imageBuild.Spec.Publishers
+	return p != nil && (p.Registry != nil || p.S3 != nil)
+}
+
+// hasPostBuildTasks returns true if publishing or delta generation follows the build
+func hasPostBuildTasks(imageBuild *automotivev1alpha1.ImageBuild) bool {
+	return hasPublishers(imageBuild) || imageBuild.Spec.Delta != nil
+}
+
+// s3KeyPrefix returns the object key prefix under which the build's artifacts are uploaded
+func s3KeyPrefix(imageBuild *automotivev1alpha1.ImageBuild) string {
+	prefix := strings.Trim(imageBuild.Spec.Publishers.S3.Prefix, "/")
+	return path.Join(prefix, imageBuild.Namespace, imageBuild.Name)
+}
+
 // ImageBuildReconciler reconciles a ImageBuild object
 //
 //nolint:revive // Name follows Kubebuilder convention for reconcilers
@@ -81,11 +118,19 @@ type ImageBuildReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Log    logr.Logger
+
+	// RegistryClient is used when publishing builds to the catalog; defaults to the containers/image client
+	RegistryClient catalogimage.RegistryClient
+
+	// Notifier sends build phase changes to the configured notification targets; nil disables notifications
+	Notifier *notify.Notifier
 }
 
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=imagebuilds,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=imagebuilds/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=imagebuilds/finalizers,verbs=update
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=catalogimages,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=automotive.sdv.cloud.redhat.com,resources=catalogimages/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
@@ -241,6 +286,10 @@ func (r *ImageBuildReconciler) handleCompletedState(
 	ctx context.Context,
 	imageBuild *automotivev1alpha1.ImageBuild,
 ) (ctrl.Result, error) {
+	if result, err := r.reconcileCatalogPublish(ctx, imageBuild); err != nil || !result.IsZero() {
+		return result, err
+	}
+
 	if !imageBuild.Spec.ServeArtifact {
 		return ctrl.Result{}, nil
 	}
@@ -331,7 +380,8 @@ func (r *ImageBuildReconciler) checkBuildProgress(
 	}
 
 	if isPipelineRunSuccessful(pipelineRun) {
-		var artifactFileName string
+		var artifactFileName, artifactSHA256 string
+		var artifactSize int64
 		// Get results from the build-image task in the pipeline
 		for _, childStatus := range pipelineRun.Status.ChildReferences {
 			if childStatus.PipelineTaskName == "build-image" {
@@ -339,9 +389,13 @@ func (r *ImageBuildReconciler) checkBuildProgress(
 				trNS := types.NamespacedName{Name: childStatus.Name, Namespace: imageBuild.Namespace}
 				if err := r.Get(ctx, trNS, taskRun); err == nil {
 					for _, res := range taskRun.Status.Results {
-						if res.Name == "artifact-filename" && res.Value.StringVal != "" {
-							artifactFileName = res.Value.StringVal
-							break
+						switch res.Name {
+						case "artifact-filename":
+							artifactFileName = strings.TrimSpace(res.Value.StringVal)
+						case "artifact-sha256":
+							artifactSHA256 = strings.TrimSpace(res.Value.StringVal)
+						case "artifact-size":
+							artifactSize, _ = strconv.ParseInt(strings.TrimSpace(res.Value.StringVal), 10, 64)
 						}
 					}
 				}
@@ -372,23 +426,51 @@ func (r *ImageBuildReconciler) checkBuildProgress(
 		if artifactFileName != "" {
 			fresh.Status.ArtifactFileName = artifactFileName
 		}
+		if artifactSHA256 != "" {
+			fresh.Status.ArtifactSHA256 = artifactSHA256
+		}
+		if artifactSize > 0 {
+			fresh.Status.ArtifactSize = artifactSize
+		}
 
-		// Check if push is configured
-		if imageBuild.Spec.Publishers != nil && imageBuild.Spec.Publishers.Registry != nil {
-			// Start push task
-			if err := r.createPushTaskRun(ctx, imageBuild); err != nil {
-				log.Error(err, "Failed to create push TaskRun")
+		// Check if push or delta generation is configured
+		if hasPostBuildTasks(imageBuild) {
+			var failMsg string
+			publishers := imageBuild.Spec.Publishers
+			if publishers != nil && publishers.Registry != nil {
+				// Start push task
+				if err := r.createPushTaskRun(ctx, imageBuild); err != nil {
+					log.Error(err, "Failed to create push TaskRun")
+					failMsg = fmt.Sprintf("Failed to start push: %v", err)
+				}
+			}
+			if failMsg == "" && publishers != nil && publishers.S3 != nil {
+				if err := r.createS3UploadTaskRun(ctx, imageBuild, resolveArtifactFilename(fresh)); err != nil {
+					log.Error(err, "Failed to create S3 upload TaskRun")
+					failMsg = fmt.Sprintf("Failed to start push: %v", err)
+				}
+			}
+			if failMsg == "" && imageBuild.Spec.Delta != nil {
+				if err := r.createDeltaTaskRun(ctx, imageBuild, resolveArtifactFilename(fresh)); err != nil {
+					log.Error(err, "Failed to create delta TaskRun")
+					failMsg = fmt.Sprintf("Failed to start delta generation: %v", err)
+				}
+			}
+			if failMsg != "" {
 				fresh.Status.Phase = phaseFailed
-				fresh.Status.Message = fmt.Sprintf("Failed to start push: %v", err)
+				fresh.Status.Message = failMsg
 				if patchErr := r.Status().Patch(ctx, fresh, patch); patchErr != nil {
-					log.Error(patchErr, "Failed to patch status after push TaskRun creation failure")
+					log.Error(patchErr, "Failed to patch status after post-build TaskRun creation failure")
 					return ctrl.Result{}, patchErr
 				}
 				return ctrl.Result{}, nil
 			}
 
 			fresh.Status.Phase = "Pushing"
-			fresh.Status.Message = "Pushing artifact to registry"
+			fresh.Status.Message = "Publishing artifact"
+			if !hasPublishers(imageBuild) {
+				fresh.Status.Message = "Generating delta"
+			}
 			if err := r.Status().Patch(ctx, fresh, patch); err != nil {
 				log.Error(err, "Failed to patch status to Pushing")
 				return ctrl.Result{}, err
@@ -433,6 +515,18 @@ func (r *ImageBuildReconciler) startNewBuild(
 	ctx context.Context,
 	imageBuild *automotivev1alpha1.ImageBuild,
 ) (ctrl.Result, error) {
+	// Tenant namespaces receive the build pipeline from the OperatorConfig controller shortly after
+	// the build API creates them, so the first build in one may have to wait for it
+	pipeline := &tektonv1.Pipeline{}
+	pipelineKey := types.NamespacedName{Name: "automotive-build-pipeline", Namespace: imageBuild.Namespace}
+	if err := r.Get(ctx, pipelineKey, pipeline); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to get build pipeline: %w", err)
+		}
+		r.Log.Info("Waiting for the build pipeline to be provisioned", "namespace", imageBuild.Namespace)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
 	pvcName, err := r.getOrCreateWorkspacePVC(ctx, imageBuild)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to get or create workspace PVC: %w", err)
@@ -833,6 +927,131 @@ func (r *ImageBuildReconciler) createPushTaskRun(ctx context.Context, imageBuild
 	return nil
 }
 
+// createS3UploadTaskRun starts a TaskRun that uploads the artifact, its checksums and provenance
+// to the configured S3-compatible storage
+func (r *ImageBuildReconciler) createS3UploadTaskRun(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	artifactFileName string,
+) error {
+	log := r.Log.WithValues("imagebuild", types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace})
+	log.Info("Creating S3 upload TaskRun for ImageBuild")
+
+	if imageBuild.Spec.Publishers == nil || imageBuild.Spec.Publishers.S3 == nil {
+		return fmt.Errorf("no S3 publisher configured")
+	}
+	s3 := imageBuild.Spec.Publishers.S3
+
+	uploadTask := tasks.GenerateUploadArtifactS3Task(OperatorNamespace)
+
+	stringParam := func(name, value string) tektonv1.Param {
+		return tektonv1.Param{
+			Name: name,
+			Value: tektonv1.ParamValue{
+				Type:      tektonv1.ParamTypeString,
+				StringVal: value,
+			},
+		}
+	}
+
+	region := s3.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	params := []tektonv1.Param{
+		stringParam("build-name", imageBuild.Name),
+		stringParam("distro", imageBuild.Spec.Distro),
+		stringParam("target", imageBuild.Spec.Target),
+		stringParam("architecture", imageBuild.Spec.Architecture),
+		stringParam("export-format", imageBuild.Spec.ExportFormat),
+		stringParam("endpoint", s3.Endpoint),
+		stringParam("region", region),
+		stringParam("bucket", s3.Bucket),
+		stringParam("key-prefix", s3KeyPrefix(imageBuild)),
+		stringParam("secret-ref", s3.Secret),
+		stringParam("insecure", fmt.Sprintf("%t", s3.InsecureSkipTLSVerify)),
+		stringParam("artifact-filename", artifactFileName),
+	}
+
+	taskRun := &tektonv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-upload-s3-", imageBuild.Name),
+			Namespace:    imageBuild.Namespace,
+			Labels: map[string]string{
+				tektonv1.ManagedByLabelKey:                        "automotive-dev-operator",
+				"automotive.sdv.cloud.redhat.com/imagebuild-name": imageBuild.Name,
+				"automotive.sdv.cloud.redhat.com/task-type":       "upload-s3",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: imageBuild.APIVersion,
+					Kind:       imageBuild.Kind,
+					Name:       imageBuild.Name,
+					UID:        imageBuild.UID,
+					Controller: ptr.To(true),
+				},
+			},
+		},
+		Spec: tektonv1.TaskRunSpec{
+			TaskSpec: &uploadTask.Spec,
+			Params:   params,
+			Workspaces: []tektonv1.WorkspaceBinding{
+				{
+					Name: "shared-workspace",
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: imageBuild.Status.PVCName,
+					},
+				},
+			},
+		},
+	}
+
+	if err := r.Create(ctx, taskRun); err != nil {
+		return fmt.Errorf("failed to create S3 upload TaskRun: %w", err)
+	}
+
+	fresh := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(ctx, types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace}, fresh); err != nil {
+		return fmt.Errorf("failed to get fresh ImageBuild: %w", err)
+	}
+
+	patch := client.MergeFrom(fresh.DeepCopy())
+	fresh.Status.S3UploadTaskRunName = taskRun.Name
+	if err := r.Status().Patch(ctx, fresh, patch); err != nil {
+		return fmt.Errorf("failed to update ImageBuild with S3 upload TaskRun name: %w", err)
+	}
+
+	log.Info("Successfully created S3 upload TaskRun", "name", taskRun.Name)
+	return nil
+}
+
+// getPublishTaskRun returns the publish TaskRun with the given name, starting it through create
+// when it has not been created yet or was deleted. A nil TaskRun means the caller should requeue.
+func (r *ImageBuildReconciler) getPublishTaskRun(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	taskRunName string,
+	create func() error,
+) (*tektonv1.TaskRun, error) {
+	if taskRunName != "" {
+		taskRun := &tektonv1.TaskRun{}
+		err := r.Get(ctx, types.NamespacedName{Name: taskRunName, Namespace: imageBuild.Namespace}, taskRun)
+		if err == nil {
+			return taskRun, nil
+		}
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		// TaskRun was deleted, recreate it below
+	}
+
+	if err := create(); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
 func (r *ImageBuildReconciler) handlePushingState(
 	ctx context.Context,
 	imageBuild *automotivev1alpha1.ImageBuild,
@@ -840,9 +1059,25 @@ func (r *ImageBuildReconciler) handlePushingState(
 	nsName := types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace}
 	log := r.Log.WithValues("imagebuild", nsName)
 
-	if imageBuild.Status.PushTaskRunName == "" {
-		// No push TaskRun yet, create one
-		if err := r.createPushTaskRun(ctx, imageBuild); err != nil {
+	publishers := imageBuild.Spec.Publishers
+	if !hasPostBuildTasks(imageBuild) {
+		if err := r.updateStatus(ctx, imageBuild, phaseFailed, "No publishers configured"); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	type publishRun struct {
+		taskRun *tektonv1.TaskRun
+		failMsg string
+	}
+	var runs []publishRun
+
+	if publishers != nil && publishers.Registry != nil {
+		taskRun, err := r.getPublishTaskRun(ctx, imageBuild, imageBuild.Status.PushTaskRunName, func() error {
+			return r.createPushTaskRun(ctx, imageBuild)
+		})
+		if err != nil {
 			log.Error(err, "Failed to create push TaskRun")
 			msg := fmt.Sprintf("Failed to create push TaskRun: %v", err)
 			if statusErr := r.updateStatus(ctx, imageBuild, phaseFailed, msg); statusErr != nil {
@@ -851,30 +1086,56 @@ func (r *ImageBuildReconciler) handlePushingState(
 			}
 			return ctrl.Result{}, nil
 		}
-		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+		if taskRun == nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+		}
+		runs = append(runs, publishRun{taskRun: taskRun, failMsg: "Push to registry failed"})
 	}
 
-	// Check push TaskRun status
-	taskRun := &tektonv1.TaskRun{}
-	err := r.Get(ctx, types.NamespacedName{
-		Name:      imageBuild.Status.PushTaskRunName,
-		Namespace: imageBuild.Namespace,
-	}, taskRun)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// TaskRun was deleted, try to recreate
-			imageBuild.Status.PushTaskRunName = ""
-			if statusErr := r.Status().Update(ctx, imageBuild); statusErr != nil {
-				log.Error(statusErr, "Failed to clear PushTaskRunName in status")
+	if publishers != nil && publishers.S3 != nil {
+		taskRun, err := r.getPublishTaskRun(ctx, imageBuild, imageBuild.Status.S3UploadTaskRunName, func() error {
+			return r.createS3UploadTaskRun(ctx, imageBuild, resolveArtifactFilename(imageBuild))
+		})
+		if err != nil {
+			log.Error(err, "Failed to create S3 upload TaskRun")
+			msg := fmt.Sprintf("Failed to create S3 upload TaskRun: %v", err)
+			if statusErr := r.updateStatus(ctx, imageBuild, phaseFailed, msg); statusErr != nil {
+				log.Error(statusErr, "Failed to update status after S3 upload TaskRun creation failure")
 				return ctrl.Result{}, statusErr
 			}
-			return ctrl.Result{Requeue: true}, nil
+			return ctrl.Result{}, nil
 		}
-		return ctrl.Result{}, err
+		if taskRun == nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+		}
+		runs = append(runs, publishRun{taskRun: taskRun, failMsg: "Upload to S3 storage failed"})
+	}
+
+	var deltaTaskRun *tektonv1.TaskRun
+	if imageBuild.Spec.Delta != nil {
+		taskRun, err := r.getPublishTaskRun(ctx, imageBuild, imageBuild.Status.DeltaTaskRunName, func() error {
+			return r.createDeltaTaskRun(ctx, imageBuild, resolveArtifactFilename(imageBuild))
+		})
+		if err != nil {
+			log.Error(err, "Failed to create delta TaskRun")
+			msg := fmt.Sprintf("Failed to create delta TaskRun: %v", err)
+			if statusErr := r.updateStatus(ctx, imageBuild, phaseFailed, msg); statusErr != nil {
+				log.Error(statusErr, "Failed to update status after delta TaskRun creation failure")
+				return ctrl.Result{}, statusErr
+			}
+			return ctrl.Result{}, nil
+		}
+		if taskRun == nil {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+		}
+		deltaTaskRun = taskRun
+		runs = append(runs, publishRun{taskRun: taskRun, failMsg: "Delta generation failed"})
 	}
 
-	if !isTaskRunCompleted(taskRun) {
-		return ctrl.Result{RequeueAfter: time.Second * 15}, nil
+	for _, run := range runs {
+		if !isTaskRunCompleted(run.taskRun) {
+			return ctrl.Result{RequeueAfter: time.Second * 15}, nil
+		}
 	}
 
 	// Push completed - cleanup transient secrets and update status
@@ -887,12 +1148,30 @@ func (r *ImageBuildReconciler) handlePushingState(
 
 	patch := client.MergeFrom(fresh.DeepCopy())
 
-	if isTaskRunSuccessful(taskRun) {
+	succeeded := true
+	var failures []string
+	for _, run := range runs {
+		if !isTaskRunSuccessful(run.taskRun) {
+			succeeded = false
+			failures = append(failures, run.failMsg)
+		}
+	}
+
+	if succeeded {
 		fresh.Status.Phase = phaseCompleted
 		fresh.Status.Message = "Build and push completed successfully"
+		if !hasPublishers(imageBuild) {
+			fresh.Status.Message = "Build completed successfully"
+		}
+		if publishers != nil && publishers.S3 != nil {
+			fresh.Status.S3ArtifactKey = path.Join(s3KeyPrefix(fresh), resolveArtifactFilename(fresh))
+		}
+		if deltaTaskRun != nil {
+			fresh.Status.DeltaFileName, fresh.Status.DeltaSHA256 = deltaResult(deltaTaskRun)
+		}
 	} else {
 		fresh.Status.Phase = phaseFailed
-		fresh.Status.Message = "Push to registry failed"
+		fresh.Status.Message = strings.Join(failures, "; ")
 	}
 
 	if fresh.Status.CompletionTime == nil {
@@ -906,7 +1185,7 @@ func (r *ImageBuildReconciler) handlePushingState(
 	}
 
 	// Update artifact info if serving
-	if imageBuild.Spec.ServeArtifact && isTaskRunSuccessful(taskRun) {
+	if imageBuild.Spec.ServeArtifact && succeeded {
 		return r.updateArtifactInfo(ctx, imageBuild)
 	}
 
@@ -1272,9 +1551,36 @@ func (r *ImageBuildReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		builder = builder.Owns(&routev1.Route{})
 	}
 
+	if r.Notifier != nil {
+		informer, err := mgr.GetCache().GetInformer(context.Background(), &automotivev1alpha1.ImageBuild{})
+		if err != nil {
+			return fmt.Errorf("failed to get ImageBuild informer for notifications: %w", err)
+		}
+		if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			UpdateFunc: r.notifyPhaseChange,
+		}); err != nil {
+			return fmt.Errorf("failed to watch ImageBuild phase changes: %w", err)
+		}
+	}
+
 	return builder.Complete(r)
 }
 
+// notifyPhaseChange sends a notification when an ImageBuild moves to another phase
+func (r *ImageBuildReconciler) notifyPhaseChange(oldObj, newObj any) {
+	previous, ok := oldObj.(*automotivev1alpha1.ImageBuild)
+	if !ok {
+		return
+	}
+	build, ok := newObj.(*automotivev1alpha1.ImageBuild)
+	if !ok {
+		return
+	}
+	if event, changed := notify.BuildPhaseChange(previous, build); changed {
+		r.Notifier.Notify(event)
+	}
+}
+
 func isTaskRunCompleted(taskRun *tektonv1.TaskRun) bool {
 	return taskRun.Status.CompletionTime != nil
 }
@@ -0,0 +1,264 @@
+package imagebuild
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/catalogimage"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	deltaMethodZstd   = "zstd"
+	deltaMethodBsdiff = "bsdiff"
+	deltaMethodOstree = "ostree"
+
+	// deltaBasePullDir is where the delta task pulls a base image from a registry
+	deltaBasePullDir = "/workspace/shared/.delta-work/base"
+)
+
+// deltaMethod returns the configured delta algorithm, defaulting to zstd
+func deltaMethod(imageBuild *automotivev1alpha1.ImageBuild) string {
+	if m := strings.TrimSpace(imageBuild.Spec.Delta.Method); m != "" {
+		return m
+	}
+	return deltaMethodZstd
+}
+
+// deltaFileName returns the name of the delta from baseName to the build's artifact.
+// The compression suffix is dropped because deltas are computed on uncompressed content.
+func deltaFileName(imageBuild *automotivev1alpha1.ImageBuild, baseName string) string {
+	name := resolveArtifactFilename(imageBuild)
+	for _, ext := range []string{".gz", ".lz4", ".xz", ".tar"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	ext := ".zst"
+	switch deltaMethod(imageBuild) {
+	case deltaMethodBsdiff:
+		ext = ".bsdiff"
+	case deltaMethodOstree:
+		ext = ".delta"
+	}
+	return fmt.Sprintf("%s.from-%s%s", name, baseName, ext)
+}
+
+// deltaBase describes where the delta task finds its base image
+type deltaBase struct {
+	name       string
+	path       string
+	image      string
+	authSecret string
+	workspace  *tektonv1.WorkspaceBinding
+}
+
+// resolveDeltaBase looks up the base ImageBuild or CatalogImage of the build's delta
+func (r *ImageBuildReconciler) resolveDeltaBase(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+) (*deltaBase, error) {
+	spec := imageBuild.Spec.Delta
+	baseBuildName := strings.TrimSpace(spec.BaseImageBuild)
+	baseCatalogName := strings.TrimSpace(spec.BaseCatalogImage)
+
+	switch {
+	case (baseBuildName == "") == (baseCatalogName == ""):
+		return nil, fmt.Errorf("exactly one of baseImageBuild or baseCatalogImage must be set")
+
+	case baseBuildName != "":
+		if baseBuildName == imageBuild.Name {
+			return nil, fmt.Errorf("an ImageBuild cannot be its own delta base")
+		}
+		base := &automotivev1alpha1.ImageBuild{}
+		nsName := types.NamespacedName{Name: baseBuildName, Namespace: imageBuild.Namespace}
+		if err := r.Get(ctx, nsName, base); err != nil {
+			return nil, fmt.Errorf("failed to get base ImageBuild %s: %w", baseBuildName, err)
+		}
+		if base.Status.Phase != phaseCompleted {
+			return nil, fmt.Errorf("base ImageBuild %s has not completed", baseBuildName)
+		}
+		if base.Status.PVCName == "" {
+			return nil, fmt.Errorf("base ImageBuild %s has no workspace", baseBuildName)
+		}
+		return &deltaBase{
+			name: base.Name,
+			path: "/workspace/base/" + resolveArtifactFilename(base),
+			workspace: &tektonv1.WorkspaceBinding{
+				Name: "base-workspace",
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: base.Status.PVCName,
+					ReadOnly:  true,
+				},
+			},
+		}, nil
+
+	default:
+		catalogImage := &automotivev1alpha1.CatalogImage{}
+		nsName := types.NamespacedName{Name: baseCatalogName, Namespace: imageBuild.Namespace}
+		if err := r.Get(ctx, nsName, catalogImage); err != nil {
+			return nil, fmt.Errorf("failed to get base CatalogImage %s: %w", baseCatalogName, err)
+		}
+		// End-of-life images are no longer verified; the build API also refuses them and warns about
+		// deprecated ones when the build is created
+		switch catalogImage.EffectiveLifecycleState() {
+		case automotivev1alpha1.LifecycleEOL:
+			return nil, fmt.Errorf("base CatalogImage %s is end-of-life", baseCatalogName)
+		case automotivev1alpha1.LifecycleDeprecated:
+			r.Log.Info("Delta base CatalogImage is deprecated", "imagebuild", imageBuild.Name,
+				"namespace", imageBuild.Namespace, "base", baseCatalogName)
+		}
+
+		base := &deltaBase{
+			name:  catalogImage.Name,
+			path:  deltaBasePullDir,
+			image: catalogimage.PullReference(catalogImage),
+		}
+		// The mirror is pulled with the credentials of its spec.mirror, or with the pipeline's own
+		// credentials for the cluster-wide mirror
+		authRef := catalogImage.Spec.AuthSecretRef
+		if catalogImage.MirrorArtifact() != nil {
+			authRef = nil
+			if catalogImage.Spec.Mirror != nil {
+				authRef = catalogImage.Spec.Mirror.AuthSecretRef
+			}
+		}
+		if ref := authRef; ref != nil {
+			if ref.Namespace != "" && ref.Namespace != imageBuild.Namespace {
+				return nil, fmt.Errorf("auth secret of CatalogImage %s must be in namespace %s", baseCatalogName,
+					imageBuild.Namespace)
+			}
+			secret := &corev1.Secret{}
+			secretName := types.NamespacedName{Name: ref.Name, Namespace: imageBuild.Namespace}
+			if err := r.Get(ctx, secretName, secret); err != nil {
+				return nil, fmt.Errorf("failed to get auth secret of CatalogImage %s: %w", baseCatalogName, err)
+			}
+			if secret.Type != corev1.SecretTypeDockerConfigJson {
+				return nil, fmt.Errorf("auth secret %s must be of type %s to pull a delta base",
+					ref.Name, corev1.SecretTypeDockerConfigJson)
+			}
+			base.authSecret = ref.Name
+		}
+		return base, nil
+	}
+}
+
+// createDeltaTaskRun starts a TaskRun that writes a binary delta from the base image to the
+// build's artifact into the build workspace
+func (r *ImageBuildReconciler) createDeltaTaskRun(
+	ctx context.Context,
+	imageBuild *automotivev1alpha1.ImageBuild,
+	artifactFileName string,
+) error {
+	log := r.Log.WithValues("imagebuild", types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace})
+	log.Info("Creating delta TaskRun for ImageBuild")
+
+	if imageBuild.Spec.Delta == nil {
+		return fmt.Errorf("no delta configured")
+	}
+
+	base, err := r.resolveDeltaBase(ctx, imageBuild)
+	if err != nil {
+		return err
+	}
+
+	deltaTask := tasks.GenerateCreateDeltaTask(OperatorNamespace, base.image, base.authSecret)
+
+	stringParam := func(name, value string) tektonv1.Param {
+		return tektonv1.Param{
+			Name: name,
+			Value: tektonv1.ParamValue{
+				Type:      tektonv1.ParamTypeString,
+				StringVal: value,
+			},
+		}
+	}
+
+	params := []tektonv1.Param{
+		stringParam("artifact-filename", artifactFileName),
+		stringParam("base-path", base.path),
+		stringParam("delta-filename", deltaFileName(imageBuild, base.name)),
+		stringParam("method", deltaMethod(imageBuild)),
+	}
+	if base.image != "" {
+		params = append(params, stringParam("base-image", base.image))
+	}
+
+	workspaces := []tektonv1.WorkspaceBinding{
+		{
+			Name: "shared-workspace",
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: imageBuild.Status.PVCName,
+			},
+		},
+	}
+	if base.workspace != nil {
+		workspaces = append(workspaces, *base.workspace)
+	}
+
+	taskRun := &tektonv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-delta-", imageBuild.Name),
+			Namespace:    imageBuild.Namespace,
+			Labels: map[string]string{
+				tektonv1.ManagedByLabelKey:                        "automotive-dev-operator",
+				"automotive.sdv.cloud.redhat.com/imagebuild-name": imageBuild.Name,
+				"automotive.sdv.cloud.redhat.com/task-type":       "delta",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: imageBuild.APIVersion,
+					Kind:       imageBuild.Kind,
+					Name:       imageBuild.Name,
+					UID:        imageBuild.UID,
+					Controller: ptr.To(true),
+				},
+			},
+		},
+		Spec: tektonv1.TaskRunSpec{
+			TaskSpec:   &deltaTask.Spec,
+			Params:     params,
+			Workspaces: workspaces,
+		},
+	}
+
+	if err := r.Create(ctx, taskRun); err != nil {
+		return fmt.Errorf("failed to create delta TaskRun: %w", err)
+	}
+
+	fresh := &automotivev1alpha1.ImageBuild{}
+	if err := r.Get(ctx, types.NamespacedName{Name: imageBuild.Name, Namespace: imageBuild.Namespace}, fresh); err != nil {
+		return fmt.Errorf("failed to get fresh ImageBuild: %w", err)
+	}
+
+	patch := client.MergeFrom(fresh.DeepCopy())
+	fresh.Status.DeltaTaskRunName = taskRun.Name
+	if err := r.Status().Patch(ctx, fresh, patch); err != nil {
+		return fmt.Errorf("failed to update ImageBuild with delta TaskRun name: %w", err)
+	}
+
+	log.Info("Successfully created delta TaskRun", "name", taskRun.Name, "base", base.name)
+	return nil
+}
+
+// deltaResult returns the delta file name and checksum produced by a successful delta TaskRun
+func deltaResult(taskRun *tektonv1.TaskRun) (fileName, sha256 string) {
+	for _, p := range taskRun.Spec.Params {
+		if p.Name == "delta-filename" {
+			fileName = strings.TrimSpace(p.Value.StringVal)
+		}
+	}
+	for _, res := range taskRun.Status.Results {
+		if res.Name == "delta-sha256" {
+			sha256 = strings.TrimSpace(res.Value.StringVal)
+		}
+	}
+	return fileName, sha256
+}
@@ -4,6 +4,7 @@ package operatorconfig
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
@@ -17,6 +18,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
 	"github.com/centos-automotive-suite/automotive-dev-operator/internal/common/tasks"
@@ -110,6 +112,10 @@ type OperatorConfigReconciler struct {
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=tekton.dev,resources=tasks;pipelines;pipelineruns,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind,resourceNames=ado-tenant-role
 
 // Reconcile manages the OperatorConfig resource lifecycle.
 func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -142,6 +148,10 @@ func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// Handle deletion
 	if !config.DeletionTimestamp.IsZero() {
 		log.Info("Handling deletion")
+		if err := r.cleanupTenants(ctx, config.Name); err != nil {
+			log.Error(err, "Failed to cleanup tenant namespaces")
+			return ctrl.Result{}, err
+		}
 		if err := r.cleanupOSBuilds(ctx); err != nil {
 			log.Error(err, "Failed to cleanup OSBuilds")
 			return ctrl.Result{}, err
@@ -199,6 +209,22 @@ func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 	}
 
+	// Provision tenant namespaces
+	tenantNamespaces, err := r.reconcileTenants(ctx, config)
+	if err != nil {
+		log.Error(err, "Failed to reconcile tenant namespaces")
+		if config.Status.Phase != phaseFailed {
+			config.Status.Phase = phaseFailed
+			config.Status.Message = fmt.Sprintf("Failed to provision tenant namespaces: %v", err)
+			_ = r.Status().Update(ctx, config)
+		}
+		return ctrl.Result{}, err
+	}
+	if !slices.Equal(config.Status.TenantNamespaces, tenantNamespaces) {
+		config.Status.TenantNamespaces = tenantNamespaces
+		statusChanged = true
+	}
+
 	// Detect Jumpstarter availability
 	jumpstarterAvailable := r.detectJumpstarter(ctx)
 	if config.Status.JumpstarterAvailable != jumpstarterAvailable {
@@ -237,7 +263,7 @@ func (r *OperatorConfigReconciler) deployBuildAPI(ctx context.Context, owner *au
 
 	// Create/update build-api deployment
 	r.Log.Info("Creating/updating build-api deployment")
-	buildAPIDeployment := r.buildBuildAPIDeployment(isOpenShift)
+	buildAPIDeployment := r.buildBuildAPIDeployment(isOpenShift, owner.Spec.BuildAPI)
 	if err := r.createOrUpdate(ctx, buildAPIDeployment, owner); err != nil {
 		r.Log.Error(err, "Failed to create/update build-api deployment")
 		return fmt.Errorf("failed to create/update build-api deployment: %w", err)
@@ -401,6 +427,22 @@ func (r *OperatorConfigReconciler) deployOSBuilds(
 		return fmt.Errorf("failed to deploy build-api: %w", err)
 	}
 
+	if err := r.deployBuildPipeline(ctx, config, operatorNamespace); err != nil {
+		return err
+	}
+
+	r.Log.Info("OSBuilds deployment completed successfully")
+	return nil
+}
+
+// deployBuildPipeline creates or updates the Tekton tasks and pipeline that builds in namespace run.
+// Owner references cannot cross namespaces, so copies in tenant namespaces are only labeled with the
+// OperatorConfig managing them and removed by cleanupTenants.
+func (r *OperatorConfigReconciler) deployBuildPipeline(
+	ctx context.Context,
+	config *automotivev1alpha1.OperatorConfig,
+	namespace string,
+) error {
 	// Convert OSBuildsConfig to BuildConfig for task generation
 	var buildConfig *tasks.BuildConfig
 	if config.Spec.OSBuilds != nil {
@@ -415,16 +457,18 @@ func (r *OperatorConfigReconciler) deployOSBuilds(
 
 	// Generate and deploy Tekton tasks
 	tektonTasks := []*tektonv1.Task{
-		tasks.GenerateBuildAutomotiveImageTask(operatorNamespace, buildConfig, ""),
-		tasks.GeneratePushArtifactRegistryTask(operatorNamespace),
-		tasks.GeneratePrepareBuilderTask(operatorNamespace),
+		tasks.GenerateBuildAutomotiveImageTask(namespace, buildConfig, ""),
+		tasks.GeneratePushArtifactRegistryTask(namespace),
+		tasks.GeneratePrepareBuilderTask(namespace),
 	}
 
 	for _, task := range tektonTasks {
-		task.Labels["automotive.sdv.cloud.redhat.com/managed-by"] = config.Name
+		task.Labels[managedByLabel] = config.Name
 
-		if err := controllerutil.SetControllerReference(config, task, r.Scheme); err != nil {
-			return fmt.Errorf("failed to set controller reference on task: %w", err)
+		if namespace == operatorNamespace {
+			if err := controllerutil.SetControllerReference(config, task, r.Scheme); err != nil {
+				return fmt.Errorf("failed to set controller reference on task: %w", err)
+			}
 		}
 
 		if err := r.createOrUpdateTask(ctx, task); err != nil {
@@ -436,11 +480,13 @@ func (r *OperatorConfigReconciler) deployOSBuilds(
 	}
 
 	// Generate and deploy Tekton pipeline
-	pipeline := tasks.GenerateTektonPipeline("automotive-build-pipeline", operatorNamespace)
-	pipeline.Labels["automotive.sdv.cloud.redhat.com/managed-by"] = config.Name
+	pipeline := tasks.GenerateTektonPipeline("automotive-build-pipeline", namespace)
+	pipeline.Labels[managedByLabel] = config.Name
 
-	if err := controllerutil.SetControllerReference(config, pipeline, r.Scheme); err != nil {
-		return fmt.Errorf("failed to set controller reference on pipeline: %w", err)
+	if namespace == operatorNamespace {
+		if err := controllerutil.SetControllerReference(config, pipeline, r.Scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference on pipeline: %w", err)
+		}
 	}
 
 	if err := r.createOrUpdatePipeline(ctx, pipeline); err != nil {
@@ -448,7 +494,6 @@ func (r *OperatorConfigReconciler) deployOSBuilds(
 		return fmt.Errorf("failed to create/update pipeline: %w", err)
 	}
 
-	r.Log.Info("OSBuilds deployment completed successfully")
 	return nil
 }
 
@@ -536,5 +581,6 @@ func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&tektonv1.Task{}).
 		Owns(&tektonv1.Pipeline{}).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(mapTenantNamespace)).
 		Complete(r)
 }
@@ -12,6 +12,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
 )
 
 const (
@@ -129,8 +131,10 @@ func (r *OperatorConfigReconciler) buildBuildAPIContainers(isOpenShift bool) []c
 	return containers
 }
 
-func (r *OperatorConfigReconciler) buildBuildAPIDeployment(isOpenShift bool) *appsv1.Deployment {
-	return &appsv1.Deployment{
+func (r *OperatorConfigReconciler) buildBuildAPIDeployment(
+	isOpenShift bool, cfg *automotivev1alpha1.BuildAPIConfig,
+) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "ado-build-api",
 			Namespace: operatorNamespace,
@@ -183,6 +187,32 @@ func (r *OperatorConfigReconciler) buildBuildAPIDeployment(isOpenShift bool) *ap
 			},
 		},
 	}
+
+	if cfg != nil && cfg.Audit != nil && cfg.Audit.File != nil {
+		addBuildAPIAuditVolume(deployment, cfg.Audit.File.ClaimName)
+	}
+	return deployment
+}
+
+// addBuildAPIAuditVolume mounts the audit log claim into the build-api container. The claim is
+// usually ReadWriteOnce, so the old pod is stopped before a new one starts.
+func addBuildAPIAuditVolume(deployment *appsv1.Deployment, claimName string) {
+	deployment.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	podSpec := &deployment.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "audit-log",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+		},
+	})
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == "build-api" {
+			podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+				Name:      "audit-log",
+				MountPath: automotivev1alpha1.BuildAPIAuditLogDir,
+			})
+		}
+	}
 }
 
 func (r *OperatorConfigReconciler) buildBuildAPIService(isOpenShift bool) *corev1.Service {
@@ -0,0 +1,200 @@
+package operatorconfig
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+)
+
+const (
+	managedByLabel    = "automotive.sdv.cloud.redhat.com/managed-by"
+	tenantQuotaName   = "ado-tenant-quota"
+	tenantMembersName = "ado-tenant-members"
+)
+
+// tenancyEnabled reports whether builds run in per-tenant namespaces
+func tenancyEnabled(config *automotivev1alpha1.OperatorConfig) bool {
+	return config.Spec.Tenancy != nil && config.Spec.Tenancy.Enabled &&
+		config.Spec.OSBuilds != nil && config.Spec.OSBuilds.Enabled
+}
+
+// reconcileTenants provisions the build pipeline and quota into every tenant namespace: the team
+// namespaces of the tenancy config, created here, and the personal namespaces the build API labels
+// on first use. It returns the sorted names of the provisioned namespaces.
+func (r *OperatorConfigReconciler) reconcileTenants(
+	ctx context.Context,
+	config *automotivev1alpha1.OperatorConfig,
+) ([]string, error) {
+	if !tenancyEnabled(config) {
+		return nil, r.cleanupTenants(ctx, config.Name)
+	}
+
+	teamGroups := map[string][]string{}
+	for _, team := range config.Spec.Tenancy.Teams {
+		if err := r.ensureTenantNamespace(ctx, team.Namespace); err != nil {
+			return nil, err
+		}
+		teamGroups[team.Namespace] = append(teamGroups[team.Namespace], team.Group)
+	}
+	for namespace, groups := range teamGroups {
+		if err := r.reconcileTenantMembers(ctx, config, namespace, groups); err != nil {
+			return nil, fmt.Errorf("failed to bind tenant role in namespace %s: %w", namespace, err)
+		}
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces, client.HasLabels{automotivev1alpha1.TenantNamespaceLabel}); err != nil {
+		return nil, fmt.Errorf("failed to list tenant namespaces: %w", err)
+	}
+
+	var provisioned []string
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if !ns.DeletionTimestamp.IsZero() || ns.Name == operatorNamespace {
+			continue
+		}
+		if err := r.deployBuildPipeline(ctx, config, ns.Name); err != nil {
+			return nil, fmt.Errorf("failed to provision tenant namespace %s: %w", ns.Name, err)
+		}
+		if err := r.reconcileTenantQuota(ctx, config, ns.Name); err != nil {
+			return nil, fmt.Errorf("failed to apply quota to tenant namespace %s: %w", ns.Name, err)
+		}
+		provisioned = append(provisioned, ns.Name)
+	}
+	sort.Strings(provisioned)
+	return provisioned, nil
+}
+
+// ensureTenantNamespace creates a tenant namespace, or labels an existing one as a tenant
+func (r *OperatorConfigReconciler) ensureTenantNamespace(ctx context.Context, name string) error {
+	ns := &corev1.Namespace{}
+	err := r.Get(ctx, client.ObjectKey{Name: name}, ns)
+	if errors.IsNotFound(err) {
+		ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{automotivev1alpha1.TenantNamespaceLabel: "true"},
+		}}
+		if err := r.Create(ctx, ns); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create tenant namespace %s: %w", name, err)
+		}
+		r.Log.Info("Created tenant namespace", "namespace", name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get tenant namespace %s: %w", name, err)
+	}
+	if _, ok := ns.Labels[automotivev1alpha1.TenantNamespaceLabel]; ok {
+		return nil
+	}
+
+	patch := client.MergeFrom(ns.DeepCopy())
+	if ns.Labels == nil {
+		ns.Labels = map[string]string{}
+	}
+	ns.Labels[automotivev1alpha1.TenantNamespaceLabel] = "true"
+	if err := r.Patch(ctx, ns, patch); err != nil {
+		return fmt.Errorf("failed to label tenant namespace %s: %w", name, err)
+	}
+	return nil
+}
+
+// reconcileTenantQuota applies the configured quota to a tenant namespace, removing it once unset
+func (r *OperatorConfigReconciler) reconcileTenantQuota(
+	ctx context.Context,
+	config *automotivev1alpha1.OperatorConfig,
+	namespace string,
+) error {
+	if len(config.Spec.Tenancy.Quota) == 0 {
+		quota := &corev1.ResourceQuota{}
+		quota.Name = tenantQuotaName
+		quota.Namespace = namespace
+		if err := r.Delete(ctx, quota); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tenantQuotaName,
+			Namespace: namespace,
+			Labels:    map[string]string{managedByLabel: config.Name},
+		},
+		Spec: corev1.ResourceQuotaSpec{Hard: config.Spec.Tenancy.Quota.DeepCopy()},
+	}
+	return r.createOrUpdate(ctx, quota, config)
+}
+
+// reconcileTenantMembers binds the tenant role to the groups mapped to a team namespace
+func (r *OperatorConfigReconciler) reconcileTenantMembers(
+	ctx context.Context,
+	config *automotivev1alpha1.OperatorConfig,
+	namespace string,
+	groups []string,
+) error {
+	subjects := make([]rbacv1.Subject, 0, len(groups))
+	for _, group := range groups {
+		subjects = append(subjects, rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: group})
+	}
+
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tenantMembersName,
+			Namespace: namespace,
+			Labels:    map[string]string{managedByLabel: config.Name},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     automotivev1alpha1.TenantClusterRole,
+		},
+		Subjects: subjects,
+	}
+	return r.createOrUpdate(ctx, binding, config)
+}
+
+// cleanupTenants removes the build pipeline, quota and team role bindings from all tenant namespaces. The namespaces
+// themselves are kept, as they hold the tenants' builds.
+func (r *OperatorConfigReconciler) cleanupTenants(ctx context.Context, configName string) error {
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces, client.HasLabels{automotivev1alpha1.TenantNamespaceLabel}); err != nil {
+		return fmt.Errorf("failed to list tenant namespaces: %w", err)
+	}
+
+	for _, ns := range namespaces.Items {
+		if ns.Name == operatorNamespace {
+			continue
+		}
+		opts := []client.DeleteAllOfOption{
+			client.InNamespace(ns.Name),
+			client.MatchingLabels{managedByLabel: configName},
+		}
+		for _, obj := range []client.Object{
+			&tektonv1.Task{}, &tektonv1.Pipeline{}, &corev1.ResourceQuota{}, &rbacv1.RoleBinding{},
+		} {
+			if err := r.DeleteAllOf(ctx, obj, opts...); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to clean up tenant namespace %s: %w", ns.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// mapTenantNamespace requeues the OperatorConfig when a tenant namespace appears, so that builds
+// in namespaces the build API creates on first use find their pipeline
+func mapTenantNamespace(_ context.Context, obj client.Object) []reconcile.Request {
+	if _, ok := obj.GetLabels()[automotivev1alpha1.TenantNamespaceLabel]; !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: "config", Namespace: operatorNamespace}}}
+}
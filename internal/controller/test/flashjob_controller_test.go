@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // Dot import is standard for Ginkgo
+	. "github.com/onsi/gomega"    //nolint:revive // Dot import is standard for Gomega
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	automotivev1alpha1 "github.com/centos-automotive-suite/automotive-dev-operator/api/v1alpha1"
+	"github.com/centos-automotive-suite/automotive-dev-operator/internal/controller/flashjob"
+)
+
+var _ = Describe("FlashJob Controller", func() {
+	Context("When flashing a completed build on the fake exporter", func() {
+		ctx := context.Background()
+
+		buildName := types.NamespacedName{Name: "flash-source", Namespace: "default"}
+		jobName := types.NamespacedName{Name: "flash-source-flash", Namespace: "default"}
+		configName := types.NamespacedName{Name: "config", Namespace: "default"}
+
+		BeforeEach(func() {
+			By("creating an OperatorConfig mapping the build target to exporters")
+			config := &automotivev1alpha1.OperatorConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: configName.Name, Namespace: configName.Namespace},
+				Spec: automotivev1alpha1.OperatorConfigSpec{
+					Jumpstarter: &automotivev1alpha1.JumpstarterConfig{
+						TargetMappings: map[string]automotivev1alpha1.JumpstarterTargetMapping{
+							"j784s4evm": {Selector: "board-type=j784s4evm"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, config)).To(Succeed())
+
+			By("creating a completed ImageBuild")
+			build := &automotivev1alpha1.ImageBuild{
+				ObjectMeta: metav1.ObjectMeta{Name: buildName.Name, Namespace: buildName.Namespace},
+				Spec: automotivev1alpha1.ImageBuildSpec{
+					Target:    "j784s4evm",
+					ExportOCI: "quay.io/test/flash-source:latest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, build)).To(Succeed())
+			build.Status.Phase = "Completed"
+			Expect(k8sClient.Status().Update(ctx, build)).To(Succeed())
+
+			By("creating the FlashJob")
+			job := &automotivev1alpha1.FlashJob{
+				ObjectMeta: metav1.ObjectMeta{Name: jobName.Name, Namespace: jobName.Namespace},
+				Spec: automotivev1alpha1.FlashJobSpec{
+					ImageBuildName:   buildName.Name,
+					ExporterSelector: "lab=lab-1",
+				},
+			}
+			Expect(k8sClient.Create(ctx, job)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, &automotivev1alpha1.ImageBuild{
+				ObjectMeta: metav1.ObjectMeta{Name: buildName.Name, Namespace: buildName.Namespace},
+			})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &automotivev1alpha1.OperatorConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: configName.Name, Namespace: configName.Namespace},
+			})).To(Succeed())
+		})
+
+		It("should lease an exporter, flash and release the lease", func() {
+			leaser := flashjob.NewFakeExporterLeaser()
+			controllerReconciler := &flashjob.FlashJobReconciler{
+				Client:          k8sClient,
+				Scheme:          k8sClient.Scheme(),
+				Log:             GinkgoLogr,
+				Leaser:          leaser,
+				ConfigNamespace: "default",
+			}
+			reconcileJob := func() *automotivev1alpha1.FlashJob {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: jobName})
+				Expect(err).NotTo(HaveOccurred())
+				job := &automotivev1alpha1.FlashJob{}
+				Expect(k8sClient.Get(ctx, jobName, job)).To(Succeed())
+				return job
+			}
+
+			By("requesting a lease")
+			job := reconcileJob()
+			Expect(job.Status.Phase).To(Equal(automotivev1alpha1.FlashJobPhaseLeasing))
+			Expect(job.Status.ImageRef).To(Equal("quay.io/test/flash-source:latest"))
+			Expect(job.Status.ExporterSelector).To(Equal("board-type=j784s4evm,lab=lab-1"))
+			Expect(job.Status.FlashCmd).To(Equal("j storage flash 'quay.io/test/flash-source:latest'"))
+			Expect(job.Status.LeaseName).NotTo(BeEmpty())
+
+			By("starting the flash pod once the exporter is leased")
+			job = reconcileJob()
+			Expect(job.Status.Phase).To(Equal(automotivev1alpha1.FlashJobPhaseFlashing))
+			Expect(job.Status.ExporterName).To(Equal("fake-exporter"))
+			pod := &corev1.Pod{}
+			podName := types.NamespacedName{Name: job.Status.PodName, Namespace: job.Status.PodNamespace}
+			Expect(k8sClient.Get(ctx, podName, pod)).To(Succeed())
+			Expect(pod.Labels).To(HaveKeyWithValue(automotivev1alpha1.FlashJobLabel, string(job.UID)))
+
+			By("recording the outcome of the flash pod")
+			pod.Status.Phase = corev1.PodSucceeded
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:  "flash",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+			}}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+			job = reconcileJob()
+			Expect(job.Status.Phase).To(Equal(automotivev1alpha1.FlashJobPhaseSucceeded))
+			Expect(*job.Status.ExitCode).To(BeZero())
+			Expect(job.Status.LeaseReleased).To(BeTrue())
+			Expect(leaser.Released(job.Status.LeaseName)).To(BeTrue())
+
+			By("removing the finalizer on deletion")
+			Expect(k8sClient.Delete(ctx, job)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: jobName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, jobName, job)).NotTo(Succeed())
+		})
+	})
+})